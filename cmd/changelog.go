@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xyue92/gitai/internal/ai"
+	"github.com/xyue92/gitai/internal/changelog"
+	"github.com/xyue92/gitai/internal/config"
+	"github.com/xyue92/gitai/internal/git"
+)
+
+var (
+	changelogFrom      string
+	changelogTo        string
+	changelogVersion   string
+	changelogFormat    string
+	changelogTemplate  string
+	changelogWrite     bool
+	changelogFile      string
+	changelogAll       bool
+	changelogPolish    bool
+	changelogSummarize bool
+)
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog",
+	Short: "Generate a changelog from Conventional Commit history",
+	Long: `Generate a changelog from the Conventional Commits between two revisions.
+
+Commits are grouped by type (and sorted by scope within a section), breaking
+changes are listed first and ticket references are turned into links when
+changelog.ticket_url is configured. The output follows Keep a Changelog by
+default; --format markdown uses one section per commit type, and --template
+renders a custom Go text/template.
+
+With --write the section is prepended to CHANGELOG.md. Running it again for
+the same version replaces that section instead of adding a duplicate.`,
+	Example: `  # Changes since the latest tag
+  gitai changelog
+
+  # Release notes for a version, written to CHANGELOG.md
+  gitai changelog --from v1.1.0 --to v1.2.0 --version 1.2.0 --write
+
+  # Let the model polish the entries and summarize each section
+  gitai changelog --polish --summarize`,
+	RunE: runChangelog,
+}
+
+func init() {
+	rootCmd.AddCommand(changelogCmd)
+
+	changelogCmd.Flags().StringVar(&changelogFrom, "from", "", "Start revision, exclusive (default: latest tag)")
+	changelogCmd.Flags().StringVar(&changelogTo, "to", "HEAD", "End revision")
+	changelogCmd.Flags().StringVar(&changelogVersion, "version", "", "Version heading (default: Unreleased)")
+	changelogCmd.Flags().StringVarP(&changelogFormat, "format", "f", "", "Output format: keepachangelog or markdown")
+	changelogCmd.Flags().StringVar(&changelogTemplate, "template", "", "Custom text/template file")
+	changelogCmd.Flags().BoolVarP(&changelogWrite, "write", "w", false, "Prepend to the changelog file instead of printing")
+	changelogCmd.Flags().StringVar(&changelogFile, "file", "", "Changelog file for --write (default: CHANGELOG.md)")
+	changelogCmd.Flags().BoolVarP(&changelogAll, "all", "a", false, "Include every commit type and non-conventional commits")
+	changelogCmd.Flags().BoolVar(&changelogPolish, "polish", false, "Ask the model to polish the entries")
+	changelogCmd.Flags().BoolVar(&changelogSummarize, "summarize", false, "Ask the model to summarize each section")
+	changelogCmd.Flags().StringVarP(&langFlag, "language", "l", "", "Language for polished text")
+	changelogCmd.Flags().StringVarP(&modelFlag, "model", "m", "", "Ollama model to use")
+}
+
+func runChangelog(cmd *cobra.Command, args []string) error {
+	if !git.IsGitRepository() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	cfg, err := loadConfig(cmd, "model", "language")
+	if err != nil {
+		return err
+	}
+
+	// Select the commits
+	from := changelogFrom
+	if from == "" {
+		from = git.LatestTag(changelogTo)
+	}
+	revRange := changelogTo
+	if from != "" {
+		revRange = from + ".." + changelogTo
+	}
+
+	commits, err := git.LogCommits(revRange, "--no-merges")
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf("no commits found in %s", revRange)
+	}
+
+	// Released versions are dated by their last commit
+	var date time.Time
+	if changelogVersion != "" {
+		date, _ = git.CommitDate(changelogTo)
+	}
+
+	format := changelogFormat
+	if format == "" {
+		format = cfg.Changelog.Format
+	}
+	if format != "" && format != changelog.FormatKeepAChangelog && format != changelog.FormatMarkdown {
+		return fmt.Errorf("unknown changelog format %q (expected keepachangelog or markdown)", format)
+	}
+
+	release := changelog.Build(commits, changelog.Options{
+		Version:       changelogVersion,
+		Date:          date,
+		Format:        format,
+		Sections:      cfg.Changelog.Sections,
+		IncludeAll:    changelogAll,
+		TicketPattern: cfg.TicketPattern,
+		TicketPrefix:  cfg.TicketPrefix,
+		TicketURL:     cfg.Changelog.TicketURL,
+	})
+	if len(release.Sections) == 0 && len(release.Breaking) == 0 {
+		return fmt.Errorf("no changelog entries in %s\nUse --all to include every commit type", revRange)
+	}
+
+	if changelogPolish || changelogSummarize {
+		if err := polishRelease(cfg, release); err != nil {
+			return err
+		}
+	}
+
+	templateText := ""
+	templatePath := changelogTemplate
+	if templatePath == "" {
+		templatePath = cfg.Changelog.Template
+	}
+	if templatePath != "" {
+		data, err := os.ReadFile(templatePath)
+		if err != nil {
+			return fmt.Errorf("failed to read changelog template: %w", err)
+		}
+		templateText = string(data)
+	}
+
+	rendered, err := changelog.Render(release, format, templateText)
+	if err != nil {
+		return err
+	}
+
+	if !changelogWrite {
+		fmt.Print(rendered)
+		return nil
+	}
+
+	path := changelogFile
+	if path == "" {
+		path = cfg.Changelog.File
+	}
+	if path == "" {
+		path = "CHANGELOG.md"
+	}
+	if err := changelog.Prepend(path, rendered, release.Version); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Updated %s with %s (%d commits)\n", path, release.Version, len(commits))
+	return nil
+}
+
+// polishRelease asks the model to polish and/or summarize every section.
+// Sections the model answers badly keep their original text.
+func polishRelease(cfg *config.Config, release *changelog.Release) error {
+	client, err := newAIClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	for i := range release.Sections {
+		section := &release.Sections[i]
+		fmt.Fprintf(os.Stderr, "🤖 Polishing %s...\n", section.Title)
+
+		entries := make([]string, len(section.Entries))
+		for j, entry := range section.Entries {
+			entries[j] = entry.Description
+		}
+		builder := &ai.ChangelogPromptBuilder{Title: section.Title, Entries: entries, Language: cfg.Language}
+
+		if changelogPolish {
+			response, err := client.Generate(builder.Build())
+			if err != nil {
+				return fmt.Errorf("failed to polish changelog: %w", err)
+			}
+			if polished, err := ai.ParsePolishedEntries(response, len(entries)); err == nil {
+				for j := range section.Entries {
+					section.Entries[j].Description = polished[j]
+				}
+			} else {
+				fmt.Fprintf(os.Stderr, "⚠️  Keeping original %s entries: %v\n", section.Title, err)
+			}
+		}
+
+		if changelogSummarize {
+			builder.Summarize = true
+			response, err := client.Generate(builder.Build())
+			if err != nil {
+				return fmt.Errorf("failed to summarize changelog: %w", err)
+			}
+			section.Summary = cleanCommitMessage(response)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/xyue92/gitai/internal/ai"
+	"github.com/xyue92/gitai/internal/config"
+	"github.com/xyue92/gitai/internal/git"
+	"github.com/xyue92/gitai/internal/precheck"
+	"github.com/xyue92/gitai/internal/ui"
+)
+
+// checkAI asks the model for a review after the rules
+var checkAI bool
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check staged changes before committing",
+	Long: `Check the staged changes for problems that should not be committed:
+
+  debug     debug prints and breakpoints (console.log, pdb.set_trace, ...)
+  todo      TODO, FIXME, XXX and HACK comments added by the change
+  binary    binary files larger than precheck.max_binary_kb (default 1024)
+  conflict  merge conflict markers
+  secret    private keys, access tokens and hard-coded passwords
+
+Each rule is set to off, warn or block in precheck.rules. Blocking findings
+make the command fail, which stops the commit when it runs from the
+pre-commit hook (gitai hooks install --all). By default conflicts and secrets
+block and the other rules warn.
+
+The rules only read the staged diff and run in well under a second. With
+--ai (or precheck.ai) the model reviews the changes afterwards; its findings
+use the level of the matching rule, and bugs or missing tests the level of the
+review rule (warn by default).`,
+	Example: `  # Check what is about to be committed
+  gitai check
+
+  # Add a model review after the rules
+  gitai check --ai
+
+  # Machine-readable report
+  gitai check --json`,
+	Args: cobra.NoArgs,
+	RunE: runCheck,
+	// Blocking findings are not usage errors, and the hook output stays short
+	SilenceUsage: true,
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+
+	checkCmd.Flags().BoolVar(&checkAI, "ai", false, "Ask the model for a review after the rules")
+	checkCmd.Flags().BoolVar(&jsonFlag, "json", false, "Print the report as JSON")
+	checkCmd.Flags().StringVarP(&modelFlag, "model", "m", "", "Ollama model to use")
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	display := ui.NewDisplay()
+
+	if !git.IsGitRepository() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	cfg, err := loadConfig(cmd, "model")
+	if err != nil {
+		return err
+	}
+
+	opts := precheck.Options{Levels: cfg.Precheck.Rules, MaxBinarySize: cfg.Precheck.MaxBinaryKB * 1024, Exclude: cfg.Precheck.Exclude}
+	if err := opts.Validate(); err != nil {
+		return fmt.Errorf("invalid precheck configuration: %w", err)
+	}
+
+	src := git.StagedSource()
+	diff, err := src.Diff()
+	if err != nil {
+		return err
+	}
+	binaries, err := stagedBinaries(src)
+	if err != nil {
+		return err
+	}
+
+	report := precheck.Check(precheck.AddedLines(git.ParsePatch(diff)), binaries, opts)
+
+	if checkAI || cfg.Precheck.WantAI() {
+		if !jsonFlag {
+			display.ShowInfo("🤖 Reviewing the staged changes...")
+		}
+		findings, err := reviewFindings(cfg, src, diff, report, opts)
+		if err != nil && !jsonFlag {
+			display.ShowWarning(fmt.Sprintf("Skipping the model review: %v", err))
+		}
+		report.Findings = append(report.Findings, findings...)
+	}
+
+	if jsonFlag {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else if len(report.Findings) == 0 {
+		display.ShowSuccess("No problems found in the staged changes")
+	} else {
+		display.ShowFindings(report.Findings)
+	}
+
+	if report.Blocked() {
+		return fmt.Errorf("%d blocking problem(s) in the staged changes\nFix them, set the rule to warn in precheck.rules, or skip the check once:\n  $ git commit --no-verify",
+			report.Count(precheck.LevelBlock))
+	}
+	if !jsonFlag && len(report.Findings) > 0 {
+		display.ShowWarning(fmt.Sprintf("%d warning(s), nothing blocks the commit", report.Count(precheck.LevelWarn)))
+	}
+	return nil
+}
+
+// stagedBinaries returns the staged binary files with their sizes; deleted
+// files are left out
+func stagedBinaries(src git.DiffSource) ([]precheck.BinaryFile, error) {
+	changes, err := src.FilesWithStats()
+	if err != nil {
+		return nil, err
+	}
+
+	var binaries []precheck.BinaryFile
+	for _, change := range changes {
+		if change.Additions != "-" {
+			continue
+		}
+		if size, err := git.StagedFileSize(change.File); err == nil {
+			binaries = append(binaries, precheck.BinaryFile{Path: change.File, Size: size})
+		}
+	}
+	return binaries, nil
+}
+
+// reviewFindings asks the model to review the staged changes and returns its
+// issues as findings; only problems the rules also found in report can block
+func reviewFindings(cfg *config.Config, src git.DiffSource, diff string, report precheck.Report, opts precheck.Options) ([]precheck.Finding, error) {
+	stats, _ := src.Stats()
+	diff, err := filterDiff(cfg, diff)
+	if err != nil {
+		return nil, err
+	}
+	budgeted, analysis := budgetDiff(cfg, diff)
+	builder := &ai.ReviewPromptBuilder{
+		DiffStats:    stats,
+		Analysis:     analysis,
+		Diff:         budgeted,
+		Language:     cfg.Language,
+		CustomPrompt: cfg.CustomPrompt,
+	}
+
+	client, err := newAIClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Generate(builder.Build())
+	if err != nil {
+		return nil, err
+	}
+	review, err := ai.ParseReview(response)
+	if err != nil {
+		return nil, err
+	}
+	return precheck.ReviewFindings(review, report, opts), nil
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/xyue92/gitai/internal/ai"
+	"github.com/xyue92/gitai/internal/config"
+	"github.com/xyue92/gitai/internal/redact"
+)
+
+// showRedactions lists what was masked in prompts before they are sent
+var showRedactions bool
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&showRedactions, "show-redactions", false, "Show which secrets were masked before prompts are sent to the model")
+}
+
+// newAIClient creates the model client for cfg. Unless redact.disabled is
+// set, secrets and personal data in prompts are replaced with placeholders
+// before they leave the process.
+func newAIClient(cfg *config.Config) (*ai.OllamaClient, error) {
+	client := ai.NewOllamaClient(cfg.Model)
+	if !cfg.Redact.IsEnabled() {
+		return client, nil
+	}
+
+	redactor, err := redact.New(cfg.Redact.Allow)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redact configuration: %w", err)
+	}
+	client.Redactor = redactor
+
+	noticed := false
+	client.OnRedact = func(redactions []redact.Redaction) {
+		if showRedactions {
+			fmt.Fprintln(os.Stderr, "🔒 Masked before sending to the model:")
+			for _, r := range redactions {
+				fmt.Fprintf(os.Stderr, "   %s: %s (%d×)\n", r.Label, strings.Join(r.Placeholders, ", "), r.Count)
+			}
+			return
+		}
+		// Hooks capture stderr together with --quiet output
+		if !noticed && !quietFlag {
+			total := 0
+			for _, r := range redactions {
+				total += r.Count
+			}
+			fmt.Fprintf(os.Stderr, "🔒 Masked %d sensitive value(s) in the prompt (--show-redactions for details)\n", total)
+			noticed = true
+		}
+	}
+	return client, nil
+}
+
+// generateChecked asks the model for an output about a change and checks it
+// with ai.CheckOutput, which flags answers that do not mention the change the
+// way a model hijacked by instructions in the diff answers. A flagged answer
+// is asked for once more, as messageSession.generate does; build receives the
+// attempt number to vary the prompt. Honest answers can be flagged too, so
+// when the second answer fails the check it is returned with a warning on
+// stderr rather than an error. With stream, answers are printed as they arrive.
+func generateChecked(client *ai.OllamaClient, evidence ai.Evidence, stream bool, build func(attempt int) string) (string, error) {
+	response, err := generate(client, build(0), stream)
+	if err != nil || ai.CheckOutput(response, evidence) == nil {
+		return response, err
+	}
+
+	if response, err = generate(client, build(1), stream); err != nil {
+		return "", err
+	}
+	if err := ai.CheckOutput(response, evidence); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Review this output carefully: %v, the changes may contain text aimed at the model\n", err)
+	}
+	return response, nil
+}
+
+// generate sends prompt once, printing the answer as it arrives with stream
+func generate(client *ai.OllamaClient, prompt string, stream bool) (string, error) {
+	if !stream {
+		return client.Generate(prompt)
+	}
+
+	fmt.Print("\n")
+	response, err := client.GenerateStream(prompt, func(chunk string) {
+		fmt.Print(chunk)
+	})
+	fmt.Print("\n\n")
+	return response, err
+}
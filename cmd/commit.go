@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/xyue92/gitai/internal/ai"
@@ -22,13 +21,23 @@ var (
 	subjectLenFlag  string
 	streamFlag      bool
 	promptScopeFlag bool
+	amendFlag       bool
 )
 
 var commitCmd = &cobra.Command{
-	Use:   "commit",
+	Use:   "commit [flags] [-- <git commit args>...]",
 	Short: "Generate and commit with AI",
-	Long:  "Analyze git diff and generate commit message using local Ollama AI",
-	RunE:  runCommit,
+	Long: `Analyze git diff and generate commit message using local Ollama AI.
+
+Arguments after -- are passed to 'git commit' unchanged, so signing,
+sign-off and authorship options work as usual. Git hooks always run
+unless --no-verify is passed through.`,
+	Example: `  # Sign off and GPG sign the commit
+  gitai commit -- --signoff -S
+
+  # Commit on behalf of someone else, skipping hooks
+  gitai commit -- --author="Jane Doe <jane@example.com>" --no-verify`,
+	RunE: runCommit,
 }
 
 func init() {
@@ -43,6 +52,7 @@ func init() {
 	commitCmd.Flags().StringVarP(&subjectLenFlag, "subject-length", "n", "", "Subject length (short/normal)")
 	commitCmd.Flags().BoolVarP(&streamFlag, "stream", "S", false, "Enable streaming output")
 	commitCmd.Flags().BoolVarP(&promptScopeFlag, "prompt-scope", "p", false, "Prompt for scope selection")
+	commitCmd.Flags().BoolVar(&amendFlag, "amend", false, "Regenerate the message of HEAD and amend it (includes staged changes)")
 }
 
 func runCommit(cmd *cobra.Command, args []string) error {
@@ -60,34 +70,48 @@ func runCommit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not a git repository\nInitialize git first:\n  $ git init")
 	}
 
-	// Load configuration
-	cfg, err := config.LoadConfig()
+	// Collect git commit arguments passed after --
+	commitOpts, err := parseCommitPassthrough(cmd, args)
 	if err != nil {
 		return err
 	}
+	commitOpts.Amend = amendFlag
 
-	// Override config with flags
-	if modelFlag != "" {
-		cfg.Model = modelFlag
-	}
-	if langFlag != "" {
-		cfg.Language = langFlag
-	}
-	if subjectLenFlag != "" {
-		cfg.SubjectLength = subjectLenFlag
+	// Load configuration
+	cfg, err := loadConfig(cmd, "model", "language", "subject-length", "prompt-scope")
+	if err != nil {
+		return err
 	}
-	if promptScopeFlag {
-		cfg.PromptScope = true
+
+	// When amending, describe HEAD plus anything staged
+	src := git.StagedSource()
+	previousMessage := ""
+	if amendFlag {
+		src = git.AmendSource()
+		previousMessage, err = git.GetCommitMessage("HEAD")
+		if err != nil {
+			return fmt.Errorf("nothing to amend: %w", err)
+		}
 	}
 
-	// Get staged changes
-	diff, err := git.GetStagedDiff()
+	// Apply the configuration rules for this branch and these files
+	ruleCtx := ruleContext(src)
+	applied, err := applyRules(display, cfg, ruleCtx)
 	if err != nil {
 		return err
 	}
 
+	// Get changes (an empty commit is allowed with --allow-empty)
+	diff, err := promptDiff(cfg, src)
+	if err != nil {
+		if !git.HasCommitArg(commitOpts.Args, "--allow-empty") {
+			return err
+		}
+		diff = "(no changes - empty commit)"
+	}
+
 	// Get changed files with stats
-	fileChanges, err := git.GetChangedFilesWithStats()
+	fileChanges, err := src.FilesWithStats()
 	if err != nil {
 		return fmt.Errorf("failed to get file changes: %w", err)
 	}
@@ -104,9 +128,10 @@ func runCommit(cmd *cobra.Command, args []string) error {
 
 	// Create selector for interactive prompts
 	selector := ui.NewCommitSelector(cfg)
+	selector.DiffStats, _ = src.Stats()
 
 	// Warn if multiple commit types detected
-	if len(typeHints) > 1 {
+	if len(typeHints) > 1 && !amendFlag {
 		display.ShowWarning("⚠️  Multiple commit types detected in staged files:")
 		for _, hint := range typeHints {
 			display.ShowInfo(fmt.Sprintf("  • %s: %d file(s)", hint.Type, len(hint.Files)))
@@ -116,19 +141,35 @@ func runCommit(cmd *cobra.Command, args []string) error {
 		}
 		fmt.Println()
 		display.ShowInfo("💡 Best practice: Split into separate commits for better history")
-		display.ShowInfo("   You can unstage files with: git restore --staged <file>")
 		fmt.Println()
 
-		// Ask user if they want to continue
-		shouldContinue, err := selector.Confirm("Do you want to continue with mixed commit types?")
-		if err != nil || !shouldContinue {
-			return fmt.Errorf("commit cancelled - please split your changes into separate commits")
+		// Offer to split the staged changes into one commit per group
+		patch, err := git.GetStagedPatch()
+		if err != nil {
+			return err
+		}
+		workflow := newSplitWorkflow(display, selector, cfg, git.ParsePatch(patch), commitOpts)
+		workflow.groups = git.GroupByTypeHints(workflow.patch)
+
+		split, err := workflow.adjust()
+		if err != nil {
+			return err
+		}
+		if split {
+			return workflow.commit(src)
 		}
 		fmt.Println()
 	}
 
-	// Select commit type
+	// Select commit type (a rule may set it; when amending, keep the existing
+	// type if it is known)
 	commitType := typeFlag
+	if commitType == "" {
+		commitType = config.RuleType(applied)
+	}
+	if commitType == "" && previousMessage != "" {
+		commitType = previousCommitType(cfg, previousMessage)
+	}
 	if commitType == "" {
 		commitType, err = selector.SelectType()
 		if err != nil {
@@ -136,9 +177,15 @@ func runCommit(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Rules for the commit type
+	ruleCtx.Type = commitType
+	if _, err := applyRules(display, cfg, ruleCtx); err != nil {
+		return err
+	}
+
 	// Select scope - only prompt if explicitly requested via flag or config
 	scope := scopeFlag
-	if scopeFlag == "" && cfg.PromptScope {
+	if scopeFlag == "" && cfg.WantPromptScope() {
 		scope, err = selector.SelectScope()
 		if err != nil {
 			return fmt.Errorf("scope selection cancelled")
@@ -146,10 +193,119 @@ func runCommit(cmd *cobra.Command, args []string) error {
 	}
 
 	// Handle ticket number
+	ticket, err := resolveTicket(display, selector, cfg, src)
+	if err != nil {
+		return err
+	}
+
+	// Get project context
+	display.ShowGenerating()
+	ctx, err := git.GetProjectContextFor(src)
+	if err != nil {
+		// Context gathering is best-effort, continue without it
+		ctx = git.ProjectContext{}
+	}
+
+	// Build prompt
+	promptBuilder := newPromptBuilder(cfg, commitType, scope, diff, ctx)
+	promptBuilder.TicketNumber = ticket
+	promptBuilder.PreviousMessage = previousMessage
+
+	// Generate and review the commit message
+	session, err := newMessageSession(display, selector, cfg, promptBuilder)
+	if err != nil {
+		return err
+	}
+	finalMessage, err := session.run()
+	if err != nil {
+		return err
+	}
+
+	// Dry run mode - don't commit, and don't ask to amend either
+	if dryRun {
+		fmt.Println()
+		if amendFlag {
+			display.ShowMessageComparison(previousMessage, finalMessage)
+		}
+		display.ShowInfo("Would commit with message:")
+		display.ShowCommitMessage(finalMessage)
+		display.ShowInfo("Would run: git " + strings.Join(git.CommitArgs("<message-file>", commitOpts), " "))
+		return nil
+	}
+
+	// When amending, show what changes and confirm before rewriting HEAD
+	if amendFlag {
+		display.ShowMessageComparison(previousMessage, finalMessage)
+		apply, err := selector.Confirm("Amend HEAD with the new message?")
+		if err != nil || !apply {
+			return fmt.Errorf("amend cancelled by user")
+		}
+	}
+
+	// Let the user know when git will ask for a signing passphrase
+	showSigningInfo(display, commitOpts)
+
+	// Perform the commit
+	if err := git.CommitWithOptions(finalMessage, commitOpts); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+
+	// Show success
+	fmt.Println()
+	display.ShowCommitSuccess(finalMessage, files)
+
+	return nil
+}
+
+// showSigningInfo tells the user when git will ask for a signing passphrase
+func showSigningInfo(display *ui.Display, opts git.CommitOptions) {
+	if signing := git.GetSigningInfo(opts.Args); signing.Enabled {
+		if signing.Key != "" {
+			display.ShowInfo(fmt.Sprintf("🔏 Signing commit with key %s", signing.Key))
+		} else {
+			display.ShowInfo("🔏 Signing commit with default key")
+		}
+	}
+}
+
+// ruleContext returns what configuration rules are matched against: the
+// current branch and the files of src
+func ruleContext(src git.DiffSource) config.RuleContext {
+	ctx := config.RuleContext{}
+	if src.IsGitBacked() {
+		ctx.Branch, _ = git.CurrentBranch()
+	}
+	ctx.Files, _ = src.ChangedFiles()
+	return ctx
+}
+
+// applyRules applies the configuration rules that match ctx and explains
+// them. Once the commit type is known, only the rules for types are shown;
+// the others were shown before.
+func applyRules(display *ui.Display, cfg *config.Config, ctx config.RuleContext) ([]config.Rule, error) {
+	applied, err := cfg.ApplyRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if quietFlag {
+		return applied, nil
+	}
+	for _, rule := range applied {
+		if ctx.Type != "" && len(rule.When.Types) == 0 {
+			continue
+		}
+		display.ShowInfo(fmt.Sprintf("📐 Rule %q applies: %s", rule.String(), rule.Changes()))
+	}
+	return applied, nil
+}
+
+// resolveTicket returns the ticket number from the flag, the branch name or a prompt
+func resolveTicket(display *ui.Display, selector *ui.CommitSelector, cfg *config.Config, src git.DiffSource) (string, error) {
+	var err error
 	ticket := ticketFlag
-	if ticket == "" && cfg.RequireTicket {
+	if ticket == "" && cfg.WantTicket() {
 		// Try to extract from branch name first
-		ctx, _ := git.GetProjectContext()
+		ctx, _ := git.GetProjectContextFor(src)
 		autoTicket := git.ExtractTicketFromBranch(ctx.BranchName, cfg.TicketPattern)
 
 		if autoTicket != "" {
@@ -165,12 +321,12 @@ func runCommit(cmd *cobra.Command, args []string) error {
 		if ticket == "" {
 			ticket, err = selector.PromptTicket(cfg.TicketPrefix)
 			if err != nil {
-				return fmt.Errorf("ticket number required but not provided")
+				return "", fmt.Errorf("ticket number required but not provided")
 			}
 		}
 	} else if ticket == "" && cfg.TicketPrefix != "" {
 		// Optional ticket with prefix - try to extract from branch
-		ctx, _ := git.GetProjectContext()
+		ctx, _ := git.GetProjectContextFor(src)
 		ticket = git.ExtractTicketFromBranch(ctx.BranchName, cfg.TicketPattern)
 	}
 
@@ -179,16 +335,17 @@ func runCommit(cmd *cobra.Command, args []string) error {
 		ticket = git.FormatTicketNumber(ticket, cfg.TicketPrefix)
 	}
 
-	// Get project context
-	display.ShowGenerating()
-	ctx, err := git.GetProjectContext()
-	if err != nil {
-		// Context gathering is best-effort, continue without it
-		ctx = git.ProjectContext{}
+	return ticket, nil
+}
+
+// newPromptBuilder creates a prompt builder from configuration and project context
+func newPromptBuilder(cfg *config.Config, commitType, scope, diff string, ctx git.ProjectContext) *ai.PromptBuilder {
+	// An ignored README must not reach the model as project description
+	if matcher, err := loadIgnore(cfg); err != nil || matcher.Match("README.md") {
+		ctx.ReadmeSnippet = ""
 	}
 
-	// Build prompt
-	promptBuilder := &ai.PromptBuilder{
+	return &ai.PromptBuilder{
 		CommitType: commitType,
 		Scope:      scope,
 		Diff:       diff,
@@ -201,164 +358,42 @@ func runCommit(cmd *cobra.Command, args []string) error {
 			DiffStats:     ctx.DiffStats,
 		},
 		Language:       cfg.Language,
-		DetailedCommit: cfg.DetailedCommit,
+		DetailedCommit: cfg.WantDetailedCommit(),
 		CustomPrompt:   cfg.CustomPrompt,
-		TicketNumber:   ticket,
 		SubjectLength:  cfg.SubjectLength,
 	}
+}
 
-	prompt := promptBuilder.Build()
-
-	// Generate commit message
-	client := ai.NewOllamaClient(cfg.Model)
-
-	var finalMessage string
-	maxRetries := 3
-
-	for i := 0; i < maxRetries; i++ {
-		startTime := time.Now()
-		var message string
-		var err error
-
-		if streamFlag {
-			// Use streaming mode
-			fmt.Print("\n")
-			message, err = client.GenerateStream(prompt, func(chunk string) {
-				fmt.Print(chunk)
-			})
-			fmt.Print("\n\n")
-		} else {
-			// Use non-streaming mode
-			message, err = client.Generate(prompt)
-		}
-
-		elapsed := time.Since(startTime)
-
-		if err != nil {
-			return fmt.Errorf("failed to generate commit message: %w", err)
-		}
-
-		// Clean up the message
-		message = cleanCommitMessage(message)
-
-		// Display time taken
-		display.ShowInfo(fmt.Sprintf("[time elapsed: %.2fs]", elapsed.Seconds()))
-		fmt.Println()
-
-		// Display generated message (only in non-streaming mode, as streaming already showed it)
-		if !streamFlag {
-			display.ShowCommitMessage(message)
-		} else {
-			// In streaming mode, show the final cleaned message in a box
-			display.ShowCommitMessage(message)
-		}
+// previousCommitType returns the Conventional Commit type of an existing
+// message if it is one of the configured types
+func previousCommitType(cfg *config.Config, message string) string {
+	subject := strings.SplitN(message, "\n", 2)[0]
+	parsed, ok := git.ParseConventionalSubject(subject)
+	if !ok || cfg.GetTypeByName(parsed.Type) == nil {
+		return ""
+	}
+	return parsed.Type
+}
 
-		// Ask user what to do
-		action, err := selector.ConfirmAction(message)
-		if err != nil {
-			return fmt.Errorf("action selection cancelled")
+// parseCommitPassthrough returns the git commit options given after --
+func parseCommitPassthrough(cmd *cobra.Command, args []string) (git.CommitOptions, error) {
+	dash := cmd.ArgsLenAtDash()
+	if dash == -1 {
+		if len(args) > 0 {
+			return git.CommitOptions{}, fmt.Errorf("unexpected arguments: %s\nPass git commit flags after --, e.g.:\n  $ gitai commit -- --signoff", strings.Join(args, " "))
 		}
-
-		switch action {
-		case ui.ActionUse:
-			finalMessage = message
-			goto commit
-		case ui.ActionRegenerate:
-			display.ShowGenerating()
-			// Increment regenerate count and rebuild prompt for variation
-			promptBuilder.RegenerateCount++
-			prompt = promptBuilder.Build()
-			continue
-		case ui.ActionEdit:
-			// Handle edit mode with post-edit options
-			editedMessage := message
-			for {
-				edited, err := selector.EditMessage(editedMessage)
-				if err != nil {
-					return fmt.Errorf("edit cancelled")
-				}
-				editedMessage = edited
-
-				// Display the edited message
-				display.ShowCommitMessage(editedMessage)
-
-				// Ask what to do after editing
-				postEditAction, err := selector.ConfirmActionAfterEdit(editedMessage)
-				if err != nil {
-					return fmt.Errorf("action selection cancelled")
-				}
-
-				switch postEditAction {
-				case ui.ActionUse:
-					finalMessage = editedMessage
-					goto commit
-				case ui.ActionRegenerateFromEdit:
-					// Use the edited message as a prompt to regenerate
-					promptBuilder.CustomPrompt = fmt.Sprintf(`USER'S COMMIT MESSAGE DRAFT:
-The user has provided the following commit message draft (which may be in mixed languages or incomplete).
-Your task is to understand their intent and generate a proper commit message based on it.
-
-User's draft:
-%s
-
-INSTRUCTIONS:
-1. Understand what the user is trying to communicate (even if it's in Chinese or mixed languages)
-2. Generate a professional commit message that captures the user's intent
-3. Keep the commit type (%s) unless the user clearly intended a different type
-4. Follow the Conventional Commits format
-5. Use the target language specified in the configuration
-
-If the user wrote:
-- "fix: 修复登录bug" → understand they mean "fix login bug" → output proper message in target language
-- "feat: add new 功能" → understand they mean "add new feature" → output proper message in target language
-- Mixed language content → understand the meaning → output clean message in target language`, editedMessage, commitType)
-
-					// Debug: Print before building prompt
-					display.ShowInfo(fmt.Sprintf("[DEBUG] Custom Prompt Set: %d chars", len(promptBuilder.CustomPrompt)))
-					display.ShowInfo(fmt.Sprintf("[DEBUG] User's draft: %s", editedMessage))
-
-					prompt = promptBuilder.Build()
-
-					display.ShowGenerating()
-					// Break out of edit loop and regenerate
-					goto regenerate
-				case ui.ActionEdit:
-					// Continue editing loop
-					continue
-				case ui.ActionCancel:
-					return fmt.Errorf("commit cancelled by user")
-				}
-			}
-		case ui.ActionCancel:
-			return fmt.Errorf("commit cancelled by user")
-		}
-
-	regenerate:
-		// Continue the outer loop to regenerate
-		// Note: display.ShowGenerating() is already called in each action branch
+		return git.CommitOptions{}, nil
 	}
-
-	return fmt.Errorf("max retries reached")
-
-commit:
-	// Dry run mode - don't commit
-	if dryRun {
-		fmt.Println()
-		display.ShowInfo("Would commit with message:")
-		display.ShowCommitMessage(finalMessage)
-		return nil
+	if dash > 0 {
+		return git.CommitOptions{}, fmt.Errorf("unexpected arguments: %s", strings.Join(args[:dash], " "))
 	}
 
-	// Perform the commit
-	if err := git.CommitWithMessage(finalMessage); err != nil {
-		return fmt.Errorf("failed to commit: %w", err)
+	opts := git.CommitOptions{Args: args[dash:]}
+	if err := git.ValidateCommitArgs(opts.Args); err != nil {
+		return git.CommitOptions{}, err
 	}
 
-	// Show success
-	fmt.Println()
-	display.ShowCommitSuccess(finalMessage, files)
-
-	return nil
+	return opts, nil
 }
 
 // cleanCommitMessage removes common prefixes and cleans up the message
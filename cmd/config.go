@@ -2,28 +2,87 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/xyue92/gitai/internal/config"
+	"github.com/xyue92/gitai/internal/precheck"
+	"github.com/xyue92/gitai/internal/ui"
+	"gopkg.in/yaml.v3"
 )
 
 var (
-	configInit bool
-	configShow bool
+	configInit   bool
+	configShow   bool
+	configOrigin bool
 )
 
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Manage gitai configuration",
-	Long:  "View or initialize gitai configuration file",
-	RunE:  runConfig,
+	Long: `View or initialize gitai configuration file.
+
+Settings are merged from these layers, later ones winning:
+  default  built-in defaults
+  user     ~/.gitcommit.yaml, then $XDG_CONFIG_HOME/gitai/config.yaml
+  repo     .gitcommit.yaml at the top of the repository
+  dir      .gitcommit.yaml in directories down to the current one
+  env      GITAI_* environment variables (e.g. GITAI_MODEL)
+  flag     -c key=value on any command, then flags such as --model
+
+Rules in the files apply on top of the file layers, below env. Every key has
+an environment variable: GITAI_ and the key in upper case with dots replaced
+by underscores, e.g. GITAI_DIFF_ANALYSIS_CONTEXT_LINES.
+
+Maps merge key by key; lists and single values replace lower layers. A config
+file can build on a built-in preset, another file or a file in another git
+repository with 'extends:'; its own settings override the extended ones.
+Use --show --origin to see which layer set each value, and 'gitai config lint'
+to check the files for mistakes. 'gitai config init --interactive' creates a
+config file, and 'gitai config get/set/unset' read and change single settings.`,
+	RunE: runConfig,
+}
+
+var configLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check the configuration files for mistakes",
+	Long: `Check every config file that applies in the current directory, and the
+GITAI_* environment variables, for unknown keys, values of the wrong type,
+unsupported choices such as subject_length or language, invalid regular
+expressions and duplicate commit types.
+
+Problems are listed with their file and line. The command fails when there are
+any, so it can run in CI.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigLint,
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of .gitcommit.yaml",
+	Long: `Print the JSON Schema of .gitcommit.yaml files.
+
+Editors with YAML language support use it for completion and validation, e.g.
+with this first line in .gitcommit.yaml:
+  # yaml-language-server: $schema=https://raw.githubusercontent.com/xyue92/gitai/main/internal/config/schema.json`,
+	Example: `  # Save the schema next to the config
+  gitai config schema > .gitcommit.schema.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, err := os.Stdout.Write(config.Schema())
+		return err
+	},
 }
 
 func init() {
 	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configLintCmd)
+	configCmd.AddCommand(configSchemaCmd)
 
 	configCmd.Flags().BoolVar(&configInit, "init", false, "Create default config file in current directory")
 	configCmd.Flags().BoolVar(&configShow, "show", false, "Show current configuration")
+	configCmd.Flags().BoolVar(&configOrigin, "origin", false, "With --show, list every setting with the layer that set it")
 }
 
 func runConfig(cmd *cobra.Command, args []string) error {
@@ -31,8 +90,11 @@ func runConfig(cmd *cobra.Command, args []string) error {
 		return initConfig()
 	}
 
+	if configShow && configOrigin {
+		return showConfigOrigins(cmd)
+	}
 	if configShow {
-		return showConfig()
+		return showConfig(cmd)
 	}
 
 	// Default: show help
@@ -53,8 +115,8 @@ func initConfig() error {
 	return nil
 }
 
-func showConfig() error {
-	cfg, err := config.LoadConfig()
+func showConfig(cmd *cobra.Command) error {
+	cfg, err := loadConfig(cmd)
 	if err != nil {
 		return err
 	}
@@ -78,3 +140,180 @@ func showConfig() error {
 
 	return nil
 }
+
+func runConfigLint(cmd *cobra.Command, args []string) error {
+	display := ui.NewDisplay()
+
+	opts, err := configOptions(cmd)
+	if err != nil {
+		return err
+	}
+	files, problems, err := config.Lint(opts)
+	if err != nil {
+		return err
+	}
+
+	// Rule names belong to precheck, which checks them when it runs
+	var notes []string
+	if len(problems) == 0 {
+		resolved, err := config.Resolve(opts)
+		if err != nil {
+			return err
+		}
+		if err := (precheck.Options{Levels: resolved.Config.Precheck.Rules}).Validate(); err != nil {
+			problems = append(problems, config.Problem{Key: "precheck.rules", Message: err.Error()})
+		}
+		notes = resolved.Notes
+	}
+
+	if len(files) == 0 {
+		display.ShowInfo("No config files found, using the defaults")
+	}
+	for _, file := range files {
+		fmt.Printf("  %s\n", file)
+	}
+	fmt.Println()
+
+	for _, note := range notes {
+		display.ShowWarning(note)
+	}
+	if len(problems) == 0 {
+		display.ShowSuccess("Configuration is valid")
+		return nil
+	}
+	for _, p := range problems {
+		fmt.Printf("  ❌ %s\n", p)
+	}
+	fmt.Println()
+	return fmt.Errorf("%d problem(s) in the configuration", len(problems))
+}
+
+// showConfigOrigins lists every setting with its value and the layer that
+// set it
+func showConfigOrigins(cmd *cobra.Command) error {
+	opts, err := configOptions(cmd)
+	if err != nil {
+		return err
+	}
+	resolved, err := config.Resolve(opts)
+	if err != nil {
+		return err
+	}
+	settings, err := resolved.Settings()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Configuration files (lowest precedence first):")
+	if len(resolved.Files) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, file := range resolved.Files {
+		fmt.Printf("  %s\n", file)
+	}
+	fmt.Println()
+
+	// Long values such as the commit types are not aligned with the rest
+	lines := make([]string, len(settings))
+	width := 0
+	for i, s := range settings {
+		value := s.Value
+		if s.Key == "types" {
+			value = typeNames(s.Value)
+		}
+		lines[i] = s.Key + ": " + flowYAML(value)
+		if len(lines[i]) > width && len(lines[i]) <= 60 {
+			width = len(lines[i])
+		}
+	}
+	for i, s := range settings {
+		fmt.Printf("%-*s  # %s\n", width, lines[i], s.Origin)
+	}
+	return nil
+}
+
+// typeNames shortens the commit types to their names
+func typeNames(types interface{}) interface{} {
+	list, ok := types.([]interface{})
+	if !ok {
+		return types
+	}
+	names := make([]interface{}, 0, len(list))
+	for _, t := range list {
+		if m, ok := t.(map[string]interface{}); ok {
+			names = append(names, m["name"])
+		}
+	}
+	return names
+}
+
+// flowYAML formats a value as single-line YAML
+func flowYAML(value interface{}) string {
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return fmt.Sprint(value)
+	}
+	setFlowStyle(&node)
+	data, err := yaml.Marshal(&node)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// setFlowStyle makes lists and maps print on one line
+func setFlowStyle(node *yaml.Node) {
+	if node.Kind == yaml.SequenceNode || node.Kind == yaml.MappingNode {
+		node.Style = yaml.FlowStyle
+	}
+	for _, child := range node.Content {
+		setFlowStyle(child)
+	}
+}
+
+// loadConfig loads the configuration for the working directory, with the
+// overrides of configOptions
+func loadConfig(cmd *cobra.Command, flags ...string) (*config.Config, error) {
+	opts, err := configOptions(cmd, flags...)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := config.Resolve(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Hooks capture stderr together with --quiet output
+	if !quietFlag {
+		for _, note := range resolved.Notes {
+			fmt.Fprintf(os.Stderr, "⚠️  %s\n", note)
+		}
+	}
+	return resolved.Config, nil
+}
+
+// configOptions returns the flag layer: the -c key=value pairs in order, then
+// the given flags of cmd when set. Each flag sets the key of the same name,
+// e.g. --subject-length sets subject_length, so it wins over -c.
+func configOptions(cmd *cobra.Command, flags ...string) (config.Options, error) {
+	var overrides []config.Override
+	for _, arg := range configOverrideFlags {
+		override, err := config.ParseOverride(arg, "-c")
+		if err != nil {
+			return config.Options{}, err
+		}
+		overrides = append(overrides, override)
+	}
+	for _, name := range flags {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || !flag.Changed {
+			continue
+		}
+		overrides = append(overrides, config.Override{
+			Key:    strings.ReplaceAll(name, "-", "_"),
+			Value:  flag.Value.String(),
+			Source: "--" + name,
+		})
+	}
+	return config.Options{Overrides: overrides}, nil
+}
@@ -0,0 +1,439 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xyue92/gitai/internal/ai"
+	"github.com/xyue92/gitai/internal/config"
+	"github.com/xyue92/gitai/internal/git"
+	"github.com/xyue92/gitai/internal/i18n"
+	"github.com/xyue92/gitai/internal/ui"
+)
+
+var (
+	configGlobal      bool
+	configInteractive bool
+)
+
+const (
+	plainTemplate = "{type}{scope}: {message}" // Subject template without emoji
+	otherModel    = "(Other...)"               // Model choice for typing a name
+)
+
+// ticketSystem is a ticket system offered by the wizard
+type ticketSystem struct {
+	Label   string
+	Pattern string // Empty for none or a custom pattern
+}
+
+var ticketSystems = []ticketSystem{
+	{Label: "None"},
+	{Label: "Jira (PROJ-123)"},
+	{Label: "GitHub / GitLab issues (#123)", Pattern: `#\d+`},
+	{Label: "Custom pattern"},
+}
+
+// stylePreset is a commit message style offered by the wizard
+type stylePreset struct {
+	Label    string
+	Settings [][2]string // Keys and values, in the order they are written
+}
+
+var stylePresets = []stylePreset{
+	{
+		Label:    "Conventional with emoji   feat(api): ✨ add login",
+		Settings: [][2]string{{"template", config.DefaultConfig().Template}, {"detailed_commit", "true"}},
+	},
+	{
+		Label:    "Conventional              feat(api): add login",
+		Settings: [][2]string{{"template", plainTemplate}, {"detailed_commit", "true"}},
+	},
+	{
+		Label:    "Compact                   feat(api): add login (short subject, no body)",
+		Settings: [][2]string{{"template", plainTemplate}, {"subject_length", "short"}, {"detailed_commit", "false"}},
+	},
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a config file",
+	Long: `Create .gitcommit.yaml at the top of the repository, or the user config file
+with --global.
+
+Without --interactive the file holds the complete default configuration. With
+--interactive a short wizard asks for the model, language, ticket system,
+scopes and a built-in convention or message style, and only the answers are
+written; everything else keeps its default.`,
+	Example: `  # Answer a few questions
+  gitai config init --interactive
+
+  # Set up the defaults for all repositories
+  gitai config init --interactive --global`,
+	Args: cobra.NoArgs,
+	RunE: runConfigInit,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a configuration value",
+	Long: `Print the effective value of a setting, after merging all layers. With
+--global, print the value of the user config file instead.
+
+Keys are dotted, e.g. diff_analysis.context_lines or changelog.sections.feat.
+The command fails when the key is not set.`,
+	Example: `  gitai config get model
+  gitai config get --global language`,
+	Args:         cobra.ExactArgs(1),
+	RunE:         runConfigGet,
+	SilenceUsage: true,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a value in a config file",
+	Long: `Set a value in .gitcommit.yaml at the top of the repository, or in the user
+config file with --global. Comments and the order of the other settings are
+kept, and the file is checked before it is written.
+
+Lists are given comma-separated or as YAML, e.g. "api,web" or "[api, web]".`,
+	Example: `  gitai config set model llama3
+  gitai config set scopes api,web,docs
+  gitai config set diff_analysis.context_lines 5
+  gitai config set --global language zh`,
+	Args:         cobra.ExactArgs(2),
+	RunE:         runConfigSet,
+	SilenceUsage: true,
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a value from a config file",
+	Long: `Remove a setting from .gitcommit.yaml at the top of the repository, or from
+the user config file with --global, so that lower layers apply again.`,
+	Example:      `  gitai config unset scopes`,
+	Args:         cobra.ExactArgs(1),
+	RunE:         runConfigUnset,
+	SilenceUsage: true,
+}
+
+func init() {
+	configCmd.AddCommand(configInitCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+
+	configInitCmd.Flags().BoolVarP(&configInteractive, "interactive", "i", false, "Ask questions instead of writing all defaults")
+	for _, c := range []*cobra.Command{configInitCmd, configGetCmd, configSetCmd, configUnsetCmd} {
+		c.Flags().BoolVarP(&configGlobal, "global", "g", false, "Use the user config file instead of the repository's")
+	}
+}
+
+// configPath returns the config file edited by the config subcommands
+func configPath() (string, error) {
+	if configGlobal {
+		return config.GlobalConfigPath(config.Options{})
+	}
+	return config.RepoConfigPath(config.Options{})
+}
+
+func runConfigInit(cmd *cobra.Command, args []string) error {
+	display := ui.NewDisplay()
+	selector := ui.NewCommitSelector(config.DefaultConfig())
+
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+
+	if !configInteractive {
+		if _, err := os.Stat(path); err == nil {
+			overwrite, err := selector.Confirm(fmt.Sprintf("%s exists. Overwrite it with the defaults?", path))
+			if err != nil || !overwrite {
+				display.ShowInfo("Cancelled")
+				return nil
+			}
+		}
+		if err := config.DefaultConfig().Save(path); err != nil {
+			return fmt.Errorf("failed to create config file: %w", err)
+		}
+		display.ShowSuccess(fmt.Sprintf("Created default configuration at: %s", path))
+		return nil
+	}
+
+	doc, err := config.OpenDocument(path)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		update, err := selector.Confirm(fmt.Sprintf("%s exists. Update it with your answers (other settings are kept)?", path))
+		if err != nil || !update {
+			display.ShowInfo("Cancelled")
+			return nil
+		}
+	}
+
+	settings, err := runConfigWizard(selector, display)
+	if err != nil {
+		return err
+	}
+	for _, s := range settings {
+		if err := doc.Set(s[0], s[1]); err != nil {
+			return err
+		}
+	}
+	if err := doc.Save(); err != nil {
+		return err
+	}
+
+	data, err := doc.Bytes()
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	display.ShowSuccess(fmt.Sprintf("Saved configuration to: %s", path))
+	fmt.Printf("\n%s", data)
+	return nil
+}
+
+// runConfigWizard asks for the main settings and returns the keys and values
+// to write
+func runConfigWizard(selector *ui.CommitSelector, display *ui.Display) ([][2]string, error) {
+	var settings [][2]string
+	defaults := config.DefaultConfig()
+
+	// Model
+	model, err := askModel(selector, display, defaults.Model)
+	if err != nil {
+		return nil, err
+	}
+	settings = append(settings, [2]string{"model", model})
+
+	// Language
+	codes := config.LanguageCodes()
+	languages := make([]string, len(codes))
+	for i, code := range codes {
+		lang, _ := i18n.GetLanguage(code)
+		languages[i] = fmt.Sprintf("%s - %s (%s)", code, lang.Name, lang.NativeName)
+	}
+	idx, err := selector.SelectItem("Commit message language", languages)
+	if err != nil {
+		return nil, err
+	}
+	settings = append(settings, [2]string{"language", codes[idx]})
+
+	// Ticket system
+	labels := make([]string, len(ticketSystems))
+	for i, system := range ticketSystems {
+		labels[i] = system.Label
+	}
+	idx, err = selector.SelectItem("Ticket system", labels)
+	if err != nil {
+		return nil, err
+	}
+	if idx > 0 {
+		ticket, err := askTicket(selector, idx)
+		if err != nil {
+			return nil, err
+		}
+		settings = append(settings, ticket...)
+	}
+
+	// Scopes, suggested from the directories of the repository
+	var inferred []string
+	if files, err := git.ListFiles(); err == nil {
+		inferred = git.InferScopes(files)
+	}
+	scopes, err := selector.PromptText("Scopes (comma-separated, empty for none)", strings.Join(inferred, ", "), nil)
+	if err != nil {
+		return nil, err
+	}
+	if scopes != "" {
+		settings = append(settings, [2]string{"scopes", scopes})
+	}
+
+	// Convention: a built-in preset, or a style for the default types
+	presets := config.Presets()
+	labels = []string{"None, pick a message style"}
+	for _, name := range presets {
+		labels = append(labels, fmt.Sprintf("%s - %s", name, config.PresetTitle(name)))
+	}
+	idx, err = selector.SelectItem("Team convention", labels)
+	if err != nil {
+		return nil, err
+	}
+	if idx > 0 {
+		// Written first, so that the answers above override the preset
+		return append([][2]string{{config.ExtendsKey, presets[idx-1]}}, settings...), nil
+	}
+
+	// Style
+	labels = make([]string, len(stylePresets))
+	for i, preset := range stylePresets {
+		labels[i] = preset.Label
+	}
+	idx, err = selector.SelectItem("Commit message style", labels)
+	if err != nil {
+		return nil, err
+	}
+	return append(settings, stylePresets[idx].Settings...), nil
+}
+
+// askModel offers the installed Ollama models, or asks for a name when
+// Ollama cannot be reached
+func askModel(selector *ui.CommitSelector, display *ui.Display, def string) (string, error) {
+	models, err := ai.NewOllamaClient(def).ListModels()
+	if err != nil || len(models) == 0 {
+		if err != nil {
+			display.ShowWarning(fmt.Sprintf("Cannot list the installed models: %v", err))
+		}
+		return selector.PromptText("Ollama model", def, requireText)
+	}
+
+	idx, err := selector.SelectItem("Ollama model", append(models, otherModel))
+	if err != nil {
+		return "", err
+	}
+	if idx < len(models) {
+		return models[idx], nil
+	}
+	return selector.PromptText("Ollama model", def, requireText)
+}
+
+// askTicket asks for the details of the ticket system at index idx of
+// ticketSystems
+func askTicket(selector *ui.CommitSelector, idx int) ([][2]string, error) {
+	var settings [][2]string
+	pattern := ticketSystems[idx].Pattern
+
+	switch ticketSystems[idx].Label {
+	case "Jira (PROJ-123)":
+		key, err := selector.PromptText("Jira project key (empty for any project)", "", func(s string) error {
+			if s != "" && !regexp.MustCompile(`^[A-Z][A-Z0-9]+$`).MatchString(s) {
+				return fmt.Errorf("project keys are upper case letters and digits, e.g. PROJ")
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		pattern = `[A-Z][A-Z0-9]+-\d+`
+		if key != "" {
+			pattern = regexp.QuoteMeta(key) + `-\d+`
+			settings = append(settings, [2]string{"ticket_prefix", key})
+		}
+	case "Custom pattern":
+		custom, err := selector.PromptText("Ticket regular expression", "", func(s string) error {
+			if err := requireText(s); err != nil {
+				return err
+			}
+			_, err := regexp.Compile(s)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		pattern = custom
+	}
+
+	required, err := selector.Confirm("Require a ticket in every commit?")
+	if err != nil {
+		return nil, err
+	}
+	settings = append([][2]string{{"ticket_pattern", pattern}}, settings...)
+	return append(settings, [2]string{"require_ticket", fmt.Sprint(required)}), nil
+}
+
+// requireText rejects empty answers
+func requireText(s string) error {
+	if s == "" {
+		return fmt.Errorf("a value is required")
+	}
+	return nil
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	var value interface{}
+	if configGlobal {
+		path, err := configPath()
+		if err != nil {
+			return err
+		}
+		doc, err := config.OpenDocument(path)
+		if err != nil {
+			return err
+		}
+		if value, err = doc.Get(args[0]); err != nil {
+			return err
+		}
+	} else {
+		opts, err := configOptions(cmd)
+		if err != nil {
+			return err
+		}
+		resolved, err := config.Resolve(opts)
+		if err != nil {
+			return err
+		}
+		if value, _, err = resolved.Value(args[0]); err != nil {
+			return err
+		}
+	}
+
+	if value == nil {
+		return fmt.Errorf("%s is not set", args[0])
+	}
+	if s, ok := value.(string); ok {
+		fmt.Println(s)
+	} else {
+		fmt.Println(flowYAML(value))
+	}
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	doc, err := config.OpenDocument(path)
+	if err != nil {
+		return err
+	}
+	if err := doc.Set(args[0], args[1]); err != nil {
+		return err
+	}
+	if err := doc.Save(); err != nil {
+		return err
+	}
+
+	ui.NewDisplay().ShowSuccess(fmt.Sprintf("Set %s in %s", args[0], path))
+	return nil
+}
+
+func runConfigUnset(cmd *cobra.Command, args []string) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	doc, err := config.OpenDocument(path)
+	if err != nil {
+		return err
+	}
+	removed, err := doc.Unset(args[0])
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("%s is not set in %s", args[0], path)
+	}
+	if err := doc.Save(); err != nil {
+		return err
+	}
+
+	ui.NewDisplay().ShowSuccess(fmt.Sprintf("Removed %s from %s", args[0], path))
+	return nil
+}
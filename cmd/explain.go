@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/xyue92/gitai/internal/ai"
+	"github.com/xyue92/gitai/internal/git"
+	"github.com/xyue92/gitai/internal/pr"
+	"github.com/xyue92/gitai/internal/ui"
+)
+
+// jsonFlag prints the result of explain and review as JSON
+var jsonFlag bool
+
+var explainCmd = &cobra.Command{
+	Use:   "explain [<rev>]",
+	Short: "Explain what an existing commit does",
+	Long: `Explain what an existing commit does, what could break and which areas of
+the code base it affects.
+
+The explanation is written from the commit's message and diff, which goes
+through the same analysis and max_diff_length budget as commit messages. It
+also points out messages that do not match the changes.`,
+	Example: `  # Explain the last commit
+  gitai explain
+
+  # Explain a commit from history, as JSON
+  gitai explain a1b2c3d --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runExplain,
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+
+	explainCmd.Flags().BoolVar(&jsonFlag, "json", false, "Print the explanation as JSON")
+	explainCmd.Flags().StringVarP(&langFlag, "language", "l", "", "Explanation language (en/zh)")
+	explainCmd.Flags().StringVarP(&modelFlag, "model", "m", "", "Ollama model to use")
+}
+
+func runExplain(cmd *cobra.Command, args []string) error {
+	display := ui.NewDisplay()
+
+	if !git.IsGitRepository() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	cfg, err := loadConfig(cmd, "model", "language")
+	if err != nil {
+		return err
+	}
+
+	rev := "HEAD"
+	if len(args) == 1 {
+		rev = args[0]
+	}
+	commits, err := git.LogCommits(rev, "-1")
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf("unknown revision: %s", rev)
+	}
+	commit := commits[0]
+
+	src := git.CommitSource(commit.Hash)
+	diff, err := promptDiff(cfg, src)
+	if err != nil {
+		return err
+	}
+	ctx, err := git.GetProjectContextFor(src)
+	if err != nil {
+		ctx = git.ProjectContext{}
+	}
+
+	var areas []string
+	for _, area := range pr.Areas(ctx.ChangedFiles, 2) {
+		areas = append(areas, area.Describe())
+	}
+
+	budgeted, analysis := budgetDiff(cfg, diff)
+	builder := &ai.ExplainPromptBuilder{
+		Message:   commit.Message(),
+		Areas:     areas,
+		DiffStats: ctx.DiffStats,
+		Analysis:  analysis,
+		Diff:      budgeted,
+		Language:  cfg.Language,
+	}
+
+	if !jsonFlag {
+		display.ShowInfo(fmt.Sprintf("Explaining %s %s", commit.ShortHash(), commit.Subject))
+		fmt.Println()
+	}
+
+	client, err := newAIClient(cfg)
+	if err != nil {
+		return err
+	}
+	response, err := client.Generate(builder.Build())
+	if err != nil {
+		return fmt.Errorf("failed to explain commit: %w", err)
+	}
+	explanation, err := ai.ParseExplanation(response)
+	if err != nil {
+		return fmt.Errorf("failed to parse explanation: %w", err)
+	}
+
+	if jsonFlag {
+		data, err := json.MarshalIndent(explanation, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	display.ShowReport([]ui.ReportSection{
+		{Title: "What it does", Text: explanation.Summary},
+		{Title: "Risks", Items: explanation.Risks, Empty: "No notable risks", Alert: true},
+		{Title: "Affected areas", Items: explanation.Areas, Empty: "None reported"},
+	})
+	return nil
+}
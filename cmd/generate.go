@@ -4,21 +4,45 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
-	"github.com/xyue92/gitai/internal/ai"
 	"github.com/xyue92/gitai/internal/config"
 	"github.com/xyue92/gitai/internal/git"
 	"github.com/xyue92/gitai/internal/ui"
 )
 
 var (
-	quietFlag bool
+	quietFlag    bool
+	revFlag      string
+	rangeFlag    string
+	unstagedFlag bool
+	patchFlag    string
+	stdinFlag    bool
+	revertFlag   string
 )
 
 var generateCmd = &cobra.Command{
 	Use:   "generate",
 	Short: "Generate commit message without committing",
-	Long:  "Generate a commit message for staged changes without actually committing",
-	RunE:  runGenerate,
+	Long: `Generate a commit message without actually committing.
+
+By default the staged changes are described. The diff can also come from an
+existing commit, a revision range, the working tree, or a patch file/stdin,
+which makes it possible to describe historical commits, emailed patches
+and CI-supplied diffs.`,
+	Example: `  # Describe staged changes
+  gitai generate
+
+  # Describe an existing commit or a range
+  gitai generate --rev abc1234
+  gitai generate --range main..feature
+
+  # Describe unstaged changes or a patch
+  gitai generate --unstaged
+  gitai generate --patch fix.patch
+  git diff | gitai generate --stdin --quiet --type fix
+
+  # Message for reverting a commit
+  gitai generate --revert abc1234`,
+	RunE: runGenerate,
 }
 
 func init() {
@@ -28,7 +52,38 @@ func init() {
 	generateCmd.Flags().StringVarP(&scopeFlag, "scope", "s", "", "Commit scope (skip selection)")
 	generateCmd.Flags().StringVarP(&langFlag, "language", "l", "", "Message language (en/zh)")
 	generateCmd.Flags().StringVarP(&modelFlag, "model", "m", "", "Ollama model to use")
+	generateCmd.Flags().StringVarP(&ticketFlag, "ticket", "k", "", "Ticket/issue number (e.g., JIRA-123)")
+	generateCmd.Flags().StringVarP(&subjectLenFlag, "subject-length", "n", "", "Subject length (short/normal)")
+	generateCmd.Flags().BoolVarP(&streamFlag, "stream", "S", false, "Enable streaming output")
+	generateCmd.Flags().BoolVarP(&promptScopeFlag, "prompt-scope", "p", false, "Prompt for scope selection")
 	generateCmd.Flags().BoolVarP(&quietFlag, "quiet", "q", false, "Quiet mode - only output the message")
+
+	// Diff source selection
+	generateCmd.Flags().StringVar(&revFlag, "rev", "", "Describe an existing commit")
+	generateCmd.Flags().StringVar(&rangeFlag, "range", "", "Describe the net changes of a range (A..B or A...B)")
+	generateCmd.Flags().BoolVar(&unstagedFlag, "unstaged", false, "Describe unstaged working tree changes")
+	generateCmd.Flags().StringVar(&patchFlag, "patch", "", "Describe a patch file")
+	generateCmd.Flags().BoolVar(&stdinFlag, "stdin", false, "Read the patch from stdin")
+	generateCmd.Flags().StringVar(&revertFlag, "revert", "", "Write the message for reverting a commit")
+	generateCmd.MarkFlagsMutuallyExclusive("rev", "range", "unstaged", "patch", "stdin", "revert")
+}
+
+// diffSourceFromFlags returns the diff source selected by the generate flags
+func diffSourceFromFlags() (git.DiffSource, error) {
+	switch {
+	case revFlag != "":
+		return git.CommitSource(revFlag), nil
+	case rangeFlag != "":
+		return git.RangeSource(rangeFlag), nil
+	case unstagedFlag:
+		return git.WorkTreeSource(), nil
+	case patchFlag != "":
+		return git.PatchFileSource(patchFlag)
+	case stdinFlag:
+		return git.PatchFileSource("-")
+	default:
+		return git.StagedSource(), nil
+	}
 }
 
 func runGenerate(cmd *cobra.Command, args []string) error {
@@ -39,50 +94,84 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		display.ShowHeader()
 	}
 
-	// Check if in git repository
-	if !git.IsGitRepository() {
+	// Determine where the diff comes from
+	src, err := diffSourceFromFlags()
+	if err != nil {
+		return err
+	}
+
+	// Check if in git repository (patches can be described anywhere)
+	if src.IsGitBacked() && !git.IsGitRepository() {
 		return fmt.Errorf("not a git repository\nInitialize git first:\n  $ git init")
 	}
 
 	// Load configuration
-	cfg, err := config.LoadConfig()
+	cfg, err := loadConfig(cmd, "model", "language", "subject-length", "prompt-scope")
 	if err != nil {
 		return err
 	}
 
-	// Override config with flags
-	if modelFlag != "" {
-		cfg.Model = modelFlag
+	// Merges and reverts in progress are described from their state
+	if revertFlag != "" {
+		sha, err := git.ResolveRev(revertFlag)
+		if err != nil {
+			return err
+		}
+		return runSequencerMessage(display, cfg, git.SequencerState{Operation: git.OperationRevert, Heads: []string{sha}})
 	}
-	if langFlag != "" {
-		cfg.Language = langFlag
+	if src.Kind == git.SourceStaged {
+		if state, err := git.GetSequencerState(); err == nil && state.Operation != git.OperationNone {
+			return runSequencerMessage(display, cfg, state)
+		}
 	}
 
-	// Get staged changes
-	diff, err := git.GetStagedDiff()
+	// Apply the configuration rules for this branch and these files
+	ruleCtx := ruleContext(src)
+	applied, err := applyRules(display, cfg, ruleCtx)
+	if err != nil {
+		return err
+	}
+
+	// Get changes from the selected source
+	diff, err := promptDiff(cfg, src)
 	if err != nil {
 		return err
 	}
 
 	// Get changed files with stats
-	fileChanges, err := git.GetChangedFilesWithStats()
+	fileChanges, err := src.FilesWithStats()
 	if err != nil {
 		return fmt.Errorf("failed to get file changes: %w", err)
 	}
 
 	// Display changed files (skip in quiet mode)
 	if !quietFlag {
+		if src.Kind != git.SourceStaged {
+			display.ShowInfo("Describing " + src.Describe())
+		}
 		display.ShowChangedFiles(fileChanges)
 	}
 
 	// Create selector for interactive prompts
 	selector := ui.NewCommitSelector(cfg)
 
+	// Stdin carries the patch, so there is no terminal to prompt on
+	interactive := !quietFlag && !stdinFlag
+
 	// Select commit type
 	commitType := typeFlag
 	if commitType == "" {
-		// In quiet mode, use default "feat" if no type specified
-		if quietFlag {
+		commitType = config.RuleType(applied)
+	}
+	if commitType == "" && src.Kind == git.SourceCommit {
+		// Keep the type of the commit being described if it is known
+		if previousMessage, err := git.GetCommitMessage(src.Rev); err == nil {
+			commitType = previousCommitType(cfg, previousMessage)
+		}
+	}
+	if commitType == "" {
+		// Without a terminal, use default "feat" if no type specified
+		if !interactive {
 			commitType = "feat"
 		} else {
 			commitType, err = selector.SelectType()
@@ -92,48 +181,62 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Select scope
+	// Rules for the commit type
+	ruleCtx.Type = commitType
+	if _, err := applyRules(display, cfg, ruleCtx); err != nil {
+		return err
+	}
+
+	// Select scope - only prompt if requested via flag or config, as commit does
 	scope := scopeFlag
-	if scopeFlag == "" && !quietFlag {
+	if scopeFlag == "" && interactive && cfg.WantPromptScope() {
 		scope, err = selector.SelectScope()
 		if err != nil {
 			return fmt.Errorf("scope selection cancelled")
 		}
 	}
 
+	// Handle ticket number (without a terminal, from the flag or the branch)
+	ticket := ticketFlag
+	if interactive {
+		ticket, err = resolveTicket(display, selector, cfg, src)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Get project context
 	if !quietFlag {
 		display.ShowGenerating()
 	}
 
-	ctx, err := git.GetProjectContext()
+	ctx, err := git.GetProjectContextFor(src)
 	if err != nil {
 		ctx = git.ProjectContext{}
 	}
+	if !interactive {
+		if ticket == "" && (cfg.WantTicket() || cfg.TicketPrefix != "") {
+			ticket = git.ExtractTicketFromBranch(ctx.BranchName, cfg.TicketPattern)
+		}
+		if ticket != "" {
+			ticket = git.FormatTicketNumber(ticket, cfg.TicketPrefix)
+		}
+	}
 
 	// Build prompt
-	promptBuilder := &ai.PromptBuilder{
-		CommitType: commitType,
-		Scope:      scope,
-		Diff:       diff,
-		Context: ai.ProjectContext{
-			ProjectName:   ctx.ProjectName,
-			RecentCommits: ctx.RecentCommits,
-			BranchName:    ctx.BranchName,
-			ChangedFiles:  ctx.ChangedFiles,
-			ReadmeSnippet: ctx.ReadmeSnippet,
-			DiffStats:     ctx.DiffStats,
-		},
-		Language:       cfg.Language,
-		DetailedCommit: cfg.DetailedCommit,
-		CustomPrompt:   cfg.CustomPrompt,
-	}
-
-	prompt := promptBuilder.Build()
+	builder := newPromptBuilder(cfg, commitType, scope, diff, ctx)
+	builder.TicketNumber = ticket
 
 	// Generate commit message
-	client := ai.NewOllamaClient(cfg.Model)
-	message, err := client.Generate(prompt)
+	client, err := newAIClient(cfg)
+	if err != nil {
+		return err
+	}
+	// Streaming would mix the answer into the output of --quiet
+	message, err := generateChecked(client, builder.Evidence(), streamFlag && !quietFlag, func(attempt int) string {
+		builder.RegenerateCount = attempt
+		return builder.Build()
+	})
 	if err != nil {
 		return fmt.Errorf("failed to generate commit message: %w", err)
 	}
@@ -155,3 +258,16 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// defaultCommitType picks the most common commit type suggested by the changed files
+func defaultCommitType(fileChanges []git.FileChange) string {
+	files := make([]string, len(fileChanges))
+	for i, fc := range fileChanges {
+		files[i] = fc.File
+	}
+
+	if commitType := git.DominantType(files); commitType != "" {
+		return commitType
+	}
+	return "feat"
+}
@@ -39,10 +39,18 @@ After installation, GitAI will automatically generate commit messages when you r
 
 The hook will be skipped when:
   - Using 'git commit -m "message"' (message already provided)
-  - During merge commits
   - During rebase/cherry-pick operations
   - Using 'git commit --no-verify' (explicitly skip hooks)
 
+Squash merges (git merge --squash) get one consolidated message instead of
+git's list of squashed commits. Merge commits summarize what the merged branch
+brings in and which conflicts were resolved, and reverts get a "revert:"
+message referencing the reverted commit.
+
+With --all, a pre-commit hook runs 'gitai check' on the staged changes and
+stops the commit on blocking findings such as secrets or conflict markers
+(see precheck in .gitcommit.yaml).
+
 If existing hooks are found, they will be backed up automatically.`,
 	Example: `  # Install the prepare-commit-msg hook
   gitai hooks install
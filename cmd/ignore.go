@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/xyue92/gitai/internal/config"
+	"github.com/xyue92/gitai/internal/git"
+	"github.com/xyue92/gitai/internal/ignore"
+)
+
+// loadIgnore returns the paths that must not reach the model: the patterns
+// of .gitaiignore at the top of the repository followed by cfg.Ignore
+func loadIgnore(cfg *config.Config) (*ignore.Matcher, error) {
+	root, err := git.GetRepoRoot()
+	if err != nil {
+		root = "."
+	}
+	matcher, err := ignore.Load(root, cfg.Ignore)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ignore configuration: %w", err)
+	}
+	return matcher, nil
+}
+
+// promptDiff returns the diff of src for a prompt: ignored files keep their
+// headers, but their content is replaced by a "N lines changed" summary
+func promptDiff(cfg *config.Config, src git.DiffSource) (string, error) {
+	diff, err := src.Diff()
+	if err != nil {
+		return "", err
+	}
+	return filterDiff(cfg, diff)
+}
+
+// filterDiff removes the content of ignored files from diff
+func filterDiff(cfg *config.Config, diff string) (string, error) {
+	matcher, err := loadIgnore(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	filtered, exclusions := matcher.FilterDiff(diff)
+	if showRedactions {
+		for _, exclusion := range exclusions {
+			fmt.Fprintf(os.Stderr, "🔒 Left out %s (%d lines, ignored)\n", exclusion.Path, exclusion.Lines)
+		}
+	}
+	return filtered, nil
+}
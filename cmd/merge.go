@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xyue92/gitai/internal/ai"
+	"github.com/xyue92/gitai/internal/config"
+	"github.com/xyue92/gitai/internal/git"
+	"github.com/xyue92/gitai/internal/ui"
+)
+
+// maxFallbackCommits limits the commit list of a merge summary written
+// without the model
+const maxFallbackCommits = 10
+
+// runSequencerMessage prints the message for the merge or revert in progress.
+// It is described from MERGE_HEAD/REVERT_HEAD rather than the staged diff.
+func runSequencerMessage(display *ui.Display, cfg *config.Config, state git.SequencerState) error {
+	var message string
+	var err error
+	if state.Operation == git.OperationRevert {
+		message, err = revertMessage(state)
+	} else {
+		message, err = mergeMessage(display, cfg, state)
+	}
+	if err != nil {
+		return err
+	}
+
+	if quietFlag {
+		fmt.Print(message)
+		return nil
+	}
+
+	display.ShowCommitMessage(strings.TrimRight(message, "\n"))
+	display.ShowInfo("Conclude it with: git commit, or copy this message")
+	return nil
+}
+
+// revertMessage returns the message for the revert in progress
+func revertMessage(state git.SequencerState) (string, error) {
+	commits, err := git.LogCommits(state.Heads[0], "-1")
+	if err != nil {
+		return "", err
+	}
+	if len(commits) == 0 {
+		return "", fmt.Errorf("unknown revision: %s", state.Heads[0])
+	}
+
+	return git.RevertMessage(commits[0]), nil
+}
+
+// mergeMessage returns the message for the merge in progress: git's subject,
+// a summary of the merged commits and the conflicts that were resolved
+func mergeMessage(display *ui.Display, cfg *config.Config, state git.SequencerState) (string, error) {
+	subject, branch := git.MergeSubject(state.Message)
+	if subject == "" {
+		subject = fmt.Sprintf("Merge commit '%s'", git.Commit{Hash: state.Heads[0]}.ShortHash())
+	}
+
+	var subjects []string
+	for _, head := range state.Heads {
+		commits, err := git.LogCommits("HEAD.."+head, "--no-merges", "--reverse")
+		if err != nil {
+			return "", err
+		}
+		for _, commit := range commits {
+			subjects = append(subjects, commit.Subject)
+		}
+	}
+	if len(subjects) == 0 {
+		return git.FormatMergeMessage(subject, "", state.Conflicts), nil
+	}
+
+	if !quietFlag {
+		display.ShowInfo(fmt.Sprintf("Describing merge of %d commits", len(subjects)))
+		display.ShowGenerating()
+	}
+
+	stats, _ := git.StagedSource().Stats()
+	builder := &ai.MergePromptBuilder{
+		Subject:   subject,
+		Branch:    branch,
+		Commits:   subjects,
+		Conflicts: state.Conflicts,
+		DiffStats: stats,
+		Language:  cfg.Language,
+	}
+
+	summary := ""
+	client, err := newAIClient(cfg)
+	if err != nil {
+		return "", err
+	}
+	response, err := client.Generate(builder.Build())
+	if err == nil {
+		summary = cleanCommitMessage(response)
+	} else {
+		// The merge still gets an informative message without the model
+		if !quietFlag {
+			display.ShowWarning(fmt.Sprintf("Listing the merged commits instead: %v", err))
+		}
+		summary = mergeCommitList(subjects)
+	}
+
+	return git.FormatMergeMessage(subject, summary, state.Conflicts), nil
+}
+
+// mergeCommitList lists the merged commit subjects, newest last
+func mergeCommitList(subjects []string) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Brings in %d commits:\n", len(subjects)))
+	for i, subject := range subjects {
+		if i == maxFallbackCommits {
+			b.WriteString(fmt.Sprintf("- ... and %d more\n", len(subjects)-maxFallbackCommits))
+			break
+		}
+		b.WriteString(fmt.Sprintf("- %s\n", subject))
+	}
+	return b.String()
+}
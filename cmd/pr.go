@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xyue92/gitai/internal/ai"
+	"github.com/xyue92/gitai/internal/config"
+	"github.com/xyue92/gitai/internal/git"
+	"github.com/xyue92/gitai/internal/pr"
+)
+
+var (
+	prBase       string
+	prHead       string
+	prOutput     string
+	prJSON       bool
+	prTemplate   string
+	prNoTemplate bool
+)
+
+var prCmd = &cobra.Command{
+	Use:   "pr",
+	Short: "Generate a pull/merge request title and description",
+	Long: `Generate a pull or merge request title and Markdown description from all
+commits and the combined diff between the merge base with --base and HEAD.
+
+The description has a summary, the changes grouped by area, testing notes and
+the tickets referenced by the branch name and commits. If the repository has a
+pull request template (.github/pull_request_template.md, a GitLab merge
+request template, ...) the description follows it instead.
+
+Progress is printed to stderr, so the output can be captured directly.`,
+	Example: `  # Describe the current branch against the default branch
+  gitai pr
+
+  # Create a GitHub pull request
+  gitai pr --base main -o pr.md > title.txt
+  gh pr create --base main --title "$(cat title.txt)" --body-file pr.md
+
+  # Create a GitLab merge request
+  gitai pr --json > mr.json
+  glab mr create --title "$(jq -r .title mr.json)" --description "$(jq -r .body mr.json)"`,
+	Args: cobra.NoArgs,
+	RunE: runPR,
+}
+
+func init() {
+	rootCmd.AddCommand(prCmd)
+
+	prCmd.Flags().StringVar(&prBase, "base", "", "Target branch (default: origin's default branch, main or master)")
+	prCmd.Flags().StringVar(&prHead, "head", "HEAD", "Source revision")
+	prCmd.Flags().StringVarP(&prOutput, "output", "o", "", "Write the body to a file and print only the title")
+	prCmd.Flags().BoolVar(&prJSON, "json", false, "Print title, body, branches and tickets as JSON")
+	prCmd.Flags().StringVar(&prTemplate, "template", "", "PR template file (default: the repository's template)")
+	prCmd.Flags().BoolVar(&prNoTemplate, "no-template", false, "Ignore the repository's PR template")
+	prCmd.Flags().StringVarP(&langFlag, "language", "l", "", "Description language")
+	prCmd.Flags().StringVarP(&modelFlag, "model", "m", "", "Ollama model to use")
+	prCmd.MarkFlagsMutuallyExclusive("template", "no-template")
+}
+
+func runPR(cmd *cobra.Command, args []string) error {
+	if !git.IsGitRepository() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	cfg, err := loadConfig(cmd, "model", "language")
+	if err != nil {
+		return err
+	}
+
+	base := prBase
+	if base == "" {
+		if base, err = git.DefaultBranch(); err != nil {
+			return err
+		}
+	}
+	mergeBase, err := git.MergeBase(base, prHead)
+	if err != nil {
+		return err
+	}
+
+	revRange := mergeBase + ".." + prHead
+	commits, err := git.LogCommits(revRange, "--no-merges", "--reverse")
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf("no commits between %s and %s", base, prHead)
+	}
+
+	src := git.RangeSource(revRange)
+	diff, err := promptDiff(cfg, src)
+	if err != nil {
+		return err
+	}
+	ctx, err := git.GetProjectContextFor(src)
+	if err != nil {
+		ctx = git.ProjectContext{}
+	}
+
+	branch := ctx.BranchName
+	if prHead != "HEAD" {
+		branch = prHead
+	}
+	tickets := prTickets(cfg, branch, commits)
+
+	templatePath, templateText, err := prTemplateText()
+	if err != nil {
+		return err
+	}
+
+	subjects := make([]string, len(commits))
+	for i, commit := range commits {
+		subjects[i] = commit.Subject
+	}
+	var areas []string
+	for _, area := range pr.Areas(ctx.ChangedFiles, 2) {
+		areas = append(areas, area.Describe())
+	}
+
+	budgeted, analysis := budgetDiff(cfg, diff)
+	builder := &ai.PRPromptBuilder{
+		Project:   ctx.ProjectName,
+		Branch:    branch,
+		Base:      base,
+		Commits:   subjects,
+		Areas:     areas,
+		Tickets:   tickets,
+		DiffStats: ctx.DiffStats,
+		Analysis:  analysis,
+		Diff:      budgeted,
+		Template:  templateText,
+		Language:  cfg.Language,
+	}
+
+	fmt.Fprintf(os.Stderr, "🤖 Describing %d commits (%d files) against %s...\n", len(commits), len(ctx.ChangedFiles), base)
+	if templatePath != "" {
+		fmt.Fprintf(os.Stderr, "📋 Using template %s\n", templatePath)
+	}
+
+	client, err := newAIClient(cfg)
+	if err != nil {
+		return err
+	}
+	evidence := ai.NewEvidence(ctx.ChangedFiles, diff)
+	response, err := generateChecked(client, evidence, false, func(int) string { return builder.Build() })
+	if err != nil {
+		return fmt.Errorf("failed to generate PR description: %w", err)
+	}
+	title, body, err := ai.ParsePRResponse(response)
+	if err != nil {
+		return fmt.Errorf("failed to parse PR description: %w", err)
+	}
+
+	description := pr.Description{
+		Title:   title,
+		Body:    pr.LinkTickets(body, tickets, cfg.Changelog.TicketURL),
+		Base:    base,
+		Head:    branch,
+		Tickets: tickets,
+	}
+
+	switch {
+	case prJSON:
+		data, err := json.MarshalIndent(description, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	case prOutput != "":
+		if err := os.WriteFile(prOutput, []byte(strings.TrimSpace(description.Body)+"\n"), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", prOutput, err)
+		}
+		fmt.Fprintf(os.Stderr, "✅ Wrote description to %s\n", prOutput)
+		fmt.Println(description.Title)
+	default:
+		fmt.Print(description.String())
+	}
+
+	return nil
+}
+
+// prTickets returns the ticket of the branch name followed by the tickets
+// referenced in the commits, without duplicates
+func prTickets(cfg *config.Config, branch string, commits []git.Commit) []string {
+	var tickets []string
+	seen := make(map[string]bool)
+	add := func(ticket string) {
+		if ticket != "" && !seen[ticket] {
+			seen[ticket] = true
+			tickets = append(tickets, ticket)
+		}
+	}
+
+	add(git.ExtractTicketFromBranch(branch, cfg.TicketPattern))
+	for _, commit := range commits {
+		for _, ticket := range git.ExtractTickets(commit.Message(), cfg.TicketPattern, cfg.TicketPrefix) {
+			add(ticket)
+		}
+	}
+	return tickets
+}
+
+// prTemplateText returns the PR template selected by the flags, if any
+func prTemplateText() (string, string, error) {
+	if prNoTemplate {
+		return "", "", nil
+	}
+	if prTemplate != "" {
+		data, err := os.ReadFile(prTemplate)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read PR template: %w", err)
+		}
+		return prTemplate, string(data), nil
+	}
+
+	root, err := git.GetRepoRoot()
+	if err != nil {
+		return "", "", err
+	}
+	return pr.FindTemplate(root)
+}
+
+// budgetDiff cuts a diff to max_diff_length. With smart truncation the most
+// important chunks are kept and the analysis of the whole diff is returned
+// so the prompt still covers what was cut.
+func budgetDiff(cfg *config.Config, diff string) (string, *ai.DiffAnalysisInfo) {
+	if !cfg.DiffAnalysis.IsEnabled() {
+		if len(diff) > cfg.MaxDiffLength {
+			diff = diff[:cfg.MaxDiffLength] + "\n... (truncated)"
+		}
+		return diff, nil
+	}
+
+	analysis := git.AnalyzeDiff(diff, cfg.MaxDiffLength)
+	info := &ai.DiffAnalysisInfo{
+		ChangeComplexity: analysis.ChangeComplexity,
+		TotalFiles:       analysis.ModifiedFiles,
+		TotalAdditions:   analysis.TotalAdditions,
+		TotalDeletions:   analysis.TotalDeletions,
+	}
+	for _, summary := range analysis.FileSummaries {
+		info.FileSummaries = append(info.FileSummaries,
+			fmt.Sprintf("%s [%s] +%d/-%d", summary.Path, summary.Status, summary.Additions, summary.Deletions))
+	}
+	if cfg.DiffAnalysis.WantFunctionNames() {
+		info.KeyChanges = analysis.KeyChanges
+	}
+	if cfg.DiffAnalysis.WantImports() {
+		info.ImportChanges = analysis.ImportChanges
+	}
+
+	if !cfg.DiffAnalysis.WantSmartTruncate() {
+		if len(diff) > cfg.MaxDiffLength {
+			diff = diff[:cfg.MaxDiffLength] + "\n... (truncated)"
+		}
+		return diff, info
+	}
+	return analysis.SmartDiff, info
+}
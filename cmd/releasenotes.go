@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xyue92/gitai/internal/ai"
+	"github.com/xyue92/gitai/internal/changelog"
+	"github.com/xyue92/gitai/internal/git"
+)
+
+var (
+	notesVersion string
+	notesFormat  string
+	notesOutput  string
+	notesNoAI    bool
+)
+
+var releaseNotesCmd = &cobra.Command{
+	Use:   "release-notes [<range>]",
+	Short: "Generate release notes for the users of a release",
+	Long: `Generate release notes for the people using a release rather than a list of
+commits.
+
+The notes start with user-facing highlights (features, fixes, performance and
+security changes), followed by upgrade notes written from the BREAKING CHANGE
+footers, and thank the contributors of the release. The model rewrites the
+highlights and upgrade notes for users; with --no-ai, or when the model is
+unavailable, the changelog entries are used as they are.
+
+Notes are written in every language of the languages setting (or in
+language), one after another. --language accepts a comma-separated list.
+With --no-ai only the first language is used, since the entries are not
+translated.
+
+Without a range, the commits since the latest tag are described, or the
+release of the latest tag when HEAD is tagged. The version defaults to the end
+of the range when it is a tag.`,
+	Example: `  # Notes for the next release
+  gitai release-notes
+
+  # Notes for a tagged release in English and Chinese, as JSON
+  gitai release-notes v1.1.0..v1.2.0 --language en,zh --format json
+
+  # Plain text notes from the changelog only
+  gitai release-notes --no-ai --format text -o NOTES.txt`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReleaseNotes,
+}
+
+func init() {
+	rootCmd.AddCommand(releaseNotesCmd)
+
+	releaseNotesCmd.Flags().StringVar(&notesVersion, "version", "", "Version heading (default: the tag ending the range, or Unreleased)")
+	releaseNotesCmd.Flags().StringVarP(&notesFormat, "format", "f", changelog.NotesMarkdown, "Output format: markdown, text or json")
+	releaseNotesCmd.Flags().StringVarP(&notesOutput, "output", "o", "", "Write the notes to a file instead of printing them")
+	releaseNotesCmd.Flags().BoolVar(&notesNoAI, "no-ai", false, "Use the changelog entries without the model")
+	releaseNotesCmd.Flags().StringVarP(&langFlag, "language", "l", "", "Languages of the notes, comma-separated (default: languages or language)")
+	releaseNotesCmd.Flags().StringVarP(&modelFlag, "model", "m", "", "Ollama model to use")
+}
+
+func runReleaseNotes(cmd *cobra.Command, args []string) error {
+	if !git.IsGitRepository() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	cfg, err := loadConfig(cmd, "model")
+	if err != nil {
+		return err
+	}
+	languages := cfg.GetEffectiveLanguages()
+	if langFlag != "" {
+		languages = strings.Split(langFlag, ",")
+	}
+	if notesNoAI {
+		languages = languages[:1]
+	}
+
+	// Select the commits
+	from, to := "", "HEAD"
+	if len(args) == 1 {
+		from, to = parseSquashRange(args[0])
+	} else if from = git.LatestTag(to); from != "" {
+		// On a tagged commit, describe the release of that tag
+		if pending, err := git.LogCommits(from+".."+to, "-1"); err == nil && len(pending) == 0 {
+			from, to = git.LatestTag(from+"^"), from
+		}
+	}
+	revRange := to
+	if from != "" {
+		revRange = from + ".." + to
+	}
+
+	commits, err := git.LogCommits(revRange, "--no-merges")
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf("no commits found in %s", revRange)
+	}
+
+	version := notesVersion
+	if version == "" && git.TagExists(to) {
+		version = to
+	}
+	var date time.Time
+	if version != "" {
+		date, _ = git.CommitDate(to)
+	}
+
+	release := changelog.Build(commits, changelog.Options{
+		Version:       version,
+		Date:          date,
+		Format:        changelog.FormatMarkdown,
+		TicketPattern: cfg.TicketPattern,
+		TicketPrefix:  cfg.TicketPrefix,
+	})
+	stats := git.AnalyzeCommits(commits)
+
+	var client *ai.OllamaClient
+	if !notesNoAI {
+		if client, err = newAIClient(cfg); err != nil {
+			return err
+		}
+	}
+
+	var notes []*changelog.Notes
+	for _, language := range languages {
+		n := changelog.BuildNotes(release, stats.AuthorStats, strings.TrimSpace(language))
+		if len(n.Highlights) == 0 && len(n.Upgrade) == 0 {
+			return fmt.Errorf("no user-facing changes in %s", revRange)
+		}
+		if client != nil {
+			writeHighlights(client, n)
+		}
+		notes = append(notes, n)
+	}
+
+	rendered, err := changelog.RenderNotes(notes, notesFormat)
+	if err != nil {
+		return err
+	}
+
+	if notesOutput == "" {
+		fmt.Print(rendered)
+		return nil
+	}
+	if err := os.WriteFile(notesOutput, []byte(rendered), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", notesOutput, err)
+	}
+	fmt.Fprintf(os.Stderr, "✅ Wrote release notes for %s to %s (%d commits)\n", release.Version, notesOutput, len(commits))
+	return nil
+}
+
+// writeHighlights asks the model to rewrite the highlights and upgrade notes
+// of notes for users. The changelog entries are kept if the model fails.
+func writeHighlights(client *ai.OllamaClient, notes *changelog.Notes) {
+	fmt.Fprintf(os.Stderr, "🤖 Writing release notes (%s)...\n", notes.Language)
+
+	builder := &ai.ReleaseHighlightsPromptBuilder{
+		Version:  notes.Version,
+		Changes:  notes.Highlights,
+		Breaking: notes.Upgrade,
+		Language: notes.Language,
+	}
+	response, err := client.Generate(builder.Build())
+	if err == nil {
+		var written ai.ReleaseHighlights
+		if written, err = ai.ParseReleaseHighlights(response); err == nil {
+			notes.Summary = written.Summary
+			notes.Highlights = written.Highlights
+			if len(notes.Upgrade) > 0 && len(written.Upgrade) > 0 {
+				notes.Upgrade = written.Upgrade
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "⚠️  Using the changelog entries for %s notes: %v\n", notes.Language, err)
+}
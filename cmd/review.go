@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/xyue92/gitai/internal/ai"
+	"github.com/xyue92/gitai/internal/git"
+	"github.com/xyue92/gitai/internal/ui"
+)
+
+// reviewStrict makes review fail when it reports issues
+var reviewStrict bool
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Review staged changes before committing",
+	Long: `Ask the model for a short, structured review of the staged changes before
+committing: possible bugs, changed behavior without tests, hard-coded secrets
+and debug leftovers.
+
+The review is a cheap local sanity check, not a replacement for code review.
+The staged diff goes through the same analysis and max_diff_length budget as
+commit messages. With --strict the command exits with an error when issues
+are reported, e.g. to use it from a script.`,
+	Example: `  # Review what is about to be committed
+  git add -p
+  gitai review
+
+  # Fail when the review reports issues
+  gitai review --strict --json`,
+	Args: cobra.NoArgs,
+	RunE: runReview,
+}
+
+func init() {
+	rootCmd.AddCommand(reviewCmd)
+
+	reviewCmd.Flags().BoolVar(&jsonFlag, "json", false, "Print the review as JSON")
+	reviewCmd.Flags().BoolVar(&reviewStrict, "strict", false, "Exit with an error when issues are reported")
+	reviewCmd.Flags().StringVarP(&langFlag, "language", "l", "", "Review language (en/zh)")
+	reviewCmd.Flags().StringVarP(&modelFlag, "model", "m", "", "Ollama model to use")
+}
+
+func runReview(cmd *cobra.Command, args []string) error {
+	display := ui.NewDisplay()
+
+	if !git.IsGitRepository() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	cfg, err := loadConfig(cmd, "model", "language")
+	if err != nil {
+		return err
+	}
+
+	src := git.StagedSource()
+	diff, err := promptDiff(cfg, src)
+	if err != nil {
+		return err
+	}
+	ctx, err := git.GetProjectContextFor(src)
+	if err != nil {
+		ctx = git.ProjectContext{}
+	}
+
+	budgeted, analysis := budgetDiff(cfg, diff)
+	builder := &ai.ReviewPromptBuilder{
+		DiffStats:    ctx.DiffStats,
+		Analysis:     analysis,
+		Diff:         budgeted,
+		Language:     cfg.Language,
+		CustomPrompt: cfg.CustomPrompt,
+	}
+
+	if !jsonFlag {
+		display.ShowInfo(fmt.Sprintf("Reviewing %d staged file(s)", len(ctx.ChangedFiles)))
+		fmt.Println()
+	}
+
+	client, err := newAIClient(cfg)
+	if err != nil {
+		return err
+	}
+	response, err := client.Generate(builder.Build())
+	if err != nil {
+		return fmt.Errorf("failed to review changes: %w", err)
+	}
+	review, err := ai.ParseReview(response)
+	if err != nil {
+		return fmt.Errorf("failed to parse review: %w", err)
+	}
+
+	if jsonFlag {
+		data, err := json.MarshalIndent(review, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		display.ShowReport([]ui.ReportSection{
+			{Title: "Possible bugs", Items: review.Bugs, Empty: "None found", Alert: true},
+			{Title: "Missing tests", Items: review.MissingTests, Empty: "None found", Alert: true},
+			{Title: "Secrets", Items: review.Secrets, Empty: "None found", Alert: true},
+			{Title: "Debug leftovers", Items: review.DebugLeftovers, Empty: "None found", Alert: true},
+		})
+		if review.Findings() == 0 {
+			display.ShowSuccess("Looks good to commit")
+		}
+	}
+
+	if reviewStrict && review.Findings() > 0 {
+		return fmt.Errorf("review reported %d issue(s)", review.Findings())
+	}
+	return nil
+}
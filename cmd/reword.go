@@ -0,0 +1,383 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xyue92/gitai/internal/config"
+	"github.com/xyue92/gitai/internal/git"
+	"github.com/xyue92/gitai/internal/ui"
+)
+
+var rewordAllFlag bool
+
+var rewordCmd = &cobra.Command{
+	Use:   "reword [<rev>]",
+	Short: "Regenerate the message of an existing commit",
+	Long: `Generate a new message for an existing commit from its own diff, using the
+old message as context. The old and new messages are shown side by side and
+nothing is rewritten until you confirm.
+
+For HEAD the commit is amended (message only, staged changes are left alone).
+For older commits an automated rebase rewrites only that commit's message;
+the content of every commit stays the same.
+
+With --range, every non-merge commit in the range whose subject fails the
+lint rules (not Conventional Commits, unknown type, vague like "wip" or
+"fix stuff", too long, trailing period) gets a new message generated from its
+own diff. The batch is reviewed as a whole and applied in a single rebase.`,
+	Example: `  # Reword the last commit
+  gitai reword
+
+  # Reword an older commit
+  gitai reword HEAD~3
+
+  # Reword every commit on the branch that fails the lint rules
+  gitai reword --range origin/main..HEAD
+
+  # Preview the batch as an old → new table
+  gitai reword --range origin/main..HEAD --dry-run`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if rangeFlag != "" {
+			if len(args) > 0 {
+				return fmt.Errorf("cannot combine a revision with --range")
+			}
+			return runRewordRange(cmd, args)
+		}
+		return runReword(cmd, args)
+	},
+}
+
+// rebaseTodoCmd is used as GIT_SEQUENCE_EDITOR by git.RewordCommits
+var rebaseTodoCmd = &cobra.Command{
+	Use:    git.RebaseTodoCommand + " <message-map> <todo-file>",
+	Hidden: true,
+	Args:   cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return git.RewriteRebaseTodoFile(args[0], args[1])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rewordCmd)
+	rootCmd.AddCommand(rebaseTodoCmd)
+
+	rewordCmd.Flags().BoolVarP(&dryRun, "dry-run", "d", false, "Show the new message without rewriting")
+	rewordCmd.Flags().StringVarP(&typeFlag, "type", "t", "", "Commit type (default: keep the existing type)")
+	rewordCmd.Flags().StringVarP(&scopeFlag, "scope", "s", "", "Commit scope")
+	rewordCmd.Flags().StringVarP(&langFlag, "language", "l", "", "Message language (en/zh)")
+	rewordCmd.Flags().StringVarP(&modelFlag, "model", "m", "", "Ollama model to use")
+	rewordCmd.Flags().BoolVarP(&streamFlag, "stream", "S", false, "Enable streaming output")
+	rewordCmd.Flags().StringVar(&rangeFlag, "range", "", "Reword commits in a range that fail the lint rules (e.g. origin/main..HEAD)")
+	rewordCmd.Flags().BoolVar(&rewordAllFlag, "all", false, "With --range, reword every commit, not only failing ones")
+}
+
+func runReword(cmd *cobra.Command, args []string) error {
+	display, err := startReword()
+	if err != nil {
+		return err
+	}
+
+	rev := "HEAD"
+	if len(args) == 1 {
+		rev = args[0]
+	}
+
+	sha, err := git.ResolveRev(rev)
+	if err != nil {
+		return err
+	}
+	if !git.IsAncestorOfHead(sha) {
+		return fmt.Errorf("%s is not part of the current branch", rev)
+	}
+
+	cfg, err := loadConfig(cmd, "model", "language")
+	if err != nil {
+		return err
+	}
+
+	message, err := rewordMessage(display, cfg, sha)
+	if err != nil {
+		return err
+	}
+	previousMessage, _ := git.GetCommitMessage(sha)
+
+	// Show old vs new and confirm before rewriting anything
+	display.ShowMessageComparison(previousMessage, message)
+
+	if dryRun {
+		display.ShowInfo(fmt.Sprintf("Would reword %s", rev))
+		return nil
+	}
+
+	selector := ui.NewCommitSelector(cfg)
+	apply, err := selector.Confirm(fmt.Sprintf("Rewrite the message of %s?", rev))
+	if err != nil || !apply {
+		return fmt.Errorf("reword cancelled by user")
+	}
+
+	if git.IsHead(sha) {
+		err = git.CommitWithOptions(message, git.CommitOptions{Amend: true, MessageOnly: true})
+	} else {
+		err = git.RewordCommits(map[string]string{sha: message})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reword: %w", err)
+	}
+
+	fmt.Println()
+	display.ShowSuccess(fmt.Sprintf("Reworded %s", rev))
+	return nil
+}
+
+// startReword shows the header and checks that we are in a repository
+func startReword() (*ui.Display, error) {
+	display := ui.NewDisplay()
+
+	if dryRun {
+		display.ShowDryRun()
+	} else {
+		display.ShowHeader()
+	}
+
+	if !git.IsGitRepository() {
+		return nil, fmt.Errorf("not a git repository\nInitialize git first:\n  $ git init")
+	}
+
+	return display, nil
+}
+
+// rewordItem is one commit of a batch reword
+type rewordItem struct {
+	commit  git.Commit
+	session *messageSession
+	message string
+}
+
+func runRewordRange(cmd *cobra.Command, args []string) error {
+	display, err := startReword()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(cmd, "model", "language")
+	if err != nil {
+		return err
+	}
+
+	commits, err := git.LogCommits(rangeFlag, "--no-merges", "--reverse")
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf("no commits found in %s", rangeFlag)
+	}
+	if !git.IsAncestorOfHead(commits[len(commits)-1].Hash) {
+		return fmt.Errorf("%s is not part of the current branch", rangeFlag)
+	}
+
+	// Find the commits whose subjects fail the lint rules
+	lintOpts := lintOptions(cfg)
+	var items []*rewordItem
+	for _, commit := range commits {
+		problems := git.LintSubject(commit.Subject, lintOpts)
+		if len(problems) == 0 && !rewordAllFlag {
+			continue
+		}
+		if len(problems) > 0 {
+			display.ShowWarning(fmt.Sprintf("%s %q: %s", commit.ShortHash(), commit.Subject, strings.Join(problems, ", ")))
+		}
+		items = append(items, &rewordItem{commit: commit})
+	}
+
+	if len(items) == 0 {
+		display.ShowSuccess(fmt.Sprintf("All %d commits in %s pass the lint rules", len(commits), rangeFlag))
+		return nil
+	}
+
+	// Generate a replacement for each commit from its own diff
+	fmt.Println()
+	display.ShowGenerating()
+	for i, item := range items {
+		display.ShowInfo(fmt.Sprintf("[%d/%d] %s %s", i+1, len(items), item.commit.ShortHash(), item.commit.Subject))
+
+		item.session, err = newRewordSession(display, cfg, item.commit.Hash, false)
+		if err != nil {
+			return err
+		}
+		item.message, err = item.session.generate()
+		if err != nil {
+			return err
+		}
+	}
+
+	if dryRun {
+		display.ShowRewordPlan(rewordPlan(items))
+		display.ShowInfo(fmt.Sprintf("Would reword %d of %d commits", len(items), len(commits)))
+		return nil
+	}
+
+	items, err = reviewRewordBatch(display, ui.NewCommitSelector(cfg), items)
+	if err != nil {
+		return err
+	}
+
+	messages := make(map[string]string, len(items))
+	for _, item := range items {
+		messages[item.commit.Hash] = item.message
+	}
+	if err := git.RewordCommits(messages); err != nil {
+		return fmt.Errorf("failed to reword: %w", err)
+	}
+
+	fmt.Println()
+	display.ShowSuccess(fmt.Sprintf("Reworded %d commits", len(items)))
+	return nil
+}
+
+// reviewRewordBatch lets the user edit, regenerate or drop messages until the
+// batch is applied. Nothing is rewritten before the user confirms.
+func reviewRewordBatch(display *ui.Display, selector *ui.CommitSelector, items []*rewordItem) ([]*rewordItem, error) {
+	for {
+		if len(items) == 0 {
+			return nil, fmt.Errorf("nothing left to reword")
+		}
+
+		display.ShowRewordPlan(rewordPlan(items))
+
+		action, err := selector.ConfirmBatchAction()
+		if err != nil || action == ui.ActionCancel {
+			return nil, fmt.Errorf("reword cancelled by user")
+		}
+		if action == ui.ActionUse {
+			return items, nil
+		}
+
+		idx, err := selector.SelectItem("Select a commit", rewordChoices(items))
+		if err != nil {
+			continue
+		}
+		item := items[idx]
+
+		switch action {
+		case ui.ActionEdit:
+			edited, err := item.session.selector.EditMessage(item.message)
+			if err != nil {
+				display.ShowWarning("Edit cancelled")
+				continue
+			}
+			item.message = edited
+		case ui.ActionRegenerate:
+			item.session.builder.RegenerateCount++
+			message, err := item.session.generate()
+			if err != nil {
+				return nil, err
+			}
+			item.message = message
+		case ui.ActionDrop:
+			items = append(items[:idx], items[idx+1:]...)
+		}
+	}
+}
+
+// rewordPlan converts batch items into display rows
+func rewordPlan(items []*rewordItem) []ui.RewordEntry {
+	entries := make([]ui.RewordEntry, len(items))
+	for i, item := range items {
+		entries[i] = ui.RewordEntry{
+			Hash:       item.commit.ShortHash(),
+			OldSubject: item.commit.Subject,
+			NewSubject: strings.SplitN(item.message, "\n", 2)[0],
+		}
+	}
+	return entries
+}
+
+// rewordChoices lists batch items for selection
+func rewordChoices(items []*rewordItem) []string {
+	choices := make([]string, len(items))
+	for i, item := range items {
+		choices[i] = fmt.Sprintf("%s %s", item.commit.ShortHash(), item.commit.Subject)
+	}
+	return choices
+}
+
+// lintOptions returns the subject lint rules implied by the configuration
+func lintOptions(cfg *config.Config) git.LintOptions {
+	opts := git.LintOptions{MaxLength: 72}
+	if cfg.SubjectLength == "short" {
+		opts.MaxLength = 36
+	}
+	for _, t := range cfg.Types {
+		opts.Types = append(opts.Types, t.Name)
+	}
+	return opts
+}
+
+// rewordMessage generates and reviews a new message for an existing commit
+func rewordMessage(display *ui.Display, cfg *config.Config, sha string) (string, error) {
+	session, err := newRewordSession(display, cfg, sha, true)
+	if err != nil {
+		return "", err
+	}
+
+	return session.run()
+}
+
+// newRewordSession prepares a message session describing an existing commit
+// from its own diff, with the old message as context. Non-interactive sessions
+// never prompt: the type falls back to the old message's type or the file types.
+func newRewordSession(display *ui.Display, cfg *config.Config, sha string, interactive bool) (*messageSession, error) {
+	src := git.CommitSource(sha)
+
+	diff, err := promptDiff(cfg, src)
+	if err != nil {
+		return nil, err
+	}
+
+	previousMessage, err := git.GetCommitMessage(sha)
+	if err != nil {
+		return nil, err
+	}
+
+	fileChanges, err := src.FilesWithStats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file changes: %w", err)
+	}
+
+	selector := ui.NewCommitSelector(cfg)
+	selector.DiffStats, _ = src.Stats()
+
+	commitType := typeFlag
+	if commitType == "" {
+		commitType = previousCommitType(cfg, previousMessage)
+	}
+	if commitType == "" {
+		if !interactive {
+			commitType = defaultCommitType(fileChanges)
+		} else {
+			display.ShowChangedFiles(fileChanges)
+			commitType, err = selector.SelectType()
+			if err != nil {
+				return nil, fmt.Errorf("type selection cancelled")
+			}
+		}
+	} else if interactive {
+		display.ShowChangedFiles(fileChanges)
+	}
+
+	if interactive {
+		display.ShowGenerating()
+	}
+	ctx, err := git.GetProjectContextFor(src)
+	if err != nil {
+		ctx = git.ProjectContext{}
+	}
+
+	promptBuilder := newPromptBuilder(cfg, commitType, scopeFlag, diff, ctx)
+	promptBuilder.PreviousMessage = previousMessage
+
+	return newMessageSession(display, selector, cfg, promptBuilder)
+}
@@ -15,6 +15,9 @@ intelligent, context-aware Git commit messages following Conventional Commits fo
 	Version: "1.0.0",
 }
 
+// configOverrideFlags are the key=value pairs of -c
+var configOverrideFlags []string
+
 // Execute runs the root command
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
@@ -25,4 +28,6 @@ func Execute() {
 
 func init() {
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.PersistentFlags().StringArrayVarP(&configOverrideFlags, "set", "c", nil,
+		"Set a configuration key for this run, e.g. -c model=llama3 (repeatable)")
 }
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/xyue92/gitai/internal/ai"
+	"github.com/xyue92/gitai/internal/config"
+	"github.com/xyue92/gitai/internal/ui"
+)
+
+// messageSession generates a commit message and lets the user review it:
+// use it, regenerate, edit it in the editor, or cancel
+type messageSession struct {
+	display    *ui.Display
+	selector   *ui.CommitSelector
+	client     *ai.OllamaClient
+	builder    *ai.PromptBuilder
+	stream     bool
+	maxRetries int
+}
+
+// newMessageSession creates a review session for the given prompt builder
+func newMessageSession(display *ui.Display, selector *ui.CommitSelector, cfg *config.Config, builder *ai.PromptBuilder) (*messageSession, error) {
+	client, err := newAIClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &messageSession{
+		display:    display,
+		selector:   selector,
+		client:     client,
+		builder:    builder,
+		stream:     streamFlag,
+		maxRetries: 3,
+	}, nil
+}
+
+// generate produces a single cleaned message without user interaction. An
+// answer that does not mention the change is asked for once more and flagged
+// if it still fails ai.CheckOutput, since the user reviews it anyway.
+func (s *messageSession) generate() (string, error) {
+	message, err := s.ask()
+	if err != nil || ai.CheckOutput(message, s.builder.Evidence()) == nil {
+		return message, err
+	}
+
+	s.builder.RegenerateCount++
+	if message, err = s.ask(); err != nil {
+		return "", err
+	}
+	if err := ai.CheckOutput(message, s.builder.Evidence()); err != nil {
+		s.display.ShowWarning(fmt.Sprintf("Review this message carefully: %v, the changes may contain text aimed at the model", err))
+	}
+	return message, nil
+}
+
+// ask sends the prompt once and returns the cleaned answer
+func (s *messageSession) ask() (string, error) {
+	prompt := s.builder.Build()
+
+	startTime := time.Now()
+	var message string
+	var err error
+
+	if s.stream {
+		// Use streaming mode
+		fmt.Print("\n")
+		message, err = s.client.GenerateStream(prompt, func(chunk string) {
+			fmt.Print(chunk)
+		})
+		fmt.Print("\n\n")
+	} else {
+		// Use non-streaming mode
+		message, err = s.client.Generate(prompt)
+	}
+
+	elapsed := time.Since(startTime)
+
+	if err != nil {
+		return "", fmt.Errorf("failed to generate commit message: %w", err)
+	}
+
+	// Display time taken
+	s.display.ShowInfo(fmt.Sprintf("[time elapsed: %.2fs]", elapsed.Seconds()))
+	fmt.Println()
+
+	return cleanCommitMessage(message), nil
+}
+
+// run generates messages until the user accepts one and returns it
+func (s *messageSession) run() (string, error) {
+	for i := 0; i < s.maxRetries; i++ {
+		message, err := s.generate()
+		if err != nil {
+			return "", err
+		}
+
+		// Show the final cleaned message in a box (also after streaming)
+		s.display.ShowCommitMessage(message)
+
+		// Ask user what to do
+		action, err := s.selector.ConfirmAction(message)
+		if err != nil {
+			return "", fmt.Errorf("action selection cancelled")
+		}
+
+		switch action {
+		case ui.ActionUse:
+			return message, nil
+		case ui.ActionRegenerate:
+			s.display.ShowGenerating()
+			// Increment regenerate count and rebuild prompt for variation
+			s.builder.RegenerateCount++
+			continue
+		case ui.ActionEdit:
+			edited, regenerate, err := s.edit(message)
+			if err != nil {
+				return "", err
+			}
+			if !regenerate {
+				return edited, nil
+			}
+			s.display.ShowGenerating()
+		case ui.ActionCancel:
+			return "", fmt.Errorf("commit cancelled by user")
+		}
+	}
+
+	return "", fmt.Errorf("max retries reached")
+}
+
+// edit handles edit mode with post-edit options. It returns the edited message,
+// or regenerate=true when the user asked to regenerate from their draft.
+func (s *messageSession) edit(message string) (string, bool, error) {
+	editedMessage := message
+	for {
+		edited, err := s.selector.EditMessage(editedMessage)
+		if err != nil {
+			return "", false, fmt.Errorf("edit cancelled")
+		}
+		editedMessage = edited
+
+		// Display the edited message
+		s.display.ShowCommitMessage(editedMessage)
+
+		// Ask what to do after editing
+		postEditAction, err := s.selector.ConfirmActionAfterEdit(editedMessage)
+		if err != nil {
+			return "", false, fmt.Errorf("action selection cancelled")
+		}
+
+		switch postEditAction {
+		case ui.ActionUse:
+			return editedMessage, false, nil
+		case ui.ActionRegenerateFromEdit:
+			// Use the edited message as a prompt to regenerate
+			s.builder.CustomPrompt = fmt.Sprintf(`USER'S COMMIT MESSAGE DRAFT:
+The user has provided the following commit message draft (which may be in mixed languages or incomplete).
+Your task is to understand their intent and generate a proper commit message based on it.
+
+User's draft:
+%s
+
+INSTRUCTIONS:
+1. Understand what the user is trying to communicate (even if it's in Chinese or mixed languages)
+2. Generate a professional commit message that captures the user's intent
+3. Keep the commit type (%s) unless the user clearly intended a different type
+4. Follow the Conventional Commits format
+5. Use the target language specified in the configuration
+
+If the user wrote:
+- "fix: 修复登录bug" → understand they mean "fix login bug" → output proper message in target language
+- "feat: add new 功能" → understand they mean "add new feature" → output proper message in target language
+- Mixed language content → understand the meaning → output clean message in target language`, editedMessage, s.builder.CommitType)
+
+			// Debug: Print before building prompt
+			s.display.ShowInfo(fmt.Sprintf("[DEBUG] Custom Prompt Set: %d chars", len(s.builder.CustomPrompt)))
+			s.display.ShowInfo(fmt.Sprintf("[DEBUG] User's draft: %s", editedMessage))
+
+			return "", true, nil
+		case ui.ActionEdit:
+			// Continue editing loop
+			continue
+		case ui.ActionCancel:
+			return "", false, fmt.Errorf("commit cancelled by user")
+		}
+	}
+}
@@ -0,0 +1,477 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xyue92/gitai/internal/ai"
+	"github.com/xyue92/gitai/internal/config"
+	"github.com/xyue92/gitai/internal/git"
+	"github.com/xyue92/gitai/internal/ignore"
+	"github.com/xyue92/gitai/internal/ui"
+)
+
+var (
+	hunksFlag     bool
+	aiClusterFlag bool
+)
+
+var splitCmd = &cobra.Command{
+	Use:   "split [flags] [-- <git commit args>...]",
+	Short: "Split staged changes into atomic commits",
+	Long: `Split the staged changes into several commits, each with its own generated
+message.
+
+By default one group is proposed per file type (code, docs, tests, ...).
+With --hunks the staged patch is split into hunks and hunks touching the
+same symbol are grouped, even across files; hunks outside any symbol join
+their file or directory. --ai asks the model to cluster the hunks by
+intent instead. The groups can be adjusted before anything is committed.
+
+One patch per group is written to .git/gitai/split and the groups are
+committed one after another. The original index is always saved as a
+stash entry ("gitai split backup") first, and restored automatically if a
+step fails, so the split can be undone.`,
+	Example: `  # Split by file type
+  gitai split
+
+  # Group related hunks across files
+  gitai split --hunks
+
+  # Let the model cluster hunks by intent, signing off every commit
+  gitai split --ai -- --signoff`,
+	RunE: runSplit,
+}
+
+func init() {
+	rootCmd.AddCommand(splitCmd)
+
+	splitCmd.Flags().BoolVarP(&dryRun, "dry-run", "d", false, "Show the groups and messages without committing")
+	splitCmd.Flags().BoolVar(&hunksFlag, "hunks", false, "Group individual hunks instead of whole files")
+	splitCmd.Flags().BoolVar(&aiClusterFlag, "ai", false, "Ask the model to cluster hunks by intent (implies --hunks)")
+	splitCmd.Flags().StringVarP(&typeFlag, "type", "t", "", "Commit type for groups without a suggested type")
+	splitCmd.Flags().StringVarP(&scopeFlag, "scope", "s", "", "Commit scope")
+	splitCmd.Flags().StringVarP(&langFlag, "language", "l", "", "Message language (en/zh)")
+	splitCmd.Flags().StringVarP(&modelFlag, "model", "m", "", "Ollama model to use")
+	splitCmd.Flags().BoolVarP(&streamFlag, "stream", "S", false, "Enable streaming output")
+}
+
+func runSplit(cmd *cobra.Command, args []string) error {
+	display := ui.NewDisplay()
+
+	if dryRun {
+		display.ShowDryRun()
+	} else {
+		display.ShowHeader()
+	}
+
+	if !git.IsGitRepository() {
+		return fmt.Errorf("not a git repository\nInitialize git first:\n  $ git init")
+	}
+
+	commitOpts, err := parseCommitPassthrough(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(cmd, "model", "language")
+	if err != nil {
+		return err
+	}
+
+	// Make sure there is something to split
+	src := git.StagedSource()
+	if _, err := src.Diff(); err != nil {
+		return err
+	}
+	fileChanges, err := src.FilesWithStats()
+	if err != nil {
+		return fmt.Errorf("failed to get file changes: %w", err)
+	}
+	display.ShowChangedFiles(fileChanges)
+
+	patchText, err := git.GetStagedPatch()
+	if err != nil {
+		return err
+	}
+
+	selector := ui.NewCommitSelector(cfg)
+	workflow := newSplitWorkflow(display, selector, cfg, git.ParsePatch(patchText), commitOpts)
+
+	switch {
+	case aiClusterFlag:
+		display.ShowInfo("🤖 Clustering hunks by intent...")
+		workflow.groups, err = clusterWithAI(cfg, workflow.patch)
+		if err != nil {
+			display.ShowWarning(fmt.Sprintf("AI clustering failed (%v), grouping by directory and symbol", err))
+			workflow.groups = git.ClusterHunks(workflow.patch)
+		}
+	case hunksFlag:
+		workflow.groups = git.ClusterHunks(workflow.patch)
+	default:
+		workflow.groups = git.GroupByTypeHints(workflow.patch)
+	}
+
+	split, err := workflow.adjust()
+	if err != nil {
+		return err
+	}
+	if !split {
+		all := workflow.patch.Hunks()
+		workflow.groups = []git.SplitGroup{{
+			Type:  git.DominantType(git.SplitGroup{Hunks: all}.Files()),
+			Label: "all changes",
+			Hunks: all,
+		}}
+	}
+
+	return workflow.commit(src)
+}
+
+// clusterWithAI asks the model to group the patch's hunks by intent
+func clusterWithAI(cfg *config.Config, patch *git.Patch) ([]git.SplitGroup, error) {
+	matcher, err := loadIgnore(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	hunks := patch.Hunks()
+	byID := make(map[int]git.Hunk, len(hunks))
+	builder := &ai.ClusterPromptBuilder{MaxHunkLines: 40}
+	var ids []int
+	for _, hunk := range hunks {
+		byID[hunk.ID] = hunk
+		ids = append(ids, hunk.ID)
+		summary := ai.HunkSummary{
+			ID:     hunk.ID,
+			File:   hunk.File,
+			Symbol: hunk.Symbol,
+			Lines:  hunk.Lines,
+		}
+		if matcher.Match(hunk.File) {
+			summary.Symbol = ""
+			summary.Lines = []string{ignore.Summary(hunk.File, changedLines(hunk.Lines))}
+		}
+		builder.Hunks = append(builder.Hunks, summary)
+	}
+	for _, t := range cfg.Types {
+		builder.Types = append(builder.Types, t.Name)
+	}
+
+	client, err := newAIClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Generate(builder.Build())
+	if err != nil {
+		return nil, err
+	}
+
+	clusters, err := ai.ParseClusterResponse(response, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]git.SplitGroup, 0, len(clusters))
+	for _, cluster := range clusters {
+		group := git.SplitGroup{Type: cluster.Type, Label: cluster.Label}
+		for _, id := range cluster.Hunks {
+			group.Hunks = append(group.Hunks, byID[id])
+		}
+		group.Hunks = sortedHunks(group.Hunks)
+		if cfg.GetTypeByName(group.Type) == nil {
+			group.Type = git.DominantType(group.Files())
+		}
+		if group.Label == "" {
+			group.Label = strings.Join(group.Files(), ", ")
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
+// splitWorkflow turns the staged changes into several commits, one per group.
+// Each group is staged with `git apply --cached`, gets its own generated
+// message and is committed in sequence. The original index is backed up first
+// and restored if anything fails partway.
+type splitWorkflow struct {
+	display  *ui.Display
+	selector *ui.CommitSelector
+	cfg      *config.Config
+	patch    *git.Patch
+	groups   []git.SplitGroup
+	opts     git.CommitOptions
+}
+
+// newSplitWorkflow creates a split workflow for a parsed staged patch
+func newSplitWorkflow(display *ui.Display, selector *ui.CommitSelector, cfg *config.Config, patch *git.Patch, opts git.CommitOptions) *splitWorkflow {
+	return &splitWorkflow{
+		display:  display,
+		selector: selector,
+		cfg:      cfg,
+		patch:    patch,
+		opts:     opts,
+	}
+}
+
+// adjust shows the proposed groups and lets the user change them. It returns
+// false when the user wants to commit everything together instead.
+func (w *splitWorkflow) adjust() (bool, error) {
+	for {
+		w.display.ShowSplitGroups(w.groups)
+
+		action, err := w.selector.ConfirmSplitAction()
+		if err != nil {
+			return false, fmt.Errorf("commit cancelled")
+		}
+
+		switch action {
+		case ui.ActionUse:
+			return true, nil
+		case ui.ActionCommitTogether:
+			return false, nil
+		case ui.ActionCancel:
+			return false, fmt.Errorf("commit cancelled by user")
+		case ui.ActionMove:
+			w.move()
+		case ui.ActionMerge:
+			w.merge()
+		case ui.ActionRetype:
+			w.retype()
+		case ui.ActionRegroup:
+			w.groups = git.ClusterHunks(w.patch)
+		}
+	}
+}
+
+// move moves a single change (hunk or whole file) to another or a new group
+func (w *splitWorkflow) move() {
+	type location struct{ group, hunk int }
+	var choices []string
+	var locations []location
+	for gi, group := range w.groups {
+		for hi, hunk := range group.Hunks {
+			choices = append(choices, fmt.Sprintf("[%d] %s", gi+1, hunk.Describe()))
+			locations = append(locations, location{gi, hi})
+		}
+	}
+
+	idx, err := w.selector.SelectItem("Select a change to move", choices)
+	if err != nil {
+		return
+	}
+	from := locations[idx]
+
+	target, err := w.selector.SelectItem("Move it to", append(w.groupChoices(), "(New group)"))
+	if err != nil || target == from.group {
+		return
+	}
+
+	hunk := w.groups[from.group].Hunks[from.hunk]
+	w.groups[from.group].Hunks = append(w.groups[from.group].Hunks[:from.hunk], w.groups[from.group].Hunks[from.hunk+1:]...)
+	if target == len(w.groups) {
+		w.groups = append(w.groups, git.SplitGroup{
+			Type:  git.DominantType([]string{hunk.File}),
+			Label: "moved changes",
+		})
+	}
+	w.groups[target].Hunks = sortedHunks(append(w.groups[target].Hunks, hunk))
+	w.dropEmptyGroups()
+}
+
+// merge combines two groups into one
+func (w *splitWorkflow) merge() {
+	if len(w.groups) < 2 {
+		return
+	}
+
+	first, err := w.selector.SelectItem("Merge group", w.groupChoices())
+	if err != nil {
+		return
+	}
+	second, err := w.selector.SelectItem("Into group", w.groupChoices())
+	if err != nil || first == second {
+		return
+	}
+
+	w.groups[second].Hunks = sortedHunks(append(w.groups[second].Hunks, w.groups[first].Hunks...))
+	w.groups[first].Hunks = nil
+	w.dropEmptyGroups()
+}
+
+// retype changes the commit type of a group
+func (w *splitWorkflow) retype() {
+	idx, err := w.selector.SelectItem("Select a group", w.groupChoices())
+	if err != nil {
+		return
+	}
+
+	commitType, err := w.selector.SelectType()
+	if err != nil {
+		return
+	}
+	w.groups[idx].Type = commitType
+}
+
+// groupChoices lists the groups for selection
+func (w *splitWorkflow) groupChoices() []string {
+	choices := make([]string, len(w.groups))
+	for i, group := range w.groups {
+		choices[i] = fmt.Sprintf("%d. %s - %s", i+1, group.Type, group.Label)
+	}
+	return choices
+}
+
+// dropEmptyGroups removes groups that have no changes left
+func (w *splitWorkflow) dropEmptyGroups() {
+	groups := w.groups[:0]
+	for _, group := range w.groups {
+		if len(group.Hunks) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	w.groups = groups
+}
+
+// sortedHunks returns hunks in their original patch order
+func sortedHunks(hunks []git.Hunk) []git.Hunk {
+	sort.Slice(hunks, func(i, j int) bool { return hunks[i].ID < hunks[j].ID })
+	return hunks
+}
+
+// commit creates one commit per group. In dry-run mode messages are generated
+// from each group's patch and nothing is staged or committed.
+func (w *splitWorkflow) commit(src git.DiffSource) error {
+	ticket, err := resolveTicket(w.display, w.selector, w.cfg, src)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		for i, group := range w.groups {
+			w.showProgress(i, group)
+			groupSrc := git.PatchSource(w.patch.Select(group.Hunks), group.Label)
+			message, err := w.generate(groupSrc, group, ticket)
+			if err != nil {
+				return err
+			}
+			w.display.ShowInfo("Would commit with message:")
+			w.display.ShowCommitMessage(message)
+		}
+		w.display.ShowInfo(fmt.Sprintf("Would create %d commits", len(w.groups)))
+		return nil
+	}
+
+	backup, err := git.BackupIndex()
+	if err != nil {
+		return err
+	}
+	if err := git.ResetIndex(); err != nil {
+		return err
+	}
+
+	if gitDir, err := git.GetGitDir(); err == nil {
+		dir := filepath.Join(gitDir, "gitai", "split")
+		if _, err := git.WriteSplitPatches(dir, w.patch, w.groups); err == nil {
+			w.display.ShowInfo(fmt.Sprintf("Wrote %d patches to %s", len(w.groups), dir))
+		}
+	}
+
+	showSigningInfo(w.display, w.opts)
+
+	for i, group := range w.groups {
+		w.showProgress(i, group)
+		if err := w.commitGroup(group, ticket); err != nil {
+			if restoreErr := backup.Restore(); restoreErr != nil {
+				return fmt.Errorf("%w\nfailed to restore the original index: %v\nThe staged tree was saved as %s", err, restoreErr, backup.Tree)
+			}
+			return fmt.Errorf("%w\nSplit aborted: the original index and HEAD were restored", err)
+		}
+	}
+
+	fmt.Println()
+	w.display.ShowSuccess(fmt.Sprintf("Created %d commits", len(w.groups)))
+	if backup.Stash != "" {
+		w.display.ShowInfo("The original index is kept in the stash as \"gitai split backup\". To undo the split:")
+		for _, undo := range backup.UndoCommands() {
+			w.display.ShowInfo("  $ " + undo)
+		}
+	}
+	return nil
+}
+
+// commitGroup stages a group's changes, generates its message and commits it
+func (w *splitWorkflow) commitGroup(group git.SplitGroup, ticket string) error {
+	if err := git.StagePatch(w.patch.Select(group.Hunks)); err != nil {
+		return err
+	}
+
+	message, err := w.generate(git.StagedSource(), group, ticket)
+	if err != nil {
+		return err
+	}
+
+	if err := git.CommitWithOptions(message, w.opts); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+
+	fmt.Println()
+	w.display.ShowCommitSuccess(message, group.Files())
+	return nil
+}
+
+// generate produces and reviews the message for one group
+func (w *splitWorkflow) generate(src git.DiffSource, group git.SplitGroup, ticket string) (string, error) {
+	diff, err := promptDiff(w.cfg, src)
+	if err != nil {
+		return "", err
+	}
+
+	commitType := group.Type
+	if commitType == "" {
+		commitType = typeFlag
+	}
+	if commitType == "" {
+		commitType, err = w.selector.SelectType()
+		if err != nil {
+			return "", fmt.Errorf("type selection cancelled")
+		}
+	}
+
+	ctx, err := git.GetProjectContextFor(src)
+	if err != nil {
+		ctx = git.ProjectContext{}
+	}
+
+	promptBuilder := newPromptBuilder(w.cfg, commitType, scopeFlag, diff, ctx)
+	promptBuilder.TicketNumber = ticket
+
+	w.selector.DiffStats, _ = src.Stats()
+	w.display.ShowGenerating()
+	session, err := newMessageSession(w.display, w.selector, w.cfg, promptBuilder)
+	if err != nil {
+		return "", err
+	}
+	return session.run()
+}
+
+// showProgress announces which group is being committed
+func (w *splitWorkflow) showProgress(i int, group git.SplitGroup) {
+	fmt.Println()
+	w.display.ShowInfo(fmt.Sprintf("Commit %d/%d: %s - %s", i+1, len(w.groups), group.Type, group.Label))
+}
+
+// changedLines counts the added and removed lines of a hunk
+func changedLines(lines []string) int {
+	count := 0
+	for _, line := range lines {
+		if strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-") {
+			count++
+		}
+	}
+	return count
+}
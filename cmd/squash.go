@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xyue92/gitai/internal/ai"
+	"github.com/xyue92/gitai/internal/git"
+	"github.com/xyue92/gitai/internal/ui"
+)
+
+var squashMessageCmd = &cobra.Command{
+	Use:   "squash-message [<base>..<head>]",
+	Short: "Generate one commit message for a squashed branch",
+	Long: `Generate a single Conventional Commit message that replaces the commits of a
+branch when it is squashed.
+
+The message is written from the branch's commit messages and its net diff
+(merge base to head), so work-in-progress subjects do not leak into history.
+Trailers of the commits (Signed-off-by, Refs, ...) are kept, other authors get
+a Co-authored-by trailer and referenced tickets are preserved.
+
+Without a range, the squash merge in progress (git merge --squash) is
+described using the commits listed in SQUASH_MSG and the staged changes. The
+prepare-commit-msg hook runs this automatically for squash merges.`,
+	Example: `  # Message for squashing a feature branch into main
+  gitai squash-message main..feature/login
+
+  # Use it for a squash merge
+  git merge --squash feature/login
+  git commit -m "$(gitai squash-message --quiet)"
+
+  # <base> alone means <base>..HEAD
+  gitai squash-message origin/main`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSquashMessage,
+}
+
+func init() {
+	rootCmd.AddCommand(squashMessageCmd)
+
+	squashMessageCmd.Flags().StringVarP(&typeFlag, "type", "t", "", "Commit type (default: derived from the commits)")
+	squashMessageCmd.Flags().StringVarP(&scopeFlag, "scope", "s", "", "Commit scope")
+	squashMessageCmd.Flags().StringVarP(&langFlag, "language", "l", "", "Message language (en/zh)")
+	squashMessageCmd.Flags().StringVarP(&modelFlag, "model", "m", "", "Ollama model to use")
+	squashMessageCmd.Flags().BoolVarP(&quietFlag, "quiet", "q", false, "Quiet mode - only output the message")
+}
+
+func runSquashMessage(cmd *cobra.Command, args []string) error {
+	display := ui.NewDisplay()
+
+	if !git.IsGitRepository() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	cfg, err := loadConfig(cmd, "model", "language")
+	if err != nil {
+		return err
+	}
+
+	var commits []git.Commit
+	var src git.DiffSource
+	branch := ""
+	if len(args) == 1 {
+		base, head := parseSquashRange(args[0])
+		commits, err = git.LogCommits(base+".."+head, "--no-merges", "--reverse")
+		src = git.RangeSource(base + "..." + head)
+		if head != "HEAD" {
+			branch = head
+		}
+	} else {
+		commits, err = squashMergeCommits()
+		src = git.StagedSource()
+	}
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf("no commits to squash in %s", src.Describe())
+	}
+
+	diff, err := promptDiff(cfg, src)
+	if err != nil {
+		return err
+	}
+	ctx, err := git.GetProjectContextFor(src)
+	if err != nil {
+		ctx = git.ProjectContext{}
+	}
+	if branch == "" {
+		branch = ctx.BranchName
+	}
+
+	commitType := typeFlag
+	if commitType == "" {
+		commitType = git.SquashType(commits)
+	}
+	if commitType == "" {
+		fileChanges, _ := src.FilesWithStats()
+		commitType = defaultCommitType(fileChanges)
+	}
+
+	var messages, breaking []string
+	for _, commit := range commits {
+		messages = append(messages, commit.Message())
+		if parsed, ok := git.ParseConventionalMessage(commit.Message()); ok && parsed.IsBreaking() {
+			breaking = append(breaking, parsed.BreakingChange)
+		}
+	}
+	tickets := prTickets(cfg, branch, commits)
+
+	budgeted, analysis := budgetDiff(cfg, diff)
+	builder := &ai.SquashPromptBuilder{
+		CommitType:    commitType,
+		Scope:         scopeFlag,
+		Commits:       messages,
+		Breaking:      breaking,
+		Tickets:       tickets,
+		DiffStats:     ctx.DiffStats,
+		Analysis:      analysis,
+		Diff:          budgeted,
+		Language:      cfg.Language,
+		SubjectLength: cfg.SubjectLength,
+		CustomPrompt:  cfg.CustomPrompt,
+	}
+
+	if !quietFlag {
+		display.ShowInfo(fmt.Sprintf("Squashing %d commits (%s)", len(commits), src.Describe()))
+		display.ShowGenerating()
+	}
+
+	client, err := newAIClient(cfg)
+	if err != nil {
+		return err
+	}
+	evidence := ai.NewEvidence(ctx.ChangedFiles, diff)
+	response, err := generateChecked(client, evidence, false, func(int) string { return builder.Build() })
+	if err != nil {
+		return fmt.Errorf("failed to generate squash message: %w", err)
+	}
+	message := squashTrailers(cleanCommitMessage(response), commits, tickets, breaking)
+
+	if quietFlag {
+		fmt.Print(message)
+		return nil
+	}
+
+	display.ShowCommitMessage(strings.TrimRight(message, "\n"))
+	return nil
+}
+
+// parseSquashRange splits "<base>..<head>" (or "...") into base and head;
+// a single revision is the base and HEAD the head
+func parseSquashRange(arg string) (string, string) {
+	separator := ".."
+	if strings.Contains(arg, "...") {
+		separator = "..."
+	}
+
+	parts := strings.SplitN(arg, separator, 2)
+	if len(parts) == 1 {
+		return parts[0], "HEAD"
+	}
+	if parts[1] == "" {
+		parts[1] = "HEAD"
+	}
+	return parts[0], parts[1]
+}
+
+// squashMergeCommits returns the commits of the squash merge in progress,
+// oldest first, from the commits git listed in SQUASH_MSG
+func squashMergeCommits() ([]git.Commit, error) {
+	squashMsg, err := git.ReadSquashMessage()
+	if err != nil {
+		return nil, err
+	}
+	hashes := git.ParseSquashMessage(squashMsg)
+	if len(hashes) == 0 {
+		return nil, fmt.Errorf("no squash merge in progress\nPass the branch range instead:\n  $ gitai squash-message main..feature")
+	}
+
+	// git lists the squashed commits newest first
+	return git.LogCommits("HEAD.."+hashes[0], "--no-merges", "--reverse")
+}
+
+// squashTrailers appends the trailers of the squashed commits to message,
+// plus a Refs trailer for tickets and a BREAKING CHANGE trailer for breaking
+// notes the generated message lost
+func squashTrailers(message string, commits []git.Commit, tickets, breaking []string) string {
+	trailers := git.SquashTrailers(commits, git.GetUserEmail())
+
+	var missing []string
+	for _, ticket := range tickets {
+		if !strings.Contains(message, ticket) {
+			missing = append(missing, ticket)
+		}
+	}
+	if len(missing) > 0 {
+		trailers = append(trailers, git.Footer{Token: "Refs", Value: strings.Join(missing, ", ")})
+	}
+
+	if parsed, ok := git.ParseConventionalMessage(message); len(breaking) > 0 && (!ok || !parsed.IsBreaking()) {
+		trailers = append(trailers, git.Footer{Token: "BREAKING CHANGE", Value: strings.Join(breaking, "; ")})
+	}
+
+	return git.AppendTrailers(message, trailers)
+}
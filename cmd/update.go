@@ -34,7 +34,7 @@ Example:
 func init() {
 	rootCmd.AddCommand(updateCmd)
 
-	updateCmd.Flags().BoolVarP(&checkOnly, "check", "c", false, "Only check for updates without installing")
+	updateCmd.Flags().BoolVar(&checkOnly, "check", false, "Only check for updates without installing")
 	updateCmd.Flags().BoolVarP(&forceFlag, "force", "f", false, "Force update even if already on latest version")
 }
 
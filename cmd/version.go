@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xyue92/gitai/internal/ai"
+	"github.com/xyue92/gitai/internal/changelog"
+	"github.com/xyue92/gitai/internal/config"
+	"github.com/xyue92/gitai/internal/git"
+	"github.com/xyue92/gitai/internal/semver"
+	"github.com/xyue92/gitai/internal/ui"
+)
+
+var (
+	versionLevel string
+	versionPre   string
+	versionBuild string
+	versionNoAI  bool
+	versionSign  bool
+	versionYes   bool
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Compute the next semantic version and tag releases",
+	Long: `Compute the next semantic version from the Conventional Commits since the
+latest version tag.
+
+Breaking changes bump the major version, features the minor version and
+fixes, performance improvements and reverts the patch version. Other commit
+types do not trigger a release. Pre-releases (--pre rc) are numbered from
+the latest pre-release tag of the same version.`,
+}
+
+var versionNextCmd = &cobra.Command{
+	Use:   "next",
+	Short: "Print the next version",
+	Example: `  # Show the next version and why
+  gitai version next
+
+  # Only print the version, e.g. for scripts
+  gitai version next --quiet
+
+  # Next release candidate with build metadata
+  gitai version next --pre rc --build 20260101`,
+	Args: cobra.NoArgs,
+	RunE: runVersionNext,
+}
+
+var versionBumpCmd = &cobra.Command{
+	Use:   "bump",
+	Short: "Create an annotated tag for the next version",
+	Long: `Create an annotated tag for the next version on HEAD.
+
+The tag message holds release notes written by the model from the changelog
+of the release. With --no-ai, or when the model is unavailable, the rendered
+changelog is used instead. The tag is not pushed.`,
+	Example: `  # Review the release notes and create the tag
+  gitai version bump
+
+  # Signed release candidate without prompts
+  gitai version bump --pre rc --sign --yes
+
+  # Show the tag that would be created
+  gitai version bump --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runVersionBump,
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+	versionCmd.AddCommand(versionNextCmd)
+	versionCmd.AddCommand(versionBumpCmd)
+
+	for _, cmd := range []*cobra.Command{versionNextCmd, versionBumpCmd} {
+		cmd.Flags().StringVar(&versionLevel, "level", "", "Force the bump level: major, minor or patch")
+		cmd.Flags().StringVar(&versionPre, "pre", "", "Pre-release identifier, e.g. rc or beta")
+		cmd.Flags().StringVar(&versionBuild, "build", "", "Build metadata, e.g. 20260101.sha")
+	}
+
+	versionNextCmd.Flags().BoolVarP(&quietFlag, "quiet", "q", false, "Quiet mode - only output the version")
+
+	versionBumpCmd.Flags().BoolVar(&versionNoAI, "no-ai", false, "Use the changelog as the tag message")
+	versionBumpCmd.Flags().BoolVar(&versionSign, "sign", false, "GPG sign the tag")
+	versionBumpCmd.Flags().BoolVarP(&versionYes, "yes", "y", false, "Create the tag without confirmation")
+	versionBumpCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the tag message without creating the tag")
+	versionBumpCmd.Flags().StringVarP(&langFlag, "language", "l", "", "Language of the release notes")
+	versionBumpCmd.Flags().StringVarP(&modelFlag, "model", "m", "", "Ollama model to use")
+}
+
+// versionPlan describes the next version and the commits that decided it
+type versionPlan struct {
+	current    semver.Version
+	currentTag string // Latest stable version tag, empty if there is none
+	next       semver.Version
+	level      semver.Level
+	summary    changelog.BumpSummary
+	commits    []git.Commit // Commits since currentTag, newest first
+}
+
+// planVersion computes the next version from the tags reachable from HEAD
+func planVersion() (*versionPlan, error) {
+	if !git.IsGitRepository() {
+		return nil, fmt.Errorf("not a git repository")
+	}
+
+	tags, err := git.ListTags("HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &versionPlan{}
+	var hasStable bool
+	plan.current, plan.currentTag, hasStable = semver.Latest(tags, false)
+	latest, _, hasLatest := semver.Latest(tags, true)
+	if !hasStable {
+		// Start from 0.0.0, keeping the prefix style of existing pre-releases
+		plan.current = semver.Version{Prefix: "v"}
+		if hasLatest {
+			plan.current.Prefix = latest.Prefix
+		}
+	}
+
+	revRange := "HEAD"
+	since := "the first commit"
+	if plan.currentTag != "" {
+		revRange = plan.currentTag + "..HEAD"
+		since = plan.currentTag
+	}
+	plan.commits, err = git.LogCommits(revRange, "--no-merges")
+	if err != nil {
+		return nil, err
+	}
+
+	plan.level, plan.summary = changelog.BumpLevel(plan.commits)
+	if versionLevel != "" {
+		if plan.level, err = semver.ParseLevel(versionLevel); err != nil {
+			return nil, err
+		}
+	}
+	if plan.level == semver.None {
+		return nil, fmt.Errorf("no feat, fix or breaking commits since %s\nUse --level to force a release", since)
+	}
+
+	plan.next = plan.current.Bump(plan.level)
+	if versionPre != "" {
+		plan.next = plan.next.WithPrerelease(versionPre, latest)
+	}
+	if versionBuild != "" {
+		plan.next.Build = strings.Split(versionBuild, ".")
+	}
+	if _, err := semver.Parse(plan.next.String()); err != nil {
+		return nil, fmt.Errorf("invalid --pre or --build value: %w", err)
+	}
+
+	return plan, nil
+}
+
+// show prints how the next version was computed
+func (p *versionPlan) show() {
+	current := "none"
+	if p.currentTag != "" {
+		current = p.currentTag
+	}
+
+	fmt.Printf("Current version: %s\n", current)
+	fmt.Printf("Commits:         %d (%d breaking, %d features, %d fixes, %d other)\n",
+		len(p.commits), p.summary.Breaking, p.summary.Features, p.summary.Fixes, p.summary.Other)
+	fmt.Printf("Bump:            %s\n", p.level)
+	fmt.Printf("Next version:    %s\n", p.next)
+}
+
+func runVersionNext(cmd *cobra.Command, args []string) error {
+	plan, err := planVersion()
+	if err != nil {
+		return err
+	}
+
+	if quietFlag {
+		fmt.Println(plan.next)
+		return nil
+	}
+
+	plan.show()
+	return nil
+}
+
+func runVersionBump(cmd *cobra.Command, args []string) error {
+	display := ui.NewDisplay()
+	if dryRun {
+		display.ShowDryRun()
+	}
+
+	plan, err := planVersion()
+	if err != nil {
+		return err
+	}
+
+	tag := plan.next.String()
+	if git.TagExists(tag) {
+		return fmt.Errorf("tag %s already exists", tag)
+	}
+
+	cfg, err := loadConfig(cmd, "model", "language")
+	if err != nil {
+		return err
+	}
+
+	plan.show()
+	fmt.Println()
+
+	release := changelog.Build(plan.commits, changelog.Options{
+		Version:       strings.TrimPrefix(tag, plan.next.Prefix),
+		Date:          time.Now(),
+		Format:        cfg.Changelog.Format,
+		Sections:      cfg.Changelog.Sections,
+		TicketPattern: cfg.TicketPattern,
+		TicketPrefix:  cfg.TicketPrefix,
+		TicketURL:     cfg.Changelog.TicketURL,
+	})
+	notes, err := changelog.Render(release, cfg.Changelog.Format, "")
+	if err != nil {
+		return err
+	}
+
+	message, err := tagMessage(display, cfg, tag, notes)
+	if err != nil {
+		return err
+	}
+
+	if !versionYes && !dryRun {
+		selector := ui.NewCommitSelector(cfg)
+	review:
+		for {
+			action, err := selector.ConfirmAction(message)
+			if err != nil {
+				return fmt.Errorf("selection cancelled: %w", err)
+			}
+
+			switch action {
+			case ui.ActionUse:
+				break review
+			case ui.ActionRegenerate:
+				if message, err = tagMessage(display, cfg, tag, notes); err != nil {
+					return err
+				}
+			case ui.ActionEdit:
+				edited, err := selector.EditMessage(message)
+				if err != nil {
+					return err
+				}
+				message = edited
+				display.ShowCommitMessage(message)
+			case ui.ActionCancel:
+				display.ShowInfo("Tag cancelled")
+				return nil
+			}
+		}
+	}
+
+	if dryRun {
+		display.ShowInfo(fmt.Sprintf("Would create tag %s on HEAD", tag))
+		return nil
+	}
+
+	if err := git.CreateTag(tag, "HEAD", message, versionSign); err != nil {
+		return err
+	}
+
+	display.ShowSuccess(fmt.Sprintf("Created tag %s", tag))
+	fmt.Printf("\nPush it with:\n  $ git push origin %s\n", tag)
+	return nil
+}
+
+// tagMessage builds the annotated tag message: a "Release <tag>" subject
+// followed by AI-written release notes, or the changelog as a fallback
+func tagMessage(display *ui.Display, cfg *config.Config, tag, changelogText string) (string, error) {
+	body := changelogText
+	if !versionNoAI {
+		fmt.Fprintln(os.Stderr, "🤖 Writing release notes...")
+		builder := &ai.ReleaseNotesPromptBuilder{Version: tag, Changelog: changelogText, Language: cfg.Language}
+		client, err := newAIClient(cfg)
+		if err != nil {
+			return "", err
+		}
+		response, err := client.Generate(builder.Build())
+		if err != nil {
+			display.ShowWarning(fmt.Sprintf("Using the changelog as release notes: %v", err))
+		} else if notes := cleanCommitMessage(response); notes != "" {
+			body = notes
+		}
+	}
+
+	message := fmt.Sprintf("Release %s\n\n%s\n", tag, strings.TrimSpace(body))
+	display.ShowCommitMessage(message)
+	return message, nil
+}
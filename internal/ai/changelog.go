@@ -0,0 +1,78 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xyue92/gitai/internal/i18n"
+)
+
+// ChangelogPromptBuilder constructs prompts that polish or summarize one
+// changelog section
+type ChangelogPromptBuilder struct {
+	Title     string   // Section title, e.g. "Added"
+	Entries   []string // Entry descriptions, in order
+	Language  string   // Output language code (default "en")
+	Summarize bool     // Write a short summary instead of rewriting the entries
+}
+
+// Build constructs the prompt
+func (cb *ChangelogPromptBuilder) Build() string {
+	var prompt strings.Builder
+
+	prompt.WriteString("You are a technical writer preparing release notes for a changelog.\n\n")
+
+	// Entries come from commit subjects and are fenced like diffs
+	data := newFence(strings.Join(cb.Entries, "\n"))
+	prompt.WriteString(data.rules())
+
+	prompt.WriteString(fmt.Sprintf("SECTION: %s\n", cb.Title))
+	prompt.WriteString("ENTRIES:\n")
+	prompt.WriteString(data.wrapList(cb.Entries))
+	prompt.WriteString("\n")
+
+	prompt.WriteString("TASK:\n")
+	if cb.Summarize {
+		prompt.WriteString("Write a summary of this section in one or two sentences for users reading\n")
+		prompt.WriteString("the release notes. Mention the most important changes only.\n\n")
+	} else {
+		prompt.WriteString("Rewrite each entry as a clear, user-facing changelog line. Fix grammar,\n")
+		prompt.WriteString("expand jargon and start with a capital letter. Do not invent changes.\n\n")
+	}
+
+	prompt.WriteString(fmt.Sprintf("Write in %s.\n\n", languageName(cb.Language)))
+
+	prompt.WriteString("OUTPUT FORMAT:\n")
+	if cb.Summarize {
+		prompt.WriteString("Output ONLY the summary text, no heading, no list and no explanation.\n")
+	} else {
+		prompt.WriteString(fmt.Sprintf("Output ONLY %d lines, one per entry in the same order, each starting with \"- \".\n", len(cb.Entries)))
+	}
+
+	return prompt.String()
+}
+
+// ParsePolishedEntries extracts the rewritten entries from a polish response.
+// It fails if the model did not return exactly one line per entry.
+func ParsePolishedEntries(response string, count int) ([]string, error) {
+	var entries []string
+	for _, line := range strings.Split(response, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") {
+			entries = append(entries, strings.TrimSpace(line[2:]))
+		}
+	}
+
+	if len(entries) != count {
+		return nil, fmt.Errorf("expected %d entries, got %d", count, len(entries))
+	}
+	return entries, nil
+}
+
+// languageName returns the name used in prompts for a language code
+func languageName(code string) string {
+	if lang, ok := i18n.GetLanguage(i18n.NormalizeLanguageCode(code)); ok {
+		return lang.AIPromptKey
+	}
+	return "English"
+}
@@ -0,0 +1,32 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePolishedEntries(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		count    int
+		want     []string
+		wantErr  bool
+	}{
+		{"dash list", "- Add OAuth2 login\n- Support pagination", 2, []string{"Add OAuth2 login", "Support pagination"}, false},
+		{"with preamble", "Here are the entries:\n\n* Fix crash on startup\n", 1, []string{"Fix crash on startup"}, false},
+		{"count mismatch", "- Only one", 2, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePolishedEntries(tt.response, tt.count)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePolishedEntries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePolishedEntries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// HunkSummary describes one hunk for the clustering prompt
+type HunkSummary struct {
+	ID     int
+	File   string
+	Symbol string
+	Lines  []string
+}
+
+// HunkCluster is a group of hunks the model considers one logical change
+type HunkCluster struct {
+	Type  string `json:"type"`
+	Label string `json:"label"`
+	Hunks []int  `json:"hunks"`
+}
+
+// ClusterPromptBuilder constructs prompts asking the model to group hunks by intent
+type ClusterPromptBuilder struct {
+	Hunks        []HunkSummary
+	Types        []string // Allowed commit types
+	MaxHunkLines int      // Lines shown per hunk (0 shows all)
+}
+
+// Build constructs the clustering prompt
+func (cb *ClusterPromptBuilder) Build() string {
+	var prompt strings.Builder
+
+	prompt.WriteString("You are an expert at organizing code changes into atomic Git commits.\n\n")
+
+	prompt.WriteString("TASK:\n")
+	prompt.WriteString("Group the following hunks so that each group is one logical change\n")
+	prompt.WriteString("(one intent, e.g. a bug fix, a feature, a refactoring or a docs update).\n")
+	prompt.WriteString("Hunks from different files belong together if they serve the same intent.\n\n")
+
+	if len(cb.Types) > 0 {
+		prompt.WriteString(fmt.Sprintf("Allowed commit types: %s\n\n", strings.Join(cb.Types, ", ")))
+	}
+
+	var content []string
+	for _, hunk := range cb.Hunks {
+		content = append(content, hunk.Lines...)
+	}
+	data := newFence(strings.Join(content, "\n"))
+	prompt.WriteString(data.rules())
+
+	prompt.WriteString("HUNKS:\n")
+	for _, hunk := range cb.Hunks {
+		header := fmt.Sprintf("[hunk %d] %s", hunk.ID, hunk.File)
+		if hunk.Symbol != "" {
+			header += " (" + hunk.Symbol + ")"
+		}
+		prompt.WriteString(header + "\n")
+
+		lines := hunk.Lines
+		if cb.MaxHunkLines > 0 && len(lines) > cb.MaxHunkLines {
+			lines = append(lines[:cb.MaxHunkLines:cb.MaxHunkLines], fmt.Sprintf("... (%d more lines)", len(hunk.Lines)-cb.MaxHunkLines))
+		}
+		prompt.WriteString(data.wrap(strings.Join(lines, "\n")))
+		prompt.WriteString("\n")
+	}
+
+	prompt.WriteString("OUTPUT FORMAT:\n")
+	prompt.WriteString("Respond with ONLY a JSON object, no explanation and no markdown:\n")
+	prompt.WriteString(`{"groups": [{"type": "feat", "label": "short description of the intent", "hunks": [0, 2]}]}`)
+	prompt.WriteString("\n\nEvery hunk id must appear in exactly one group.\n")
+
+	return prompt.String()
+}
+
+// ParseClusterResponse extracts hunk clusters from a model response. Unknown
+// and repeated hunk ids are ignored; hunks the model left out are collected in
+// a final "unassigned" cluster so no change is ever lost.
+func ParseClusterResponse(response string, ids []int) ([]HunkCluster, error) {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start == -1 || end < start {
+		return nil, fmt.Errorf("no JSON object in response")
+	}
+
+	var parsed struct {
+		Groups []HunkCluster `json:"groups"`
+	}
+	if err := json.Unmarshal([]byte(response[start:end+1]), &parsed); err != nil {
+		return nil, fmt.Errorf("invalid clustering response: %w", err)
+	}
+
+	known := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		known[id] = true
+	}
+
+	assigned := make(map[int]bool, len(ids))
+	var clusters []HunkCluster
+	for _, group := range parsed.Groups {
+		cluster := HunkCluster{Type: strings.ToLower(strings.TrimSpace(group.Type)), Label: strings.TrimSpace(group.Label)}
+		for _, id := range group.Hunks {
+			if known[id] && !assigned[id] {
+				assigned[id] = true
+				cluster.Hunks = append(cluster.Hunks, id)
+			}
+		}
+		if len(cluster.Hunks) > 0 {
+			clusters = append(clusters, cluster)
+		}
+	}
+
+	if len(clusters) == 0 {
+		return nil, fmt.Errorf("clustering response contains no usable groups")
+	}
+
+	var missing []int
+	for _, id := range ids {
+		if !assigned[id] {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		clusters = append(clusters, HunkCluster{Label: "unassigned", Hunks: missing})
+	}
+
+	return clusters, nil
+}
@@ -0,0 +1,48 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseClusterResponse(t *testing.T) {
+	ids := []int{0, 1, 2, 3}
+
+	tests := []struct {
+		name     string
+		response string
+		want     []HunkCluster
+		wantErr  bool
+	}{
+		{
+			name:     "all hunks assigned",
+			response: `{"groups": [{"type": "Fix", "label": "nil check", "hunks": [0, 2]}, {"type": "docs", "label": "usage", "hunks": [1, 3]}]}`,
+			want: []HunkCluster{
+				{Type: "fix", Label: "nil check", Hunks: []int{0, 2}},
+				{Type: "docs", Label: "usage", Hunks: []int{1, 3}},
+			},
+		},
+		{
+			name:     "wrapped in prose, duplicates and unknown ids dropped, missing collected",
+			response: "Here you go:\n```json\n{\"groups\": [{\"type\": \"feat\", \"label\": \"login\", \"hunks\": [0, 0, 9]}, {\"type\": \"test\", \"label\": \"tests\", \"hunks\": [0]}]}\n```",
+			want: []HunkCluster{
+				{Type: "feat", Label: "login", Hunks: []int{0}},
+				{Label: "unassigned", Hunks: []int{1, 2, 3}},
+			},
+		},
+		{name: "no JSON", response: "I cannot help with that", wantErr: true},
+		{name: "no usable groups", response: `{"groups": [{"type": "feat", "hunks": [7]}]}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseClusterResponse(tt.response, ids)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseClusterResponse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseClusterResponse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package ai
+
+import (
+	"errors"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ErrUnrelatedOutput is returned for outputs that mention nothing the change
+// touches, which is what a model hijacked by instructions in a diff produces.
+// Honest outputs can fail the check too, so callers treat it as a warning.
+var ErrUnrelatedOutput = errors.New("the output does not mention any changed file or symbol")
+
+// ErrLeakedMarkers is returned for outputs that repeat the data fence
+var ErrLeakedMarkers = errors.New("the output repeats the data markers of the prompt")
+
+// fenceMarkerPattern matches the markers written by fence
+var fenceMarkerPattern = regexp.MustCompile(`<<<DATA-[0-9a-f]{12}|DATA-[0-9a-f]{12}>>>`)
+
+// definitionPattern captures names defined or changed in added/removed lines
+var definitionPattern = regexp.MustCompile(`\b(?:func|def|class|type|interface|struct|enum|trait|fn|function|const|var|let|module)\s+(?:\([^)]*\)\s*)?([A-Za-z_]\w*)`)
+
+// hunkContextPattern captures the enclosing definition git writes after @@
+var hunkContextPattern = regexp.MustCompile(`^@@[^@]*@@\s*(.*)$`)
+
+// conventionalPrefixPattern matches the type of a Conventional Commit subject
+var conventionalPrefixPattern = regexp.MustCompile(`^(\w+)(\(([^)]*)\))?!?:\s*`)
+
+// wordPattern finds words and identifiers in outputs and names
+var wordPattern = regexp.MustCompile(`[A-Za-z][A-Za-z0-9]*`)
+
+// camelPattern splits identifiers such as parseHTTPRequest into words
+var camelPattern = regexp.MustCompile(`[A-Z]+[a-z0-9]*|[a-z0-9]+`)
+
+// genericWords are path and code words that say nothing about a change
+var genericWords = map[string]bool{
+	"internal": true, "src": true, "lib": true, "pkg": true, "cmd": true, "app": true,
+	"main": true, "index": true, "util": true, "utils": true, "common": true, "the": true,
+	"and": true, "for": true, "with": true, "new": true, "get": true, "set": true,
+}
+
+// kindWords are the words an output may use for a kind of file instead of
+// its name, e.g. "bump dependencies" for go.sum. Outputs of the listed types
+// pass whenever a file of the kind changed (see looseTypes).
+var kindWords = []struct {
+	match func(file string) bool
+	words []string
+	types []string
+}{
+	{func(f string) bool { return strings.Contains(f, "test") || strings.Contains(f, "spec") },
+		[]string{"test", "tests", "testing", "spec", "coverage"}, nil},
+	{func(f string) bool {
+		return strings.HasSuffix(f, ".md") || strings.HasSuffix(f, ".rst") || strings.HasPrefix(f, "docs/") || strings.Contains(f, "/docs/")
+	}, []string{"doc", "docs", "documentation", "readme", "guide", "typo", "wording"}, []string{"docs"}},
+	{func(f string) bool {
+		base := path.Base(f)
+		switch base {
+		case "go.mod", "go.sum", "package.json", "package-lock.json", "yarn.lock", "pnpm-lock.yaml",
+			"cargo.toml", "cargo.lock", "requirements.txt", "pipfile", "pipfile.lock", "poetry.lock",
+			"pom.xml", "build.gradle", "gemfile", "gemfile.lock", "composer.json", "composer.lock":
+			return true
+		}
+		return false
+	}, []string{"dependency", "dependencies", "deps", "bump", "upgrade", "version", "module", "package", "library"}, []string{"build", "chore"}},
+	{func(f string) bool {
+		base := path.Base(f)
+		switch base {
+		case "makefile", "gnumakefile", "cmakelists.txt", "build.sh", "magefile.go", ".goreleaser.yml", ".goreleaser.yaml", "go.work", ".tool-versions":
+			return true
+		}
+		return path.Ext(base) == ".mk"
+	}, []string{"build", "make", "makefile", "toolchain", "compile", "compiler", "target", "release"}, []string{"build", "chore"}},
+	{func(f string) bool {
+		return strings.HasPrefix(f, ".github/") || strings.Contains(f, "gitlab-ci") || strings.Contains(f, "jenkinsfile") || strings.HasPrefix(f, ".circleci/")
+	}, []string{"ci", "workflow", "pipeline", "action", "actions", "build", "release"}, []string{"ci", "build", "chore"}},
+	{func(f string) bool {
+		ext := path.Ext(f)
+		return ext == ".yaml" || ext == ".yml" || ext == ".toml" || ext == ".ini" || ext == ".env" || ext == ".json" || ext == ".conf"
+	}, []string{"config", "configuration", "settings", "option", "options"}, []string{"chore"}},
+	{func(f string) bool { return path.Base(f) == "dockerfile" || strings.HasPrefix(path.Base(f), "docker-") },
+		[]string{"docker", "container", "image"}, []string{"build", "ci", "chore"}},
+}
+
+// looseTypes are the commit types whose messages describe files by what they
+// do ("clarify installation steps" for README.md) rather than by name. Such
+// a message passes when a file of a kind that lists its type changed.
+var looseTypes = map[string]bool{"docs": true, "build": true, "ci": true, "chore": true}
+
+// Evidence is what an output about a change can be expected to mention: the
+// changed files and the symbols the diff defines or touches
+type Evidence struct {
+	Files   []string
+	Symbols []string
+}
+
+// NewEvidence collects the symbols of diff alongside the changed files
+func NewEvidence(files []string, diff string) Evidence {
+	evidence := Evidence{Files: files}
+	seen := make(map[string]bool)
+	add := func(symbol string) {
+		if symbol != "" && !seen[symbol] {
+			seen[symbol] = true
+			evidence.Symbols = append(evidence.Symbols, symbol)
+		}
+	}
+
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			if m := hunkContextPattern.FindStringSubmatch(line); m != nil {
+				for _, def := range definitionPattern.FindAllStringSubmatch(m[1], -1) {
+					add(def[1])
+				}
+			}
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"), strings.HasPrefix(line, "-"):
+			for _, def := range definitionPattern.FindAllStringSubmatch(line, -1) {
+				add(def[1])
+			}
+		}
+	}
+	return evidence
+}
+
+// Evidence returns what the commit message built by pb should mention
+func (pb *PromptBuilder) Evidence() Evidence {
+	return NewEvidence(pb.Context.ChangedFiles, pb.Diff)
+}
+
+// vocabulary returns the stemmed words that count as mentioning the change
+func (e Evidence) vocabulary() map[string]bool {
+	vocab := make(map[string]bool)
+	addWords := func(text string) {
+		for _, word := range wordPattern.FindAllString(text, -1) {
+			vocab[stem(strings.ToLower(word))] = true
+			for _, part := range camelPattern.FindAllString(word, -1) {
+				vocab[stem(strings.ToLower(part))] = true
+			}
+		}
+	}
+
+	for _, file := range e.Files {
+		addWords(strings.TrimSuffix(file, path.Ext(file)))
+		lower := strings.ToLower(file)
+		for _, kind := range kindWords {
+			if kind.match(lower) {
+				for _, word := range kind.words {
+					vocab[stem(word)] = true
+				}
+			}
+		}
+	}
+	for _, symbol := range e.Symbols {
+		addWords(symbol)
+	}
+
+	for word := range vocab {
+		if len(word) < 2 || genericWords[word] {
+			delete(vocab, word)
+		}
+	}
+	return vocab
+}
+
+// CheckOutput flags model outputs that cannot be about the change: outputs
+// that repeat the data markers, or that mention none of the changed files and
+// symbols. Without evidence every output passes, and so do outputs written
+// mostly in other scripts than Latin, since file and symbol names cannot be
+// compared with their words.
+func CheckOutput(output string, evidence Evidence) error {
+	if fenceMarkerPattern.MatchString(output) {
+		return ErrLeakedMarkers
+	}
+
+	vocab := evidence.vocabulary()
+	if len(vocab) == 0 {
+		return nil
+	}
+
+	// The scope of a Conventional Commit counts, its type does not
+	text := strings.TrimSpace(output)
+	if m := conventionalPrefixPattern.FindStringSubmatch(text); m != nil {
+		if looseTypes[strings.ToLower(m[1])] && evidence.hasKindOf(strings.ToLower(m[1])) {
+			return nil
+		}
+		text = m[3] + " " + text[len(m[0]):]
+	}
+	if !mostlyLatin(text) {
+		return nil
+	}
+
+	for _, word := range wordPattern.FindAllString(text, -1) {
+		candidates := []string{word}
+		candidates = append(candidates, camelPattern.FindAllString(word, -1)...)
+		for _, candidate := range candidates {
+			if mentions(vocab, stem(strings.ToLower(candidate))) {
+				return nil
+			}
+		}
+	}
+	return ErrUnrelatedOutput
+}
+
+// hasKindOf reports whether a changed file is of a kind that commits of
+// commitType usually touch, e.g. a Makefile for "build"
+func (e Evidence) hasKindOf(commitType string) bool {
+	for _, file := range e.Files {
+		lower := strings.ToLower(file)
+		for _, kind := range kindWords {
+			if !kind.match(lower) {
+				continue
+			}
+			for _, t := range kind.types {
+				if t == commitType {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// mostlyLatin reports whether at least half of the letters in text are Latin
+func mostlyLatin(text string) bool {
+	letters, latin := 0, 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if unicode.Is(unicode.Latin, r) {
+			latin++
+		}
+	}
+	return letters > 0 && latin*2 >= letters
+}
+
+// mentions reports whether word matches the vocabulary, allowing longer
+// forms of words of four letters or more ("auth" for "authentication")
+func mentions(vocab map[string]bool, word string) bool {
+	if len(word) < 2 || genericWords[word] {
+		return false
+	}
+	if vocab[word] {
+		return true
+	}
+	if len(word) < 4 {
+		return false
+	}
+	for known := range vocab {
+		if len(known) >= 4 && (strings.HasPrefix(word, known) || strings.HasPrefix(known, word)) {
+			return true
+		}
+	}
+	return false
+}
+
+// stem strips common English suffixes so "parsing" and "parser" match
+func stem(word string) string {
+	for _, suffix := range []string{"ations", "ation", "ings", "ing", "ers", "er", "ies", "es", "ed", "s"} {
+		if strings.HasSuffix(word, suffix) && len(word)-len(suffix) >= 3 {
+			if suffix == "ies" {
+				return word[:len(word)-3] + "y"
+			}
+			return word[:len(word)-len(suffix)]
+		}
+	}
+	return word
+}
@@ -0,0 +1,67 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MergePromptBuilder constructs prompts that summarize what a merge brings in
+type MergePromptBuilder struct {
+	Subject   string   // Merge subject prepared by git
+	Branch    string   // Merged branch, tag or commit
+	Commits   []string // Subjects of the merged commits, oldest first
+	Conflicts []string // Files whose conflicts were resolved
+	DiffStats string   // Stats of the changes the merge brings in
+	Language  string   // Output language code (default "en")
+}
+
+// Build constructs the prompt
+func (mb *MergePromptBuilder) Build() string {
+	var prompt strings.Builder
+
+	prompt.WriteString("You are a Git commit message generator expert.\n\n")
+
+	// Commit subjects come from the repository and are fenced like diffs
+	data := newFence(strings.Join(mb.Commits, "\n"))
+	prompt.WriteString(data.rules())
+
+	prompt.WriteString(fmt.Sprintf("MERGE: %s\n", mb.Subject))
+	if mb.Branch != "" {
+		prompt.WriteString(fmt.Sprintf("MERGED: %s\n", mb.Branch))
+	}
+	prompt.WriteString("\n")
+
+	commits := mb.Commits
+	prompt.WriteString(fmt.Sprintf("MERGED COMMITS (%d, oldest first):\n", len(commits)))
+	if len(commits) > defaultMaxPRCommits {
+		prompt.WriteString(fmt.Sprintf("- ... %d older commits omitted\n", len(commits)-defaultMaxPRCommits))
+		commits = commits[len(commits)-defaultMaxPRCommits:]
+	}
+	prompt.WriteString(data.wrapList(commits))
+	prompt.WriteString("\n")
+
+	if mb.DiffStats != "" {
+		prompt.WriteString("CHANGES SUMMARY:\n")
+		prompt.WriteString(strings.TrimRight(mb.DiffStats, "\n"))
+		prompt.WriteString("\n\n")
+	}
+
+	if len(mb.Conflicts) > 0 {
+		prompt.WriteString("CONFLICTS RESOLVED IN:\n")
+		for _, path := range mb.Conflicts {
+			prompt.WriteString(fmt.Sprintf("- %s\n", path))
+		}
+		prompt.WriteString("\n")
+	}
+
+	prompt.WriteString("TASK:\n")
+	prompt.WriteString("Summarize what this merge brings in for the body of the merge commit.\n")
+	prompt.WriteString("Group related commits and describe the resulting changes, not each commit.\n")
+	prompt.WriteString(fmt.Sprintf("Write in %s.\n\n", languageName(mb.Language)))
+
+	prompt.WriteString("OUTPUT FORMAT:\n")
+	prompt.WriteString("Output ONLY 1-5 lines, each starting with \"- \". No subject line, no heading,\n")
+	prompt.WriteString("no list of conflicts and no explanation.\n")
+
+	return prompt.String()
+}
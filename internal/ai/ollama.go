@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/xyue92/gitai/internal/redact"
 )
 
 // OllamaClient handles communication with local Ollama server
@@ -15,6 +17,11 @@ type OllamaClient struct {
 	Model        string
 	Client       *http.Client
 	EnableStream bool // Enable streaming output
+
+	// Redactor masks secrets in prompts before they are sent; nil sends
+	// prompts as they are. OnRedact is told what was masked.
+	Redactor *redact.Redactor
+	OnRedact func(redactions []redact.Redaction)
 }
 
 // OllamaRequest represents the request structure for Ollama API
@@ -53,7 +60,7 @@ func (c *OllamaClient) Generate(prompt string) (string, error) {
 	// Prepare request
 	reqBody := OllamaRequest{
 		Model:  c.Model,
-		Prompt: prompt,
+		Prompt: c.redact(prompt),
 		Stream: false,
 	}
 
@@ -112,7 +119,7 @@ func (c *OllamaClient) GenerateStream(prompt string, onChunk func(chunk string))
 	// Prepare request
 	reqBody := OllamaRequest{
 		Model:  c.Model,
-		Prompt: prompt,
+		Prompt: c.redact(prompt),
 		Stream: true,
 	}
 
@@ -174,6 +181,46 @@ func (c *OllamaClient) GenerateStream(prompt string, onChunk func(chunk string))
 	return fullResponse, nil
 }
 
+// redact masks the secrets in prompt with the client's redactor
+func (c *OllamaClient) redact(prompt string) string {
+	if c.Redactor == nil {
+		return prompt
+	}
+	redacted, redactions := c.Redactor.Redact(prompt)
+	if len(redactions) > 0 && c.OnRedact != nil {
+		c.OnRedact(redactions)
+	}
+	return redacted
+}
+
+// ListModels returns the names of the models installed in Ollama
+func (c *OllamaClient) ListModels() ([]string, error) {
+	resp, err := c.Client.Get(c.BaseURL + "/api/tags")
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to Ollama: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ollama returned status code: %d", resp.StatusCode)
+	}
+
+	var tags struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, fmt.Errorf("failed to parse model list: %w", err)
+	}
+
+	names := make([]string, len(tags.Models))
+	for i, model := range tags.Models {
+		names[i] = model.Name
+	}
+	return names, nil
+}
+
 // checkConnection checks if Ollama server is reachable
 func (c *OllamaClient) checkConnection() error {
 	url := c.BaseURL + "/api/tags"
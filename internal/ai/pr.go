@@ -0,0 +1,184 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultMaxPRCommits limits how many commit subjects are listed in a PR prompt
+const defaultMaxPRCommits = 50
+
+// PRPromptBuilder constructs prompts for pull/merge request descriptions
+type PRPromptBuilder struct {
+	Project    string
+	Branch     string            // Source branch
+	Base       string            // Target branch
+	Commits    []string          // Commit subjects, oldest first
+	Areas      []string          // Changed areas, one line each
+	Tickets    []string          // Tickets the PR relates to
+	DiffStats  string            // git diff --stat of the whole PR
+	Analysis   *DiffAnalysisInfo // Analysis of the combined diff (optional)
+	Diff       string            // Combined diff, already cut to the diff budget
+	Template   string            // Repository PR template (optional)
+	Language   string            // Output language code (default "en")
+	MaxCommits int               // Commit subjects to list (default 50); the newest are kept
+}
+
+// Build constructs the prompt
+func (pb *PRPromptBuilder) Build() string {
+	var prompt strings.Builder
+
+	prompt.WriteString("You are a senior engineer writing the description of a pull request for reviewers.\n\n")
+
+	// Commit subjects and the diff come from the repository
+	data := newFence(strings.Join(pb.Commits, "\n"), pb.Diff)
+	prompt.WriteString(data.rules())
+
+	prompt.WriteString("PULL REQUEST:\n")
+	if pb.Project != "" {
+		prompt.WriteString(fmt.Sprintf("- Project: %s\n", pb.Project))
+	}
+	if pb.Branch != "" {
+		prompt.WriteString(fmt.Sprintf("- Branch: %s\n", pb.Branch))
+	}
+	if pb.Base != "" {
+		prompt.WriteString(fmt.Sprintf("- Target: %s\n", pb.Base))
+	}
+	if len(pb.Tickets) > 0 {
+		prompt.WriteString(fmt.Sprintf("- Tickets: %s\n", strings.Join(pb.Tickets, ", ")))
+	}
+	prompt.WriteString("\n")
+
+	// Commits, keeping the newest ones when over budget
+	maxCommits := pb.MaxCommits
+	if maxCommits <= 0 {
+		maxCommits = defaultMaxPRCommits
+	}
+	commits := pb.Commits
+	prompt.WriteString(fmt.Sprintf("COMMITS (%d):\n", len(commits)))
+	if len(commits) > maxCommits {
+		prompt.WriteString(fmt.Sprintf("- ... %d older commits omitted\n", len(commits)-maxCommits))
+		commits = commits[len(commits)-maxCommits:]
+	}
+	prompt.WriteString(data.wrapList(commits))
+	prompt.WriteString("\n")
+
+	if len(pb.Areas) > 0 {
+		prompt.WriteString("CHANGED AREAS:\n")
+		for _, area := range pb.Areas {
+			prompt.WriteString(fmt.Sprintf("- %s\n", area))
+		}
+		prompt.WriteString("\n")
+	}
+
+	if pb.DiffStats != "" {
+		prompt.WriteString("CHANGES SUMMARY:\n")
+		prompt.WriteString(strings.TrimRight(pb.DiffStats, "\n"))
+		prompt.WriteString("\n\n")
+	}
+
+	if pb.Analysis != nil {
+		analysis := pb.Analysis
+		prompt.WriteString("DETAILED ANALYSIS:\n")
+		prompt.WriteString(fmt.Sprintf("Complexity: %s | Files: %d | +%d/-%d lines\n",
+			analysis.ChangeComplexity, analysis.TotalFiles,
+			analysis.TotalAdditions, analysis.TotalDeletions))
+		if len(analysis.KeyChanges) > 0 {
+			prompt.WriteString("Key code changes:\n")
+			for _, change := range analysis.KeyChanges {
+				prompt.WriteString(fmt.Sprintf("  - %s\n", change))
+			}
+		}
+		if len(analysis.ImportChanges) > 0 {
+			prompt.WriteString("Import/dependency changes:\n")
+			for _, imp := range analysis.ImportChanges {
+				prompt.WriteString(fmt.Sprintf("  - %s\n", imp))
+			}
+		}
+		prompt.WriteString("\n")
+	}
+
+	if pb.Diff != "" {
+		prompt.WriteString("CHANGES (diff):\n")
+		prompt.WriteString(data.wrap(pb.Diff))
+		prompt.WriteString("\n")
+	}
+
+	prompt.WriteString("TASK:\n")
+	prompt.WriteString("Write a title and a Markdown description for this pull request.\n")
+	prompt.WriteString("The title is a concise summary under 72 characters; if the commits follow\n")
+	prompt.WriteString("Conventional Commits, use the same format for the title.\n")
+	if pb.Template != "" {
+		prompt.WriteString("The description MUST follow the repository's pull request template below:\n")
+		prompt.WriteString("keep its headings and checklists, fill in every section from the changes and\n")
+		prompt.WriteString("leave checkboxes unchecked unless the changes clearly satisfy them.\n\n")
+		prompt.WriteString("TEMPLATE:\n")
+		prompt.WriteString(strings.TrimSpace(pb.Template))
+		prompt.WriteString("\n\n")
+	} else {
+		prompt.WriteString("Use these sections:\n")
+		prompt.WriteString("## Summary - what the pull request does and why, in 2-3 sentences\n")
+		prompt.WriteString("## Changes - bullet points grouped by area (### <area> per changed area)\n")
+		prompt.WriteString("## Testing - how the changes were or should be tested\n")
+		if len(pb.Tickets) > 0 {
+			prompt.WriteString("## Related tickets - the tickets listed above\n")
+		}
+		prompt.WriteString("\n")
+	}
+	prompt.WriteString("Only describe changes that appear above; do not invent features or test results.\n")
+	prompt.WriteString(fmt.Sprintf("Write in %s.\n\n", languageName(pb.Language)))
+
+	prompt.WriteString("OUTPUT FORMAT:\n")
+	prompt.WriteString("TITLE: <title>\n\n<Markdown description>\n\n")
+	prompt.WriteString("Output ONLY the title line and the description, without code fences.\n")
+
+	return prompt.String()
+}
+
+// ParsePRResponse splits a PR response into title and body. The title is
+// taken from a "TITLE:" line, or else from the first non-empty line.
+func ParsePRResponse(response string) (string, string, error) {
+	lines := strings.Split(strings.TrimSpace(stripCodeFence(response)), "\n")
+
+	title := ""
+	start := 0
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if upper := strings.ToUpper(trimmed); strings.HasPrefix(upper, "TITLE:") {
+			title = strings.TrimSpace(trimmed[len("TITLE:"):])
+		} else {
+			title = strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+		}
+		start = i + 1
+		break
+	}
+	title = strings.Trim(title, "\"'`*")
+	if title == "" {
+		return "", "", fmt.Errorf("response has no title")
+	}
+
+	body := strings.TrimSpace(strings.Join(lines[start:], "\n"))
+	if upper := strings.ToUpper(body); strings.HasPrefix(upper, "BODY:") || strings.HasPrefix(upper, "DESCRIPTION:") {
+		body = strings.TrimSpace(body[strings.Index(body, ":")+1:])
+	}
+	body = strings.TrimSpace(stripCodeFence(body))
+
+	return title, body, nil
+}
+
+// stripCodeFence removes a code fence wrapped around the whole text
+func stripCodeFence(text string) string {
+	trimmed := strings.TrimSpace(text)
+	if !strings.HasPrefix(trimmed, "```") || !strings.HasSuffix(trimmed, "```") || len(trimmed) < 6 {
+		return text
+	}
+
+	inner := strings.TrimSuffix(trimmed, "```")
+	if newline := strings.Index(inner, "\n"); newline != -1 {
+		return inner[newline+1:]
+	}
+	return text
+}
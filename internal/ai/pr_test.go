@@ -0,0 +1,71 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePRResponse(t *testing.T) {
+	tests := []struct {
+		name      string
+		response  string
+		wantTitle string
+		wantBody  string
+		wantErr   bool
+	}{
+		{
+			name:      "title line",
+			response:  "TITLE: feat(auth): add OAuth2 login\n\n## Summary\n\nAdds login.",
+			wantTitle: "feat(auth): add OAuth2 login",
+			wantBody:  "## Summary\n\nAdds login.",
+		},
+		{
+			name:      "body label and fence",
+			response:  "```markdown\nTitle: \"Add export\"\nBODY:\n## Summary\nExport.\n```",
+			wantTitle: "Add export",
+			wantBody:  "## Summary\nExport.",
+		},
+		{
+			name:      "markdown heading",
+			response:  "# Add export\n\nExport.",
+			wantTitle: "Add export",
+			wantBody:  "Export.",
+		},
+		{
+			name:     "empty",
+			response: "  \n",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, body, err := ParsePRResponse(tt.response)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePRResponse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if title != tt.wantTitle || body != tt.wantBody {
+				t.Errorf("ParsePRResponse() = %q, %q, want %q, %q", title, body, tt.wantTitle, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestPRPromptBuilderCommitBudget(t *testing.T) {
+	builder := &PRPromptBuilder{
+		Commits:    []string{"feat: one", "fix: two", "docs: three"},
+		MaxCommits: 2,
+		Template:   "## What\n\n## Why",
+	}
+	prompt := builder.Build()
+
+	if strings.Contains(prompt, "feat: one") || !strings.Contains(prompt, "1 older commits omitted") {
+		t.Error("Build() did not drop the oldest commit over budget")
+	}
+	if !strings.Contains(prompt, "docs: three") || !strings.Contains(prompt, "## What\n\n## Why") {
+		t.Error("Build() is missing the newest commit or the template")
+	}
+	if strings.Contains(prompt, "## Testing") {
+		t.Error("Build() used the default sections despite a template")
+	}
+}
@@ -42,6 +42,7 @@ type PromptBuilder struct {
 	TicketNumber     string   // Ticket/issue number (e.g., JIRA-123)
 	SubjectLength    string   // Subject length: "short" (36 chars) or "normal" (72 chars)
 	RegenerateCount  int      // Number of times regenerated (adds variation hints)
+	PreviousMessage  string   // Existing message being replaced (amend/reword)
 }
 
 // Build constructs the complete prompt for Ollama
@@ -51,6 +52,10 @@ func (pb *PromptBuilder) Build() string {
 	// Header
 	prompt.WriteString("You are a Git commit message generator expert.\n\n")
 
+	// Repository content is fenced so instructions hidden in it are not followed
+	data := newFence(pb.Diff, pb.Context.ReadmeSnippet, pb.PreviousMessage, strings.Join(pb.Context.RecentCommits, "\n"))
+	prompt.WriteString(data.rules())
+
 	// Project context
 	if pb.Context.ProjectName != "" || pb.Context.BranchName != "" || len(pb.Context.RecentCommits) > 0 {
 		prompt.WriteString("PROJECT CONTEXT:\n")
@@ -65,13 +70,12 @@ func (pb *PromptBuilder) Build() string {
 
 		if len(pb.Context.RecentCommits) > 0 {
 			prompt.WriteString("- Recent commits style:\n")
-			for _, commit := range pb.Context.RecentCommits {
-				prompt.WriteString(fmt.Sprintf("  * %s\n", commit))
-			}
+			prompt.WriteString(data.wrapList(pb.Context.RecentCommits))
 		}
 
 		if pb.Context.ReadmeSnippet != "" {
-			prompt.WriteString(fmt.Sprintf("- Project description: %s\n", pb.Context.ReadmeSnippet))
+			prompt.WriteString("- Project description (from the README):\n")
+			prompt.WriteString(data.wrap(pb.Context.ReadmeSnippet))
 		}
 
 		prompt.WriteString("\n")
@@ -98,6 +102,14 @@ func (pb *PromptBuilder) Build() string {
 		prompt.WriteString(fmt.Sprintf("IMPORTANT: Include the ticket number [%s] in the commit message.\n", pb.TicketNumber))
 	}
 
+	// Existing message being replaced (amend/reword)
+	if pb.PreviousMessage != "" {
+		prompt.WriteString("\nPREVIOUS COMMIT MESSAGE:\n")
+		prompt.WriteString(data.wrap(pb.PreviousMessage))
+		prompt.WriteString("\nUse the previous message to understand the author's intent, but rewrite it so it\n")
+		prompt.WriteString("accurately describes the changes below. Keep ticket numbers and trailers it references.\n")
+	}
+
 	// Determine language(s) to use
 	language := pb.Language
 	if language == "" {
@@ -183,8 +195,8 @@ func (pb *PromptBuilder) Build() string {
 	if len(diff) > 2000 {
 		diff = diff[:2000] + "\n... (truncated)"
 	}
-	prompt.WriteString(diff)
-	prompt.WriteString("\n\n")
+	prompt.WriteString(data.wrap(diff))
+	prompt.WriteString("\n")
 
 	// Requirements - different based on detailed mode
 	prompt.WriteString("REQUIREMENTS:\n")
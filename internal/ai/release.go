@@ -0,0 +1,119 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReleaseNotesPromptBuilder constructs prompts that turn a rendered changelog
+// into release notes, e.g. for an annotated tag message
+type ReleaseNotesPromptBuilder struct {
+	Version   string // Version being released, e.g. "v1.3.0"
+	Changelog string // Rendered changelog of the release
+	Language  string // Output language code (default "en")
+}
+
+// Build constructs the prompt
+func (rb *ReleaseNotesPromptBuilder) Build() string {
+	var prompt strings.Builder
+
+	prompt.WriteString("You are a technical writer preparing the release notes of a software release.\n\n")
+
+	// The changelog is rendered from commit messages and is fenced like diffs
+	data := newFence(rb.Changelog)
+	prompt.WriteString(data.rules())
+
+	prompt.WriteString(fmt.Sprintf("VERSION: %s\n\n", rb.Version))
+	prompt.WriteString("CHANGELOG:\n")
+	prompt.WriteString(data.wrap(strings.TrimSpace(rb.Changelog)))
+	prompt.WriteString("\n")
+
+	prompt.WriteString("TASK:\n")
+	prompt.WriteString("Write release notes for this version. Start with one or two sentences that\n")
+	prompt.WriteString("summarize the release, then list the notable changes grouped under short\n")
+	prompt.WriteString("headings. Call out breaking changes first. Only mention changes that appear\n")
+	prompt.WriteString("in the changelog; do not invent features.\n\n")
+
+	prompt.WriteString(fmt.Sprintf("Write in %s.\n\n", languageName(rb.Language)))
+
+	prompt.WriteString("OUTPUT FORMAT:\n")
+	prompt.WriteString("Output ONLY the release notes as plain Markdown, without a title line and without\n")
+	prompt.WriteString("commit hashes. Keep lines under 72 characters.\n")
+
+	return prompt.String()
+}
+
+// ReleaseHighlightsPromptBuilder constructs prompts that rewrite the changes
+// of a release for its users, in one language
+type ReleaseHighlightsPromptBuilder struct {
+	Version  string   // Version being released
+	Changes  []string // User-facing changelog entries
+	Breaking []string // Breaking change notes
+	Language string   // Output language code (default "en")
+}
+
+// Build constructs the prompt
+func (hb *ReleaseHighlightsPromptBuilder) Build() string {
+	var prompt strings.Builder
+
+	prompt.WriteString("You are a technical writer preparing the release notes of a software release\n")
+	prompt.WriteString("for its users, who do not read the code.\n\n")
+
+	// Changes come from commit messages and are fenced like diffs
+	data := newFence(strings.Join(hb.Changes, "\n"), strings.Join(hb.Breaking, "\n"))
+	prompt.WriteString(data.rules())
+
+	prompt.WriteString(fmt.Sprintf("VERSION: %s\n\n", hb.Version))
+	prompt.WriteString("CHANGES:\n")
+	prompt.WriteString(data.wrapList(hb.Changes))
+	prompt.WriteString("\n")
+	if len(hb.Breaking) > 0 {
+		prompt.WriteString("BREAKING CHANGES:\n")
+		prompt.WriteString(data.wrapList(hb.Breaking))
+		prompt.WriteString("\n")
+	}
+
+	prompt.WriteString("TASK:\n")
+	prompt.WriteString("1. Summarize the release in one or two sentences.\n")
+	prompt.WriteString("2. List the highlights: what users can now do or no longer run into. Merge\n")
+	prompt.WriteString("   related changes, leave out internal ones and avoid jargon.\n")
+	if len(hb.Breaking) > 0 {
+		prompt.WriteString("3. For each breaking change, tell users what they must do when upgrading.\n")
+	}
+	prompt.WriteString("Only mention changes listed above; do not invent features.\n")
+	prompt.WriteString(fmt.Sprintf("Write in %s, but keep the labels below in English.\n\n", languageName(hb.Language)))
+
+	prompt.WriteString("OUTPUT FORMAT:\n")
+	prompt.WriteString("SUMMARY: <summary>\n")
+	prompt.WriteString("HIGHLIGHTS:\n- <highlight>\n")
+	if len(hb.Breaking) > 0 {
+		prompt.WriteString("UPGRADE:\n- <upgrade note>\n")
+	}
+	prompt.WriteString("\nOutput nothing else.\n")
+
+	return prompt.String()
+}
+
+// ReleaseHighlights are the parts of release notes written by the model
+type ReleaseHighlights struct {
+	Summary    string
+	Highlights []string
+	Upgrade    []string
+}
+
+// ParseReleaseHighlights extracts the summary, highlights and upgrade notes
+// from a ReleaseHighlightsPromptBuilder response. It fails if the response
+// has no highlights.
+func ParseReleaseHighlights(response string) (ReleaseHighlights, error) {
+	sections := parseSections(response, "SUMMARY", "HIGHLIGHTS", "UPGRADE", "UPGRADE NOTES")
+	result := ReleaseHighlights{
+		Summary:    strings.Join(sections["SUMMARY"], " "),
+		Highlights: sections["HIGHLIGHTS"],
+		Upgrade:    append(sections["UPGRADE"], sections["UPGRADE NOTES"]...),
+	}
+
+	if len(result.Highlights) == 0 {
+		return ReleaseHighlights{}, fmt.Errorf("no highlights in response")
+	}
+	return result, nil
+}
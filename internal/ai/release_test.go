@@ -0,0 +1,52 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseReleaseHighlights(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     ReleaseHighlights
+		wantErr  bool
+	}{
+		{
+			name:     "labels",
+			response: "SUMMARY: Faster exports\nand fewer crashes.\nHIGHLIGHTS:\n- Export to CSV\n* No crash on empty profiles\nUPGRADE:\n- Use the /v2 routes",
+			want: ReleaseHighlights{
+				Summary:    "Faster exports and fewer crashes.",
+				Highlights: []string{"Export to CSV", "No crash on empty profiles"},
+				Upgrade:    []string{"Use the /v2 routes"},
+			},
+		},
+		{
+			name:     "markdown labels in a fence",
+			response: "```\n**SUMMARY:** 更快的导出。\n## Highlights\n- 支持 CSV 导出\n```",
+			want: ReleaseHighlights{
+				Summary:    "更快的导出。",
+				Highlights: []string{"支持 CSV 导出"},
+			},
+		},
+		{
+			name:     "no highlights",
+			response: "SUMMARY: Nothing to see.",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseReleaseHighlights(tt.response)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseReleaseHighlights() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Summary != tt.want.Summary ||
+				strings.Join(got.Highlights, "|") != strings.Join(tt.want.Highlights, "|") ||
+				strings.Join(got.Upgrade, "|") != strings.Join(tt.want.Upgrade, "|") {
+				t.Errorf("ParseReleaseHighlights() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExplainPromptBuilder constructs prompts that explain an existing commit
+type ExplainPromptBuilder struct {
+	Message   string            // Full commit message
+	Areas     []string          // Areas of the code base the commit touches
+	DiffStats string            // Stats of the commit
+	Analysis  *DiffAnalysisInfo // Analysis of the diff (optional)
+	Diff      string            // Diff, already cut to the diff budget
+	Language  string            // Output language code (default "en")
+}
+
+// Explanation is the model's account of a commit
+type Explanation struct {
+	Summary string   `json:"summary"`
+	Risks   []string `json:"risks"`
+	Areas   []string `json:"areas"`
+}
+
+// ReviewPromptBuilder constructs prompts that review staged changes
+type ReviewPromptBuilder struct {
+	DiffStats    string            // Stats of the staged changes
+	Analysis     *DiffAnalysisInfo // Analysis of the diff (optional)
+	Diff         string            // Diff, already cut to the diff budget
+	Language     string            // Output language code (default "en")
+	CustomPrompt string            // Company/team guidelines
+}
+
+// Review is a short structured code review
+type Review struct {
+	Bugs           []string `json:"bugs"`
+	MissingTests   []string `json:"missing_tests"`
+	Secrets        []string `json:"secrets"`
+	DebugLeftovers []string `json:"debug_leftovers"`
+}
+
+// Findings returns the number of issues in the review
+func (r Review) Findings() int {
+	return len(r.Bugs) + len(r.MissingTests) + len(r.Secrets) + len(r.DebugLeftovers)
+}
+
+// Build constructs the prompt
+func (eb *ExplainPromptBuilder) Build() string {
+	var prompt strings.Builder
+
+	prompt.WriteString("You are a senior engineer explaining a commit to a colleague who is new to\n")
+	prompt.WriteString("the code base.\n\n")
+
+	// The message and the diff come from the repository
+	data := newFence(eb.Message, eb.Diff)
+	prompt.WriteString(data.rules())
+
+	prompt.WriteString("COMMIT MESSAGE:\n")
+	prompt.WriteString(data.wrap(strings.TrimSpace(eb.Message)))
+	prompt.WriteString("\n")
+
+	if len(eb.Areas) > 0 {
+		prompt.WriteString("CHANGED AREAS:\n")
+		for _, area := range eb.Areas {
+			prompt.WriteString(fmt.Sprintf("- %s\n", area))
+		}
+		prompt.WriteString("\n")
+	}
+	writeDiffContext(&prompt, data, eb.DiffStats, eb.Analysis, eb.Diff)
+
+	prompt.WriteString("TASK:\n")
+	prompt.WriteString("1. Explain what the commit does and why, based on the diff. Say so if the\n")
+	prompt.WriteString("   message does not match the changes.\n")
+	prompt.WriteString("2. List the risks: behavior that could break, edge cases, compatibility,\n")
+	prompt.WriteString("   performance or security concerns.\n")
+	prompt.WriteString("3. List the affected areas and how each is affected.\n")
+	prompt.WriteString(fmt.Sprintf("Write in %s, but keep the labels below in English.\n\n", languageName(eb.Language)))
+
+	prompt.WriteString("OUTPUT FORMAT:\n")
+	prompt.WriteString("SUMMARY: <2-4 sentences>\n")
+	prompt.WriteString("RISKS:\n- <risk> (or \"- None\")\n")
+	prompt.WriteString("AREAS:\n- <area>: <impact>\n\n")
+	prompt.WriteString("Output nothing else.\n")
+
+	return prompt.String()
+}
+
+// Build constructs the prompt
+func (rb *ReviewPromptBuilder) Build() string {
+	var prompt strings.Builder
+
+	prompt.WriteString("You are a careful code reviewer checking changes before they are committed.\n\n")
+
+	if rb.CustomPrompt != "" {
+		prompt.WriteString("COMPANY/TEAM GUIDELINES:\n")
+		prompt.WriteString(rb.CustomPrompt)
+		prompt.WriteString("\n\n")
+	}
+	data := newFence(rb.Diff)
+	if rb.Diff != "" {
+		prompt.WriteString(data.rules())
+	}
+	writeDiffContext(&prompt, data, rb.DiffStats, rb.Analysis, rb.Diff)
+
+	prompt.WriteString("TASK:\n")
+	prompt.WriteString("Review ONLY the added and changed lines. Report:\n")
+	prompt.WriteString("- BUGS: likely bugs such as wrong conditions, unhandled errors, nil/null access,\n")
+	prompt.WriteString("  off-by-one errors or resource leaks\n")
+	prompt.WriteString("- TESTS: changed behavior that has no test changes\n")
+	prompt.WriteString("- SECRETS: hard-coded passwords, tokens, keys or credentials\n")
+	prompt.WriteString("- DEBUG: debug leftovers such as print statements, commented-out code, TODOs\n")
+	prompt.WriteString("  added by this change or disabled checks\n")
+	prompt.WriteString("Be specific: name the file and, if possible, the line. Do not report style\n")
+	prompt.WriteString("preferences. If there is nothing to report for a category, write \"- None\".\n")
+	prompt.WriteString(fmt.Sprintf("Write in %s, but keep the labels below in English.\n\n", languageName(rb.Language)))
+
+	prompt.WriteString("OUTPUT FORMAT:\n")
+	prompt.WriteString("BUGS:\n- <file>: <issue>\n")
+	prompt.WriteString("TESTS:\n- <file>: <missing test>\n")
+	prompt.WriteString("SECRETS:\n- <file>: <secret>\n")
+	prompt.WriteString("DEBUG:\n- <file>: <leftover>\n\n")
+	prompt.WriteString("Output nothing else.\n")
+
+	return prompt.String()
+}
+
+// writeDiffContext writes the stats, analysis and diff of a change, with the
+// diff inside data
+func writeDiffContext(prompt *strings.Builder, data fence, diffStats string, analysis *DiffAnalysisInfo, diff string) {
+	if diffStats != "" {
+		prompt.WriteString("CHANGES SUMMARY:\n")
+		prompt.WriteString(strings.TrimRight(diffStats, "\n"))
+		prompt.WriteString("\n\n")
+	}
+
+	if analysis != nil {
+		prompt.WriteString(fmt.Sprintf("Complexity: %s | Files: %d | +%d/-%d lines\n",
+			analysis.ChangeComplexity, analysis.TotalFiles,
+			analysis.TotalAdditions, analysis.TotalDeletions))
+		if len(analysis.KeyChanges) > 0 {
+			prompt.WriteString("Key code changes:\n")
+			for _, change := range analysis.KeyChanges {
+				prompt.WriteString(fmt.Sprintf("  - %s\n", change))
+			}
+		}
+		prompt.WriteString("\n")
+	}
+
+	if diff != "" {
+		prompt.WriteString("DIFF:\n")
+		prompt.WriteString(data.wrap(diff))
+		prompt.WriteString("\n")
+	}
+}
+
+// ParseExplanation extracts the summary, risks and areas from an
+// ExplainPromptBuilder response. It fails if the response has no summary.
+func ParseExplanation(response string) (Explanation, error) {
+	sections := parseSections(response, "SUMMARY", "RISKS", "AREAS")
+	explanation := Explanation{
+		Summary: strings.Join(sections["SUMMARY"], " "),
+		Risks:   nonEmpty(sections["RISKS"]),
+		Areas:   nonEmpty(sections["AREAS"]),
+	}
+
+	if explanation.Summary == "" {
+		return Explanation{}, fmt.Errorf("no summary in response")
+	}
+	return explanation, nil
+}
+
+// ParseReview extracts the findings from a ReviewPromptBuilder response. It
+// fails if the response has none of the expected sections.
+func ParseReview(response string) (Review, error) {
+	sections := parseSections(response, "BUGS", "TESTS", "SECRETS", "DEBUG")
+	if len(sections) == 0 {
+		return Review{}, fmt.Errorf("no review sections in response")
+	}
+
+	return Review{
+		Bugs:           nonEmpty(sections["BUGS"]),
+		MissingTests:   nonEmpty(sections["TESTS"]),
+		Secrets:        nonEmpty(sections["SECRETS"]),
+		DebugLeftovers: nonEmpty(sections["DEBUG"]),
+	}, nil
+}
+
+// parseSections splits a response into the lines under each of labels. A
+// label starts a line ("RISKS:" or "RISKS: text"), may be a Markdown heading
+// or bold, and is matched case-insensitively. List markers are removed.
+// Only labels found in the response are present in the result.
+func parseSections(response string, labels ...string) map[string][]string {
+	known := make(map[string]bool)
+	for _, label := range labels {
+		known[label] = true
+	}
+
+	sections := make(map[string][]string)
+	current := ""
+	for _, line := range strings.Split(stripCodeFence(response), "\n") {
+		line = strings.TrimSpace(line)
+
+		label, rest, _ := strings.Cut(strings.TrimLeft(line, "#* "), ":")
+		if key := strings.ToUpper(strings.Trim(label, "* ")); known[key] {
+			current = key
+			sections[current] = []string{}
+			line = strings.TrimSpace(strings.Trim(rest, "* "))
+		} else if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") {
+			line = strings.TrimSpace(line[2:])
+		}
+
+		if current != "" && line != "" {
+			sections[current] = append(sections[current], line)
+		}
+	}
+	return sections
+}
+
+// nonEmpty drops the "None" placeholders the model writes for empty sections
+func nonEmpty(items []string) []string {
+	result := []string{}
+	for _, item := range items {
+		switch strings.ToLower(strings.TrimRight(item, ".")) {
+		case "none", "n/a", "nothing", "no issues", "no issues found":
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
@@ -0,0 +1,79 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseExplanation(t *testing.T) {
+	response := "SUMMARY: Adds cursor pagination\nto the list endpoint.\nRISKS:\n- Clients relying on offsets break\nAREAS:\n- api: new query parameters\n- db: extra index"
+	got, err := ParseExplanation(response)
+	if err != nil {
+		t.Fatalf("ParseExplanation() error = %v", err)
+	}
+	if got.Summary != "Adds cursor pagination to the list endpoint." {
+		t.Errorf("Summary = %q", got.Summary)
+	}
+	if len(got.Risks) != 1 || len(got.Areas) != 2 || got.Areas[1] != "db: extra index" {
+		t.Errorf("Risks = %q, Areas = %q", got.Risks, got.Areas)
+	}
+
+	if _, err := ParseExplanation("RISKS:\n- None"); err == nil {
+		t.Error("ParseExplanation() accepted a response without summary")
+	}
+}
+
+func TestParseReview(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     []int // Bugs, MissingTests, Secrets, DebugLeftovers
+		wantErr  bool
+	}{
+		{
+			name:     "findings",
+			response: "BUGS:\n- user.go: nil check missing\nTESTS:\n- None\nSECRETS:\n- config.go: AWS key\nDEBUG:\n- main.go: fmt.Println left in\n* main.go: TODO added",
+			want:     []int{1, 0, 1, 2},
+		},
+		{
+			name:     "markdown headings, nothing found",
+			response: "```\n## Bugs\nNone.\n## Tests\n- none\n**Secrets:** None\n### DEBUG:\nN/A\n```",
+			want:     []int{0, 0, 0, 0},
+		},
+		{
+			name:     "unstructured",
+			response: "Looks fine to me!",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseReview(tt.response)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseReview() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			counts := []int{len(got.Bugs), len(got.MissingTests), len(got.Secrets), len(got.DebugLeftovers)}
+			for i := range counts {
+				if counts[i] != tt.want[i] {
+					t.Fatalf("ParseReview() = %+v, want counts %v", got, tt.want)
+				}
+			}
+			if got.Findings() != tt.want[0]+tt.want[1]+tt.want[2]+tt.want[3] {
+				t.Errorf("Findings() = %d", got.Findings())
+			}
+		})
+	}
+}
+
+func TestReviewPromptBuilder(t *testing.T) {
+	prompt := (&ReviewPromptBuilder{Diff: "+password = \"hunter2\"", Language: "zh", CustomPrompt: "No panics"}).Build()
+	for _, want := range []string{"No panics", "+password", "Chinese", "SECRETS:"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("review prompt missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SquashPromptBuilder constructs prompts that consolidate the commits of a
+// branch into a single Conventional Commit message
+type SquashPromptBuilder struct {
+	CommitType    string
+	Scope         string
+	Commits       []string          // Commit messages, oldest first
+	Breaking      []string          // Breaking change notes of the commits
+	Tickets       []string          // Tickets the commits reference
+	DiffStats     string            // Stats of the net diff
+	Analysis      *DiffAnalysisInfo // Analysis of the net diff (optional)
+	Diff          string            // Net diff, already cut to the diff budget
+	Language      string            // Output language code (default "en")
+	SubjectLength string            // "short" (36 chars) or "normal" (72 chars)
+	CustomPrompt  string            // Company/team commit guidelines
+}
+
+// Build constructs the prompt
+func (sb *SquashPromptBuilder) Build() string {
+	var prompt strings.Builder
+
+	prompt.WriteString("You are a Git commit message generator expert.\n\n")
+
+	if sb.CustomPrompt != "" {
+		prompt.WriteString("COMPANY/TEAM COMMIT GUIDELINES:\n")
+		prompt.WriteString(sb.CustomPrompt)
+		prompt.WriteString("\n\n")
+	}
+
+	var commits strings.Builder
+	for _, commit := range sb.Commits {
+		lines := strings.Split(strings.TrimSpace(commit), "\n")
+		commits.WriteString(fmt.Sprintf("- %s\n", lines[0]))
+		for _, line := range lines[1:] {
+			if line = strings.TrimSpace(line); line != "" {
+				commits.WriteString(fmt.Sprintf("    %s\n", line))
+			}
+		}
+	}
+
+	// Commit messages and the diff come from the repository
+	data := newFence(commits.String(), strings.Join(sb.Breaking, "\n"), sb.Diff)
+	prompt.WriteString(data.rules())
+
+	prompt.WriteString(fmt.Sprintf("SQUASHED COMMITS (%d, oldest first):\n", len(sb.Commits)))
+	prompt.WriteString(data.wrap(commits.String()))
+	prompt.WriteString("\n")
+
+	if len(sb.Breaking) > 0 {
+		prompt.WriteString("BREAKING CHANGES:\n")
+		prompt.WriteString(data.wrapList(sb.Breaking))
+		prompt.WriteString("\n")
+	}
+
+	if sb.DiffStats != "" {
+		prompt.WriteString("NET CHANGES SUMMARY:\n")
+		prompt.WriteString(strings.TrimRight(sb.DiffStats, "\n"))
+		prompt.WriteString("\n\n")
+	}
+
+	if sb.Analysis != nil {
+		prompt.WriteString(fmt.Sprintf("Complexity: %s | Files: %d | +%d/-%d lines\n",
+			sb.Analysis.ChangeComplexity, sb.Analysis.TotalFiles,
+			sb.Analysis.TotalAdditions, sb.Analysis.TotalDeletions))
+		if len(sb.Analysis.KeyChanges) > 0 {
+			prompt.WriteString("Key code changes:\n")
+			for _, change := range sb.Analysis.KeyChanges {
+				prompt.WriteString(fmt.Sprintf("  - %s\n", change))
+			}
+		}
+		prompt.WriteString("\n")
+	}
+
+	if sb.Diff != "" {
+		prompt.WriteString("NET CHANGES (diff):\n")
+		prompt.WriteString(data.wrap(sb.Diff))
+		prompt.WriteString("\n")
+	}
+
+	prompt.WriteString("TASK:\n")
+	prompt.WriteString(fmt.Sprintf("Write ONE %s commit message that replaces all of the commits above.\n", sb.CommitType))
+	prompt.WriteString("Describe the net result of the branch, not its history: ignore work-in-progress,\n")
+	prompt.WriteString("fixup and review commits, and do not list the commits one by one.\n")
+	if sb.Scope != "" {
+		prompt.WriteString(fmt.Sprintf("Scope: %s\n", sb.Scope))
+	}
+	if len(sb.Tickets) > 0 {
+		prompt.WriteString(fmt.Sprintf("Mention these tickets where relevant: %s\n", strings.Join(sb.Tickets, ", ")))
+	}
+	if len(sb.Breaking) > 0 {
+		prompt.WriteString("Mark the subject as breaking with \"!\" after the type/scope.\n")
+	}
+	prompt.WriteString(fmt.Sprintf("Write in %s.\n\n", languageName(sb.Language)))
+
+	maxLength := 72
+	if sb.SubjectLength == "short" {
+		maxLength = 36
+	}
+	prompt.WriteString("REQUIREMENTS:\n")
+	prompt.WriteString("1. Follow Conventional Commits format\n")
+	prompt.WriteString(fmt.Sprintf("2. Subject line: concise summary (max %d characters)\n", maxLength))
+	prompt.WriteString("3. Body: explain WHAT changed and WHY (2-5 bullet points)\n")
+	prompt.WriteString("4. Do not add trailers such as Signed-off-by or Co-authored-by; they are added separately\n\n")
+
+	format := sb.CommitType
+	if sb.Scope != "" {
+		format += "(" + sb.Scope + ")"
+	}
+	prompt.WriteString("OUTPUT FORMAT:\n")
+	prompt.WriteString(format + ": <subject line>\n\n<body with bullet points>\n\n")
+	prompt.WriteString("Generate the commit message now (subject + body):\n")
+
+	return prompt.String()
+}
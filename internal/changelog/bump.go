@@ -0,0 +1,53 @@
+package changelog
+
+import (
+	"github.com/xyue92/gitai/internal/git"
+	"github.com/xyue92/gitai/internal/semver"
+)
+
+// BumpSummary counts the commits that decided a version bump
+type BumpSummary struct {
+	Breaking int
+	Features int
+	Fixes    int // fix, perf and revert commits
+	Other    int // Commits that do not affect the version
+}
+
+// BumpLevel returns the version bump implied by commits: major for breaking
+// changes, minor for features and patch for fixes, performance improvements
+// and reverts. Other types and non-conventional commits do not bump.
+func BumpLevel(commits []git.Commit) (semver.Level, BumpSummary) {
+	level := semver.None
+	var summary BumpSummary
+
+	for _, commit := range commits {
+		if commit.IsMerge() {
+			continue
+		}
+
+		parsed, ok := git.ParseConventionalMessage(commit.Message())
+		switch {
+		case ok && parsed.IsBreaking():
+			summary.Breaking++
+			level = maxLevel(level, semver.Major)
+		case ok && parsed.Type == "feat":
+			summary.Features++
+			level = maxLevel(level, semver.Minor)
+		case ok && (parsed.Type == "fix" || parsed.Type == "perf" || parsed.Type == "revert"):
+			summary.Fixes++
+			level = maxLevel(level, semver.Patch)
+		default:
+			summary.Other++
+		}
+	}
+
+	return level, summary
+}
+
+// maxLevel returns the larger of two levels
+func maxLevel(a, b semver.Level) semver.Level {
+	if b > a {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,48 @@
+package changelog
+
+import (
+	"testing"
+
+	"github.com/xyue92/gitai/internal/git"
+	"github.com/xyue92/gitai/internal/semver"
+)
+
+func TestBumpLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		subjects []string
+		want     semver.Level
+	}{
+		{"empty", nil, semver.None},
+		{"chores only", []string{"docs: update README", "chore: bump deps", "wip"}, semver.None},
+		{"fix", []string{"docs: update README", "fix: handle nil user"}, semver.Patch},
+		{"perf", []string{"perf(db): cache queries"}, semver.Patch},
+		{"feat", []string{"fix: handle nil user", "feat(api): add pagination"}, semver.Minor},
+		{"breaking", []string{"feat: add export", "refactor(api)!: drop v1 endpoints"}, semver.Major},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var commits []git.Commit
+			for _, subject := range tt.subjects {
+				commits = append(commits, git.Commit{Subject: subject})
+			}
+			if got, _ := BumpLevel(commits); got != tt.want {
+				t.Errorf("BumpLevel() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBumpLevelSummary(t *testing.T) {
+	level, summary := BumpLevel(testCommits)
+	if level != semver.Major {
+		t.Errorf("BumpLevel() = %s, want major", level)
+	}
+
+	// The merge commit is skipped
+	want := BumpSummary{Breaking: 1, Features: 2, Fixes: 1, Other: 2}
+	if summary != want {
+		t.Errorf("BumpLevel() summary = %+v, want %+v", summary, want)
+	}
+}
@@ -0,0 +1,222 @@
+package changelog
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xyue92/gitai/internal/git"
+)
+
+// Supported output formats
+const (
+	FormatKeepAChangelog = "keepachangelog" // https://keepachangelog.com sections (Added, Fixed, ...)
+	FormatMarkdown       = "markdown"       // One section per commit type (Features, Bug Fixes, ...)
+)
+
+// otherSection collects commits without a section of their own (--all)
+const otherSection = "Other"
+
+// Ticket is a ticket or issue referenced by a commit
+type Ticket struct {
+	ID  string
+	URL string // Empty when no ticket_url is configured
+}
+
+// Entry is a single change listed in a release
+type Entry struct {
+	Type        string
+	Scope       string
+	Description string
+	Hash        string // Abbreviated commit hash
+	Author      string
+	Tickets     []Ticket
+	Breaking    bool
+}
+
+// Section groups entries under a heading such as "Added" or "Bug Fixes"
+type Section struct {
+	Title   string
+	Summary string // Optional AI-written summary
+	Entries []Entry
+}
+
+// Release is the changelog of one version
+type Release struct {
+	Version  string    // "Unreleased" or a version such as "1.2.0"
+	Date     time.Time // Zero for unreleased changes
+	Breaking []Entry   // Breaking changes, Description holds the breaking note
+	Sections []Section
+}
+
+// Options controls how commits are turned into a release
+type Options struct {
+	Version       string
+	Date          time.Time
+	Format        string            // FormatKeepAChangelog (default) or FormatMarkdown
+	Sections      map[string]string // Commit type → section title overrides
+	IncludeAll    bool              // Include every type and non-conventional commits
+	TicketPattern string            // Ticket pattern (config ticket_pattern)
+	TicketPrefix  string            // Ticket key (config ticket_prefix), used without a pattern
+	TicketURL     string            // Link template with a {ticket} placeholder
+}
+
+// sectionLayout maps commit types to section titles and orders the sections
+type sectionLayout struct {
+	titles   map[string]string
+	order    []string
+	optional map[string]bool // Types only included with IncludeAll
+}
+
+// layouts holds the built-in section layout of each format
+var layouts = map[string]sectionLayout{
+	FormatKeepAChangelog: {
+		titles: map[string]string{
+			"feat": "Added", "fix": "Fixed", "perf": "Changed", "refactor": "Changed",
+			"revert": "Changed", "security": "Security", "deprecate": "Deprecated", "remove": "Removed",
+			"docs": "Documentation", "style": "Maintenance", "test": "Maintenance",
+			"build": "Maintenance", "ci": "Maintenance", "chore": "Maintenance",
+		},
+		order:    []string{"Added", "Changed", "Deprecated", "Removed", "Fixed", "Security", "Documentation", "Maintenance"},
+		optional: map[string]bool{"docs": true, "style": true, "test": true, "build": true, "ci": true, "chore": true},
+	},
+	FormatMarkdown: {
+		titles: map[string]string{
+			"feat": "Features", "fix": "Bug Fixes", "perf": "Performance Improvements",
+			"refactor": "Code Refactoring", "revert": "Reverts", "docs": "Documentation",
+			"style": "Styles", "test": "Tests", "build": "Build System",
+			"ci": "Continuous Integration", "chore": "Chores",
+		},
+		order: []string{"Features", "Bug Fixes", "Performance Improvements", "Code Refactoring", "Reverts",
+			"Documentation", "Styles", "Tests", "Build System", "Continuous Integration", "Chores"},
+		optional: map[string]bool{"docs": true, "style": true, "test": true, "build": true, "ci": true, "chore": true},
+	},
+}
+
+// Build groups commits into a release. Commits are expected newest first,
+// as returned by git log; entries keep that order within a scope.
+func Build(commits []git.Commit, opts Options) *Release {
+	layout, ok := layouts[opts.Format]
+	if !ok {
+		layout = layouts[FormatKeepAChangelog]
+	}
+
+	release := &Release{Version: opts.Version, Date: opts.Date}
+	if release.Version == "" {
+		release.Version = "Unreleased"
+	}
+
+	sections := make(map[string]*Section)
+	for _, commit := range commits {
+		if commit.IsMerge() {
+			continue
+		}
+
+		parsed, ok := git.ParseConventionalMessage(commit.Message())
+		entry := Entry{
+			Hash:    commit.ShortHash(),
+			Author:  commit.Author,
+			Tickets: tickets(commit.Message(), opts),
+		}
+		if ok {
+			entry.Type = parsed.Type
+			entry.Scope = parsed.Scope
+			entry.Description = parsed.Description
+			entry.Breaking = parsed.IsBreaking()
+		} else {
+			entry.Description = commit.Subject
+		}
+		entry.Description = stripTickets(entry.Description, entry.Tickets)
+
+		if entry.Breaking {
+			note := entry
+			note.Description = stripTickets(parsed.BreakingChange, entry.Tickets)
+			release.Breaking = append(release.Breaking, note)
+		}
+
+		title := sectionTitle(layout, entry.Type, opts)
+		if title == "" {
+			continue
+		}
+		if sections[title] == nil {
+			sections[title] = &Section{Title: title}
+		}
+		sections[title].Entries = append(sections[title].Entries, entry)
+	}
+
+	for _, title := range sectionOrder(layout, sections) {
+		section := sections[title]
+		sort.SliceStable(section.Entries, func(i, j int) bool {
+			return section.Entries[i].Scope < section.Entries[j].Scope
+		})
+		release.Sections = append(release.Sections, *section)
+	}
+
+	return release
+}
+
+// sectionTitle returns the section an entry of commitType belongs to, or ""
+// if it is left out of the changelog
+func sectionTitle(layout sectionLayout, commitType string, opts Options) string {
+	if title, ok := opts.Sections[commitType]; ok {
+		return title
+	}
+	if title, ok := layout.titles[commitType]; ok && (opts.IncludeAll || !layout.optional[commitType]) {
+		return title
+	}
+	if opts.IncludeAll {
+		return otherSection
+	}
+	return ""
+}
+
+// sectionOrder returns the titles present in sections: built-in titles first,
+// then custom titles alphabetically, then "Other"
+func sectionOrder(layout sectionLayout, sections map[string]*Section) []string {
+	var order []string
+	known := make(map[string]bool)
+	for _, title := range layout.order {
+		known[title] = true
+		if sections[title] != nil {
+			order = append(order, title)
+		}
+	}
+
+	var custom []string
+	for title := range sections {
+		if !known[title] && title != otherSection {
+			custom = append(custom, title)
+		}
+	}
+	sort.Strings(custom)
+	order = append(order, custom...)
+
+	if sections[otherSection] != nil {
+		order = append(order, otherSection)
+	}
+	return order
+}
+
+// tickets returns the tickets referenced in a commit message, linked if possible
+func tickets(message string, opts Options) []Ticket {
+	var result []Ticket
+	for _, id := range git.ExtractTickets(message, opts.TicketPattern, opts.TicketPrefix) {
+		ticket := Ticket{ID: id}
+		if opts.TicketURL != "" {
+			ticket.URL = strings.ReplaceAll(opts.TicketURL, "{ticket}", strings.TrimPrefix(id, "#"))
+		}
+		result = append(result, ticket)
+	}
+	return result
+}
+
+// stripTickets removes bracketed ticket references such as "[PROJ-1]" or
+// "(#42)" from a description, since they are rendered as links instead
+func stripTickets(description string, tickets []Ticket) string {
+	for _, ticket := range tickets {
+		pattern := regexp.MustCompile(`\s*[\[(]` + regexp.QuoteMeta(ticket.ID) + `[\])]`)
+		description = pattern.ReplaceAllString(description, "")
+	}
+	return strings.TrimSpace(description)
+}
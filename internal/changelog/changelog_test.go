@@ -0,0 +1,150 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xyue92/gitai/internal/git"
+)
+
+var testCommits = []git.Commit{
+	{Hash: "aaaaaaa1111", Subject: "feat(auth): add OAuth2 login [PROJ-12]"},
+	{Hash: "bbbbbbb2222", Subject: "fix: handle nil user (#42)"},
+	{Hash: "ccccccc3333", Subject: "refactor(api)!: drop v1 endpoints", Body: "BREAKING CHANGE: the /v1 routes are gone"},
+	{Hash: "ddddddd4444", Subject: "docs: update README"},
+	{Hash: "eeeeeee5555", Subject: "wip"},
+	{Hash: "fffffff6666", Subject: "feat: add export", Parents: []string{"a", "b"}},
+	{Hash: "0000000777", Subject: "feat(api): add pagination"},
+}
+
+func TestBuild(t *testing.T) {
+	release := Build(testCommits, Options{Version: "1.2.0", TicketURL: "https://jira.example.com/browse/{ticket}"})
+
+	var titles []string
+	for _, s := range release.Sections {
+		titles = append(titles, s.Title)
+	}
+	if got := strings.Join(titles, ","); got != "Added,Changed,Fixed" {
+		t.Errorf("sections = %s, want Added,Changed,Fixed", got)
+	}
+
+	added := release.Sections[0].Entries
+	if len(added) != 2 || added[0].Scope != "api" || added[1].Scope != "auth" {
+		t.Errorf("Added entries not sorted by scope: %+v", added)
+	}
+	if added[1].Description != "add OAuth2 login" {
+		t.Errorf("ticket not stripped from description: %q", added[1].Description)
+	}
+	if len(added[1].Tickets) != 1 || added[1].Tickets[0].URL != "https://jira.example.com/browse/PROJ-12" {
+		t.Errorf("tickets = %+v", added[1].Tickets)
+	}
+
+	if len(release.Breaking) != 1 || release.Breaking[0].Description != "the /v1 routes are gone" {
+		t.Errorf("breaking = %+v", release.Breaking)
+	}
+}
+
+func TestBuildIncludeAll(t *testing.T) {
+	release := Build(testCommits, Options{Format: FormatMarkdown, IncludeAll: true, Sections: map[string]string{"refactor": "Internals"}})
+
+	var titles []string
+	for _, s := range release.Sections {
+		titles = append(titles, s.Title)
+	}
+	if got := strings.Join(titles, ","); got != "Features,Bug Fixes,Documentation,Internals,Other" {
+		t.Errorf("sections = %s", got)
+	}
+	if release.Version != "Unreleased" {
+		t.Errorf("Version = %q, want Unreleased", release.Version)
+	}
+}
+
+func TestRender(t *testing.T) {
+	release := Build(testCommits, Options{
+		Version:   "1.2.0",
+		Date:      time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+		TicketURL: "https://github.com/o/r/issues/{ticket}",
+	})
+
+	got, err := Render(release, FormatKeepAChangelog, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `## [1.2.0] - 2026-10-18
+
+### ⚠ BREAKING CHANGES
+
+- **api:** the /v1 routes are gone (ccccccc)
+
+### Added
+
+- **api:** add pagination (0000000)
+- **auth:** add OAuth2 login ([PROJ-12](https://github.com/o/r/issues/PROJ-12)) (aaaaaaa)
+
+### Changed
+
+- **api:** drop v1 endpoints (ccccccc)
+
+### Fixed
+
+- handle nil user ([#42](https://github.com/o/r/issues/42)) (bbbbbbb)
+`
+	if got != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+
+	custom, err := Render(release, "", `# {{ .Version }}{{ range .Sections }} {{ .Title }}={{ len .Entries }}{{ end }}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if custom != "# 1.2.0 Added=2 Changed=1 Fixed=1\n" {
+		t.Errorf("custom Render() = %q", custom)
+	}
+}
+
+func TestPrepend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	existing := "# Changelog\n\nIntro.\n\n## [1.0.0] - 2026-01-01\n\n### Added\n\n- first\n"
+	if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	section := "## [1.1.0] - 2026-02-01\n\n### Fixed\n\n- a bug\n"
+	for i := 0; i < 2; i++ {
+		if err := Prepend(path, section, "1.1.0"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, _ := os.ReadFile(path)
+	want := "# Changelog\n\nIntro.\n\n" + section + "\n## [1.0.0] - 2026-01-01\n\n### Added\n\n- first\n"
+	if string(data) != want {
+		t.Errorf("after prepending twice:\n%s\nwant\n%s", data, want)
+	}
+
+	// Regenerating a version replaces its section
+	updated := "## [1.1.0] - 2026-02-01\n\n### Fixed\n\n- a bug\n- another bug\n"
+	if err := Prepend(path, updated, "v1.1.0"); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	if strings.Count(string(data), "## [1.1.0]") != 1 || !strings.Contains(string(data), "another bug") {
+		t.Errorf("section not replaced:\n%s", data)
+	}
+}
+
+func TestPrependNewFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	if err := Prepend(path, "## [Unreleased]\n\n### Added\n\n- x\n", "Unreleased"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "# Changelog\n") || !strings.HasSuffix(string(data), "## [Unreleased]\n\n### Added\n\n- x\n") {
+		t.Errorf("new changelog =\n%s", data)
+	}
+}
@@ -0,0 +1,189 @@
+package changelog
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xyue92/gitai/internal/i18n"
+)
+
+// Release notes output formats
+const (
+	NotesMarkdown = "markdown"
+	NotesText     = "text"
+	NotesJSON     = "json"
+)
+
+// highlightTypes are the commit types users notice, in the order their
+// entries are highlighted
+var highlightTypes = []string{"feat", "fix", "perf", "security", "deprecate", "remove"}
+
+// Contributor is an author of commits in a release
+type Contributor struct {
+	Name    string `json:"name"`
+	Commits int    `json:"commits"`
+}
+
+// Notes are the release notes of one version in one language: what users
+// notice, what they must do to upgrade and who made the release
+type Notes struct {
+	Version      string        `json:"version"`
+	Date         time.Time     `json:"-"`
+	Language     string        `json:"language"`
+	Summary      string        `json:"summary,omitempty"` // Optional AI-written introduction
+	Highlights   []string      `json:"highlights"`
+	Upgrade      []string      `json:"upgrade_notes"`
+	Contributors []Contributor `json:"contributors"`
+}
+
+// notesJSON adds the formatted date to the JSON form of Notes
+type notesJSON struct {
+	*Notes
+	Date string `json:"date,omitempty"`
+}
+
+// BuildNotes derives release notes from a release: user-facing entries become
+// highlights, breaking change notes become upgrade notes, and authorStats
+// (commits per author) lists the contributors
+func BuildNotes(release *Release, authorStats map[string]int, language string) *Notes {
+	notes := &Notes{
+		Version:      release.Version,
+		Date:         release.Date,
+		Language:     language,
+		Highlights:   []string{},
+		Upgrade:      []string{},
+		Contributors: Contributors(authorStats),
+	}
+
+	for _, commitType := range highlightTypes {
+		for _, section := range release.Sections {
+			for _, entry := range section.Entries {
+				if entry.Type == commitType {
+					notes.Highlights = append(notes.Highlights, noteLine(entry))
+				}
+			}
+		}
+	}
+
+	for _, entry := range release.Breaking {
+		notes.Upgrade = append(notes.Upgrade, noteLine(entry))
+	}
+
+	return notes
+}
+
+// noteLine formats an entry as "scope: description"
+func noteLine(entry Entry) string {
+	if entry.Scope != "" {
+		return entry.Scope + ": " + entry.Description
+	}
+	return entry.Description
+}
+
+// Contributors returns the authors of authorStats, most commits first
+func Contributors(authorStats map[string]int) []Contributor {
+	contributors := []Contributor{}
+	for name, commits := range authorStats {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		contributors = append(contributors, Contributor{Name: name, Commits: commits})
+	}
+
+	sort.Slice(contributors, func(i, j int) bool {
+		if contributors[i].Commits != contributors[j].Commits {
+			return contributors[i].Commits > contributors[j].Commits
+		}
+		return contributors[i].Name < contributors[j].Name
+	})
+	return contributors
+}
+
+// RenderNotes renders release notes, one per language, in format. Markdown
+// and text notes are separated by a rule; JSON is always an array.
+func RenderNotes(notes []*Notes, format string) (string, error) {
+	switch format {
+	case "", NotesMarkdown, NotesText:
+	case NotesJSON:
+		items := make([]notesJSON, len(notes))
+		for i, n := range notes {
+			items[i] = notesJSON{Notes: n}
+			if !n.Date.IsZero() {
+				items[i].Date = n.Date.Format("2006-01-02")
+			}
+		}
+		data, err := json.MarshalIndent(items, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data) + "\n", nil
+	default:
+		return "", fmt.Errorf("unknown release notes format %q (expected markdown, text or json)", format)
+	}
+
+	rendered := make([]string, len(notes))
+	for i, n := range notes {
+		rendered[i] = renderNotes(n, format == NotesText)
+	}
+
+	separator := "\n---\n\n"
+	if format == NotesText {
+		separator = "\n" + strings.Repeat("-", 40) + "\n\n"
+	}
+	return strings.Join(rendered, separator), nil
+}
+
+// renderNotes renders one set of notes as Markdown or, with plain, as text
+func renderNotes(notes *Notes, plain bool) string {
+	headings := i18n.GetNotesHeadings(notes.Language)
+
+	var b strings.Builder
+	heading := func(level int, title string) {
+		if plain {
+			underline := "="
+			if level > 1 {
+				underline = "-"
+			}
+			b.WriteString(title + "\n" + strings.Repeat(underline, len([]rune(title))) + "\n\n")
+			return
+		}
+		b.WriteString(strings.Repeat("#", level) + " " + title + "\n\n")
+	}
+	list := func(items []string) {
+		for _, item := range items {
+			b.WriteString("- " + item + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	title := notes.Version
+	if !notes.Date.IsZero() {
+		title += " (" + notes.Date.Format("2006-01-02") + ")"
+	}
+	heading(1, title)
+
+	if notes.Summary != "" {
+		b.WriteString(strings.TrimSpace(notes.Summary) + "\n\n")
+	}
+	if len(notes.Upgrade) > 0 {
+		heading(2, headings.Upgrade)
+		list(notes.Upgrade)
+	}
+	if len(notes.Highlights) > 0 {
+		heading(2, headings.Highlights)
+		list(notes.Highlights)
+	}
+	if len(notes.Contributors) > 0 {
+		heading(2, headings.Contributors)
+		names := make([]string, len(notes.Contributors))
+		for i, contributor := range notes.Contributors {
+			names[i] = fmt.Sprintf("%s (%d)", contributor.Name, contributor.Commits)
+		}
+		list(names)
+	}
+
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
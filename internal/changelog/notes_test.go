@@ -0,0 +1,80 @@
+package changelog
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildNotes(t *testing.T) {
+	release := Build(testCommits, Options{Version: "1.2.0", Format: FormatMarkdown})
+	notes := BuildNotes(release, map[string]int{"bob": 1, "alice": 3, "carol": 1, "": 2}, "en")
+
+	wantHighlights := []string{"api: add pagination", "auth: add OAuth2 login", "handle nil user"}
+	if strings.Join(notes.Highlights, "|") != strings.Join(wantHighlights, "|") {
+		t.Errorf("Highlights = %q, want %q", notes.Highlights, wantHighlights)
+	}
+	if len(notes.Upgrade) != 1 || notes.Upgrade[0] != "api: the /v1 routes are gone" {
+		t.Errorf("Upgrade = %q", notes.Upgrade)
+	}
+
+	var names []string
+	for _, c := range notes.Contributors {
+		names = append(names, c.Name)
+	}
+	if got := strings.Join(names, ","); got != "alice,bob,carol" {
+		t.Errorf("Contributors = %s, want alice,bob,carol", got)
+	}
+}
+
+func TestRenderNotes(t *testing.T) {
+	notes := &Notes{
+		Version:      "v1.2.0",
+		Date:         time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		Language:     "en",
+		Summary:      "Faster and safer.",
+		Highlights:   []string{"Pagination for the API"},
+		Upgrade:      []string{"Move to the /v2 routes"},
+		Contributors: []Contributor{{Name: "alice", Commits: 3}},
+	}
+
+	markdown, err := RenderNotes([]*Notes{notes}, NotesMarkdown)
+	if err != nil {
+		t.Fatalf("RenderNotes() error = %v", err)
+	}
+	for _, want := range []string{"# v1.2.0 (2026-03-01)\n\nFaster and safer.", "## Upgrade notes\n\n- Move to the /v2 routes", "## Highlights", "- alice (3)"} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("markdown notes missing %q:\n%s", want, markdown)
+		}
+	}
+	if strings.Index(markdown, "Upgrade notes") > strings.Index(markdown, "Highlights") {
+		t.Errorf("upgrade notes should come before the highlights:\n%s", markdown)
+	}
+
+	localized := *notes
+	localized.Language = "zh"
+	text, err := RenderNotes([]*Notes{notes, &localized}, NotesText)
+	if err != nil {
+		t.Fatalf("RenderNotes() error = %v", err)
+	}
+	if strings.Contains(text, "#") || !strings.Contains(text, "Highlights\n----------") || !strings.Contains(text, "亮点\n--") {
+		t.Errorf("unexpected text notes:\n%s", text)
+	}
+
+	data, err := RenderNotes([]*Notes{notes}, NotesJSON)
+	if err != nil {
+		t.Fatalf("RenderNotes() error = %v", err)
+	}
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, data)
+	}
+	if len(decoded) != 1 || decoded[0]["date"] != "2026-03-01" || decoded[0]["version"] != "v1.2.0" {
+		t.Errorf("unexpected JSON notes: %s", data)
+	}
+
+	if _, err := RenderNotes([]*Notes{notes}, "html"); err == nil {
+		t.Error("RenderNotes() accepted an unknown format")
+	}
+}
@@ -0,0 +1,162 @@
+package changelog
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// defaultHeader starts a new changelog file
+const defaultHeader = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+`
+
+// keepAChangelogTemplate renders a release as a Keep a Changelog section
+const keepAChangelogTemplate = `## [{{ .Version }}]{{ if not .Date.IsZero }} - {{ .Date.Format "2006-01-02" }}{{ end }}
+{{- if .Breaking }}
+
+### ⚠ BREAKING CHANGES
+{{ range .Breaking }}
+- {{ template "entry" . }}
+{{- end }}
+{{- end }}
+{{- range .Sections }}
+
+### {{ .Title }}
+{{- if .Summary }}
+
+{{ .Summary }}
+{{- end }}
+{{ range .Entries }}
+- {{ template "entry" . }}
+{{- end }}
+{{- end }}
+`
+
+// markdownTemplate renders a release in the conventional-changelog style
+const markdownTemplate = `## {{ .Version }}{{ if not .Date.IsZero }} ({{ .Date.Format "2006-01-02" }}){{ end }}
+{{- if .Breaking }}
+
+### ⚠ BREAKING CHANGES
+{{ range .Breaking }}
+* {{ template "entry" . }}
+{{- end }}
+{{- end }}
+{{- range .Sections }}
+
+### {{ .Title }}
+{{- if .Summary }}
+
+{{ .Summary }}
+{{- end }}
+{{ range .Entries }}
+* {{ template "entry" . }}
+{{- end }}
+{{- end }}
+`
+
+// entryTemplate renders a single entry; custom templates can use it as {{ template "entry" . }}
+const entryTemplate = `{{ define "entry" }}{{ if .Scope }}**{{ .Scope }}:** {{ end }}{{ .Description }}
+{{- range .Tickets }} ({{ if .URL }}[{{ .ID }}]({{ .URL }}){{ else }}{{ .ID }}{{ end }}){{ end }} ({{ .Hash }}){{ end }}`
+
+// Render renders a release with the built-in template of format, or with a
+// custom text/template when templateText is not empty
+func Render(release *Release, format, templateText string) (string, error) {
+	if templateText == "" {
+		templateText = keepAChangelogTemplate
+		if format == FormatMarkdown {
+			templateText = markdownTemplate
+		}
+	}
+
+	tmpl, err := template.New("changelog").Parse(entryTemplate)
+	if err == nil {
+		tmpl, err = tmpl.Parse(templateText)
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid changelog template: %w", err)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, release); err != nil {
+		return "", fmt.Errorf("failed to render changelog: %w", err)
+	}
+
+	return strings.TrimSpace(b.String()) + "\n", nil
+}
+
+// Prepend writes a rendered release section into a changelog file. If the file
+// already has a section for version it is replaced, so running it again for
+// the same version gives the same file. New sections go above the newest one.
+func Prepend(path, section, version string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	content := string(data)
+	if strings.TrimSpace(content) == "" {
+		content = defaultHeader
+	}
+
+	if err := os.WriteFile(path, []byte(insertSection(content, section, version)), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// insertSection replaces the section of version in content, or inserts the
+// section before the first release heading
+func insertSection(content, section, version string) string {
+	lines := strings.Split(content, "\n")
+	section = strings.TrimRight(section, "\n")
+
+	first, start, end := -1, -1, len(lines)
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "## ") {
+			continue
+		}
+		if first == -1 {
+			first = i
+		}
+		if start != -1 {
+			end = i
+			break
+		}
+		if isVersionHeading(line, version) {
+			start = i
+		}
+	}
+
+	var result []string
+	switch {
+	case start != -1:
+		result = append(result, lines[:start]...)
+		result = append(result, section, "")
+		result = append(result, lines[end:]...)
+	case first != -1:
+		result = append(result, lines[:first]...)
+		result = append(result, section, "")
+		result = append(result, lines[first:]...)
+	default:
+		result = append(result, strings.TrimRight(content, "\n"), "", section, "")
+	}
+
+	return strings.TrimRight(strings.Join(result, "\n"), "\n") + "\n"
+}
+
+// isVersionHeading reports whether a "## " heading belongs to version, e.g.
+// "## [1.2.0] - 2026-01-01", "## 1.2.0 (2026-01-01)" or "## [Unreleased]"
+func isVersionHeading(line, version string) bool {
+	fields := strings.Fields(strings.TrimPrefix(line, "## "))
+	if len(fields) == 0 {
+		return false
+	}
+	name := strings.TrimPrefix(strings.Trim(fields[0], "[]"), "v")
+	return strings.EqualFold(name, strings.TrimPrefix(version, "v"))
+}
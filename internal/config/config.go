@@ -3,7 +3,6 @@ package config
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 
 	"gopkg.in/yaml.v3"
 )
@@ -13,28 +12,147 @@ type Config struct {
 	Model              string           `yaml:"model"`
 	Language           string           `yaml:"language"`
 	Languages          []string         `yaml:"languages,omitempty"`           // Multiple languages for multilingual commits
-	AutoDetectLanguage bool             `yaml:"auto_detect_language,omitempty"` // Auto-detect language from project
+	AutoDetectLanguage *bool            `yaml:"auto_detect_language,omitempty"` // Auto-detect language from project (default: false)
 	Types              []CommitType     `yaml:"types"`
 	Template           string           `yaml:"template"`
 	Scopes             []string         `yaml:"scopes"`
 	CustomPrompt       string           `yaml:"custom_prompt,omitempty"`
 	MaxDiffLength      int              `yaml:"max_diff_length,omitempty"`
-	DetailedCommit     bool             `yaml:"detailed_commit,omitempty"` // Generate detailed commit messages with body
-	PromptScope        bool             `yaml:"prompt_scope,omitempty"`    // Whether to prompt for scope (default: false)
-	RequireTicket      bool             `yaml:"require_ticket,omitempty"`  // Require ticket/issue number
+	DetailedCommit     *bool            `yaml:"detailed_commit,omitempty"` // Generate detailed commit messages with body (default: true)
+	PromptScope        *bool            `yaml:"prompt_scope,omitempty"`    // Whether to prompt for scope (default: false)
+	RequireTicket      *bool            `yaml:"require_ticket,omitempty"`  // Require ticket/issue number (default: false)
 	TicketPattern      string           `yaml:"ticket_pattern,omitempty"`  // Pattern for ticket numbers (e.g., "PROJ-\d+")
 	TicketPrefix       string           `yaml:"ticket_prefix,omitempty"`   // Default ticket prefix (e.g., "JIRA", "PROJ")
 	SubjectLength      string           `yaml:"subject_length,omitempty"`  // Subject length: "short" (36 chars) or "normal" (72 chars)
 	DiffAnalysis       DiffAnalysisConfig `yaml:"diff_analysis,omitempty"` // Intelligent diff analysis configuration
+	Changelog          ChangelogConfig  `yaml:"changelog,omitempty"`       // Changelog generation settings
+	Precheck           PrecheckConfig   `yaml:"precheck,omitempty"`        // Pre-commit check settings
+	Redact             RedactConfig     `yaml:"redact,omitempty"`          // Masking of secrets in prompts
+	Ignore             []string         `yaml:"ignore,omitempty"`          // Globs of files never sent to the model, like .gitaiignore
+	Rules              []Rule           `yaml:"rules,omitempty"`           // Settings for matching branches, paths or commit types
+
+	pinned map[string]bool // Keys set by the environment or flags, which rules leave alone
+}
+
+// RedactConfig configures the masking of secrets and personal data before
+// prompts are sent to the model
+type RedactConfig struct {
+	Disabled *bool    `yaml:"disabled,omitempty"` // Send prompts unmasked (default: false)
+	Allow    []string `yaml:"allow,omitempty"`    // Regular expressions of values to keep, e.g. "@mycompany\\.com$"
+}
+
+// PrecheckConfig configures `gitai check` and the pre-commit hook
+type PrecheckConfig struct {
+	AI          *bool             `yaml:"ai,omitempty"`            // Ask the model for a review after the rules (default: false)
+	MaxBinaryKB int64             `yaml:"max_binary_kb,omitempty"` // Report binary files above this size (default: 1024)
+	Rules       map[string]string `yaml:"rules,omitempty"`         // Rule → "off", "warn" or "block"
+	Exclude     []string          `yaml:"exclude,omitempty"`       // Files the debug, todo and binary rules skip, in .gitignore syntax
+}
+
+// ChangelogConfig configures `gitai changelog`
+type ChangelogConfig struct {
+	Format    string            `yaml:"format,omitempty"`     // "keepachangelog" (default) or "markdown"
+	Template  string            `yaml:"template,omitempty"`   // Path to a custom text/template file
+	File      string            `yaml:"file,omitempty"`       // File to prepend to (default: CHANGELOG.md)
+	TicketURL string            `yaml:"ticket_url,omitempty"` // Link template, e.g. "https://jira.example.com/browse/{ticket}"
+	Sections  map[string]string `yaml:"sections,omitempty"`   // Commit type → section title overrides
 }
 
-// DiffAnalysisConfig configures intelligent diff analysis
+// DiffAnalysisConfig configures intelligent diff analysis. Unset fields
+// (nil) use the defaults, so "false" and "0" can be told apart from
+// settings a file leaves out.
 type DiffAnalysisConfig struct {
-	Enabled             bool `yaml:"enabled"`               // Enable smart diff analysis (default: true)
-	IncludeFunctionNames bool `yaml:"include_function_names"` // Extract function/class names (default: true)
-	IncludeImports      bool `yaml:"include_imports"`       // Extract import changes (default: true)
-	SmartTruncate       bool `yaml:"smart_truncate"`        // Use intelligent truncation (default: true)
-	ContextLines        int  `yaml:"context_lines"`         // Number of context lines in diff chunks (default: 3)
+	Enabled              *bool `yaml:"enabled,omitempty"`                // Enable smart diff analysis (default: true)
+	IncludeFunctionNames *bool `yaml:"include_function_names,omitempty"` // Extract function/class names (default: true)
+	IncludeImports       *bool `yaml:"include_imports,omitempty"`        // Extract import changes (default: true)
+	SmartTruncate        *bool `yaml:"smart_truncate,omitempty"`         // Use intelligent truncation (default: true)
+	ContextLines         *int  `yaml:"context_lines,omitempty"`          // Number of context lines in diff chunks (default: 3)
+}
+
+// Built-in defaults of the optional settings, used when no layer sets them
+const (
+	DefaultDetailedCommit     = true
+	DefaultPromptScope        = false
+	DefaultRequireTicket      = false
+	DefaultAutoDetectLanguage = false
+	DefaultDiffAnalysis       = true // Also the default of the include_* and smart_truncate settings
+	DefaultContextLines       = 3
+)
+
+// Bool returns a pointer to v, for optional settings
+func Bool(v bool) *bool {
+	return &v
+}
+
+// Int returns a pointer to v, for optional settings
+func Int(v int) *int {
+	return &v
+}
+
+// boolOr returns the value of an optional setting, or def when it is unset
+func boolOr(p *bool, def bool) bool {
+	if p == nil {
+		return def
+	}
+	return *p
+}
+
+// WantDetailedCommit reports whether messages get a body
+func (c *Config) WantDetailedCommit() bool {
+	return boolOr(c.DetailedCommit, DefaultDetailedCommit)
+}
+
+// WantPromptScope reports whether to ask for a scope
+func (c *Config) WantPromptScope() bool {
+	return boolOr(c.PromptScope, DefaultPromptScope)
+}
+
+// WantTicket reports whether a ticket number is required
+func (c *Config) WantTicket() bool {
+	return boolOr(c.RequireTicket, DefaultRequireTicket)
+}
+
+// WantAutoDetectLanguage reports whether to detect the language from the project
+func (c *Config) WantAutoDetectLanguage() bool {
+	return boolOr(c.AutoDetectLanguage, DefaultAutoDetectLanguage)
+}
+
+// IsEnabled reports whether smart diff analysis is enabled
+func (d DiffAnalysisConfig) IsEnabled() bool {
+	return boolOr(d.Enabled, DefaultDiffAnalysis)
+}
+
+// WantFunctionNames reports whether to extract function and class names
+func (d DiffAnalysisConfig) WantFunctionNames() bool {
+	return boolOr(d.IncludeFunctionNames, DefaultDiffAnalysis)
+}
+
+// WantImports reports whether to extract import changes
+func (d DiffAnalysisConfig) WantImports() bool {
+	return boolOr(d.IncludeImports, DefaultDiffAnalysis)
+}
+
+// WantSmartTruncate reports whether long diffs are cut by importance
+func (d DiffAnalysisConfig) WantSmartTruncate() bool {
+	return boolOr(d.SmartTruncate, DefaultDiffAnalysis)
+}
+
+// Context returns the number of context lines in diff chunks
+func (d DiffAnalysisConfig) Context() int {
+	if d.ContextLines == nil {
+		return DefaultContextLines
+	}
+	return *d.ContextLines
+}
+
+// WantAI reports whether `gitai check` asks the model for a review
+func (p PrecheckConfig) WantAI() bool {
+	return boolOr(p.AI, false)
+}
+
+// IsEnabled reports whether prompts are masked
+func (r RedactConfig) IsEnabled() bool {
+	return !boolOr(r.Disabled, false)
 }
 
 // CommitType defines a type of commit with description and emoji
@@ -44,76 +162,33 @@ type CommitType struct {
 	Emoji string `yaml:"emoji"`
 }
 
-// LoadConfig loads configuration from file with fallback to defaults
+// LoadConfig loads the configuration merged from all layers for the working
+// directory, see Resolve
 func LoadConfig() (*Config, error) {
-	// Try to find config file in order of priority
-	configPaths := []string{
-		".gitcommit.yaml",
-		".gitcommit.yml",
-		filepath.Join(os.Getenv("HOME"), ".gitcommit.yaml"),
-		filepath.Join(os.Getenv("HOME"), ".gitcommit.yml"),
-	}
-
-	for _, path := range configPaths {
-		if _, err := os.Stat(path); err == nil {
-			// Found config file
-			config, err := loadFromFile(path)
-			if err != nil {
-				return nil, fmt.Errorf("invalid config file format at %s: %w\nCheck .gitcommit.yaml syntax", path, err)
-			}
-			return config, nil
-		}
+	resolved, err := Resolve(Options{})
+	if err != nil {
+		return nil, err
 	}
-
-	// No config file found, use defaults
-	return DefaultConfig(), nil
+	return resolved.Config, nil
 }
 
-// loadFromFile loads configuration from a YAML file
+// loadFromFile loads a single YAML file over the defaults
 func loadFromFile(path string) (*Config, error) {
-	data, err := os.ReadFile(path)
+	_, env, err := Options{}.normalize()
 	if err != nil {
 		return nil, err
 	}
-
-	config := &Config{}
-	if err := yaml.Unmarshal(data, config); err != nil {
+	r, err := newResolver(env)
+	if err != nil {
 		return nil, err
 	}
-
-	// Apply defaults for missing fields
-	if config.Model == "" {
-		config.Model = "qwen2.5-coder:7b"
-	}
-	if config.Language == "" {
-		config.Language = "en"
-	}
-	if len(config.Types) == 0 {
-		config.Types = DefaultConfig().Types
-	}
-	if config.Template == "" {
-		config.Template = "{type}{scope}: {emoji} {message}"
-	}
-	if config.MaxDiffLength == 0 {
-		config.MaxDiffLength = 2000
+	if err := r.mergeFile(fileLayer{Layer: LayerRepo, Path: path}); err != nil {
+		return nil, err
 	}
-	if config.SubjectLength == "" {
-		config.SubjectLength = "normal"
+	if len(r.problems) > 0 {
+		return nil, &ValidationError{Problems: r.problems}
 	}
-
-	// Apply defaults for diff analysis
-	if config.DiffAnalysis.Enabled || (!config.DiffAnalysis.Enabled && config.DiffAnalysis.ContextLines == 0) {
-		// If not explicitly configured, use defaults
-		if config.DiffAnalysis.ContextLines == 0 {
-			config.DiffAnalysis.Enabled = true
-			config.DiffAnalysis.IncludeFunctionNames = true
-			config.DiffAnalysis.IncludeImports = true
-			config.DiffAnalysis.SmartTruncate = true
-			config.DiffAnalysis.ContextLines = 3
-		}
-	}
-
-	return config, nil
+	return r.decode()
 }
 
 // DefaultConfig returns the default configuration
@@ -132,19 +207,25 @@ func DefaultConfig() *Config {
 			{Name: "chore", Desc: "Build process or auxiliary tool changes", Emoji: "🔧"},
 			{Name: "ci", Desc: "CI configuration changes", Emoji: "👷"},
 			{Name: "build", Desc: "Build system changes", Emoji: "📦"},
+			{Name: "revert", Desc: "Reverts a previous commit", Emoji: "⏪"},
 		},
-		Template:       "{type}{scope}: {emoji} {message}",
-		Scopes:         []string{},
-		MaxDiffLength:  2000,
-		DetailedCommit: true,     // Default to detailed commits
-		SubjectLength:  "normal", // Default to normal length (72 chars)
+		Template:           "{type}{scope}: {emoji} {message}",
+		Scopes:             []string{},
+		MaxDiffLength:      2000,
+		AutoDetectLanguage: Bool(DefaultAutoDetectLanguage),
+		DetailedCommit:     Bool(DefaultDetailedCommit),
+		PromptScope:        Bool(DefaultPromptScope),
+		RequireTicket:      Bool(DefaultRequireTicket),
+		SubjectLength:      "normal", // Default to normal length (72 chars)
 		DiffAnalysis: DiffAnalysisConfig{
-			Enabled:             true,
-			IncludeFunctionNames: true,
-			IncludeImports:      true,
-			SmartTruncate:       true,
-			ContextLines:        3,
+			Enabled:              Bool(DefaultDiffAnalysis),
+			IncludeFunctionNames: Bool(DefaultDiffAnalysis),
+			IncludeImports:       Bool(DefaultDiffAnalysis),
+			SmartTruncate:        Bool(DefaultDiffAnalysis),
+			ContextLines:         Int(DefaultContextLines),
 		},
+		Precheck: PrecheckConfig{AI: Bool(false)},
+		Redact:   RedactConfig{Disabled: Bool(false)},
 	}
 }
 
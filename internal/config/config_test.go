@@ -2,6 +2,8 @@ package config
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -83,3 +85,106 @@ func TestSaveAndLoad(t *testing.T) {
 		t.Errorf("Expected language 'zh', got '%s'", loaded.Language)
 	}
 }
+
+func TestOptionalSettings(t *testing.T) {
+	settings := []struct {
+		key   string // Dotted key
+		value func(c *Config) bool
+		def   bool
+	}{
+		{"detailed_commit", (*Config).WantDetailedCommit, true},
+		{"prompt_scope", (*Config).WantPromptScope, false},
+		{"require_ticket", (*Config).WantTicket, false},
+		{"auto_detect_language", (*Config).WantAutoDetectLanguage, false},
+		{"diff_analysis.enabled", func(c *Config) bool { return c.DiffAnalysis.IsEnabled() }, true},
+		{"diff_analysis.include_function_names", func(c *Config) bool { return c.DiffAnalysis.WantFunctionNames() }, true},
+		{"diff_analysis.include_imports", func(c *Config) bool { return c.DiffAnalysis.WantImports() }, true},
+		{"diff_analysis.smart_truncate", func(c *Config) bool { return c.DiffAnalysis.WantSmartTruncate() }, true},
+		{"precheck.ai", func(c *Config) bool { return c.Precheck.WantAI() }, false},
+		{"redact.disabled", func(c *Config) bool { return !c.Redact.IsEnabled() }, false},
+	}
+	states := []struct {
+		name string
+		yaml string // Value written to the file, "" to leave the key out
+		want func(def bool) bool
+	}{
+		{"unset", "", func(def bool) bool { return def }},
+		{"false", "false", func(bool) bool { return false }},
+		{"true", "true", func(bool) bool { return true }},
+	}
+
+	for _, setting := range settings {
+		for _, state := range states {
+			t.Run(setting.key+"/"+state.name, func(t *testing.T) {
+				content := "model: test\n"
+				if state.yaml != "" {
+					parts := strings.Split(setting.key, ".")
+					if len(parts) == 2 {
+						content += parts[0] + ":\n  " + parts[1] + ": " + state.yaml + "\n"
+					} else {
+						content += setting.key + ": " + state.yaml + "\n"
+					}
+				}
+				path := filepath.Join(t.TempDir(), ".gitcommit.yaml")
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+
+				loaded, err := loadFromFile(path)
+				if err != nil {
+					t.Fatalf("loadFromFile() error = %v", err)
+				}
+				want := state.want(setting.def)
+				if got := setting.value(loaded); got != want {
+					t.Errorf("loaded %s = %v, want %v", setting.key, got, want)
+				}
+
+				// The value survives saving and loading again
+				if err := loaded.Save(path); err != nil {
+					t.Fatal(err)
+				}
+				reloaded, err := loadFromFile(path)
+				if err != nil {
+					t.Fatalf("loadFromFile() after Save error = %v", err)
+				}
+				if got := setting.value(reloaded); got != want {
+					t.Errorf("saved and reloaded %s = %v, want %v", setting.key, got, want)
+				}
+
+				// A configuration built in code without the setting uses the default
+				if got := setting.value(&Config{}); got != setting.def {
+					t.Errorf("unset %s = %v, want %v", setting.key, got, setting.def)
+				}
+			})
+		}
+	}
+}
+
+func TestContextLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{"unset", "model: test\n", DefaultContextLines},
+		{"zero", "diff_analysis:\n  context_lines: 0\n", 0},
+		{"set", "diff_analysis:\n  context_lines: 10\n", 10},
+		{"analysis disabled", "diff_analysis:\n  enabled: false\n", DefaultContextLines},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".gitcommit.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			loaded, err := loadFromFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := loaded.DiffAnalysis.Context(); got != tt.want {
+				t.Errorf("context lines = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is a config file opened for editing. Changes keep the comments,
+// key order and formatting of the rest of the file.
+type Document struct {
+	Path string
+	root *yaml.Node // Mapping node of the document
+	doc  *yaml.Node
+}
+
+// OpenDocument reads a config file for editing. A missing file is an empty
+// document that Save creates.
+func OpenDocument(path string) (*Document, error) {
+	d := &Document{Path: path}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid config file format at %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: the configuration must be a map of keys to values", path)
+	}
+	d.doc, d.root = &doc, doc.Content[0]
+	return d, nil
+}
+
+// lookupEditableKey finds the key of a setting. Entries of maps, such as
+// changelog.sections.feat, are keys of their own, and so is extends.
+func lookupEditableKey(name string) (Key, error) {
+	if key, ok := LookupKey(name); ok {
+		return key, nil
+	}
+	if name == ExtendsKey {
+		return Key{Name: name, Type: reflect.TypeOf([]string{})}, nil
+	}
+	if parent, ok := LookupKey(parentKey(name)); ok && parent.Type.Kind() == reflect.Map {
+		return Key{Name: name, Type: parent.Type.Elem()}, nil
+	}
+	return Key{}, fmt.Errorf("unknown configuration key %q", name)
+}
+
+// Get returns the value of key in the file, or nil when the file does not
+// set it
+func (d *Document) Get(name string) (interface{}, error) {
+	if _, err := lookupEditableKey(name); err != nil {
+		return nil, err
+	}
+
+	node := d.root
+	for _, part := range strings.Split(name, ".") {
+		if node.Kind != yaml.MappingNode {
+			return nil, nil
+		}
+		if node = mappingValue(node, part); node == nil {
+			return nil, nil
+		}
+	}
+
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Set sets key to a value given as on the command line (see Key.Parse).
+// Comments on the old value are kept.
+func (d *Document) Set(name, raw string) error {
+	key, err := lookupEditableKey(name)
+	if err != nil {
+		return err
+	}
+	value, err := key.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", name, err)
+	}
+
+	if refs, ok := value.([]interface{}); ok && name == ExtendsKey && len(refs) == 1 {
+		value = refs[0] // extends: angular
+	}
+
+	var valueNode yaml.Node
+	if err := valueNode.Encode(value); err != nil {
+		return err
+	}
+	if valueNode.Kind == yaml.SequenceNode && len(valueNode.Content) <= 8 {
+		valueNode.Style = yaml.FlowStyle // scopes: [api, web]
+	}
+
+	parts := strings.Split(name, ".")
+	node := d.root
+	for _, part := range parts[:len(parts)-1] {
+		child := mappingValue(node, part)
+		if child == nil || child.Kind != yaml.MappingNode {
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			setMappingValue(node, part, child)
+		}
+		node = child
+	}
+	setMappingValue(node, parts[len(parts)-1], &valueNode)
+	return nil
+}
+
+// Unset removes key from the file and reports whether it was set. Sections
+// left empty are removed too.
+func (d *Document) Unset(name string) (bool, error) {
+	if _, err := lookupEditableKey(name); err != nil {
+		return false, err
+	}
+	return removeKey(d.root, strings.Split(name, ".")), nil
+}
+
+// removeKey removes a key path from a mapping node
+func removeKey(node *yaml.Node, parts []string) bool {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != parts[0] {
+			continue
+		}
+		if len(parts) > 1 {
+			child := node.Content[i+1]
+			if child.Kind != yaml.MappingNode || !removeKey(child, parts[1:]) {
+				return false
+			}
+			if len(child.Content) > 0 {
+				return true
+			}
+		}
+		node.Content = append(node.Content[:i], node.Content[i+2:]...)
+		return true
+	}
+	return false
+}
+
+// setMappingValue replaces the value of key in a mapping node, keeping the
+// comments of the old value, or appends the key
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			old := node.Content[i+1]
+			if value.LineComment == "" {
+				value.LineComment = old.LineComment
+			}
+			if value.HeadComment == "" {
+				value.HeadComment = old.HeadComment
+			}
+			if value.FootComment == "" {
+				value.FootComment = old.FootComment
+			}
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+// Bytes returns the edited file
+func (d *Document) Bytes() ([]byte, error) {
+	if len(d.root.Content) == 0 {
+		return nil, nil
+	}
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(d.doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Save checks the edited file and writes it. Nothing is written when the
+// result has problems.
+func (d *Document) Save() error {
+	data, err := d.Bytes()
+	if err != nil {
+		return err
+	}
+	if problems := lintData(d.Path, data); len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(d.Path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(d.Path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
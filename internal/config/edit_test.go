@@ -0,0 +1,156 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDocumentSet(t *testing.T) {
+	original := `# Team settings
+model: llama3 # shared model
+language: en
+
+# Commit scopes
+scopes:
+  - api
+`
+
+	tests := []struct {
+		name  string
+		key   string
+		value string
+		want  []string // Lines the saved file must contain
+	}{
+		{
+			name:  "replace value keeps comments",
+			key:   "model",
+			value: "qwen2.5-coder:7b",
+			want:  []string{"# Team settings", "model: qwen2.5-coder:7b # shared model", "# Commit scopes"},
+		},
+		{
+			name:  "replace list",
+			key:   "scopes",
+			value: "api,web",
+			want:  []string{"# Commit scopes", "scopes: [api, web]"},
+		},
+		{
+			name:  "add nested key",
+			key:   "diff_analysis.context_lines",
+			value: "5",
+			want:  []string{"language: en", "diff_analysis:", "  context_lines: 5"},
+		},
+		{
+			name:  "add map entry",
+			key:   "changelog.sections.feat",
+			value: "New Features",
+			want:  []string{"changelog:", "  sections:", "    feat: New Features"},
+		},
+		{
+			name:  "extends one preset",
+			key:   "extends",
+			value: "angular",
+			want:  []string{"extends: angular"},
+		},
+		{
+			name:  "boolean",
+			key:   "detailed_commit",
+			value: "false",
+			want:  []string{"detailed_commit: false"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".gitcommit.yaml")
+			writeFile(t, path, original)
+
+			doc, err := OpenDocument(path)
+			if err != nil {
+				t.Fatalf("OpenDocument() error = %v", err)
+			}
+			if err := doc.Set(tt.key, tt.value); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+			if err := doc.Save(); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, line := range tt.want {
+				if !strings.Contains(string(data), line+"\n") {
+					t.Errorf("file = %q, want line %q", data, line)
+				}
+			}
+		})
+	}
+}
+
+func TestDocumentGetUnset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, "model: llama3\nchangelog:\n  sections:\n    feat: Features\n")
+
+	doc, err := OpenDocument(path)
+	if err != nil {
+		t.Fatalf("OpenDocument() error = %v", err)
+	}
+
+	if got, err := doc.Get("changelog.sections"); err != nil || !reflect.DeepEqual(got, map[string]interface{}{"feat": "Features"}) {
+		t.Errorf("Get(changelog.sections) = %v, %v", got, err)
+	}
+	if got, err := doc.Get("language"); err != nil || got != nil {
+		t.Errorf("Get(language) = %v, %v, want nil", got, err)
+	}
+
+	removed, err := doc.Unset("changelog.sections.feat")
+	if err != nil || !removed {
+		t.Fatalf("Unset() = %v, %v, want true", removed, err)
+	}
+	if removed, _ := doc.Unset("language"); removed {
+		t.Error("Unset(language) = true for a key that is not set")
+	}
+	data, err := doc.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "model: llama3\n" {
+		t.Errorf("Bytes() = %q, want the emptied sections removed", data)
+	}
+}
+
+func TestDocumentErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "missing", ".gitcommit.yaml")
+
+	doc, err := OpenDocument(path)
+	if err != nil {
+		t.Fatalf("OpenDocument() of a missing file error = %v", err)
+	}
+	if err := doc.Set("modle", "llama3"); err == nil || !strings.Contains(err.Error(), "unknown configuration key") {
+		t.Errorf("Set(modle) error = %v, want unknown key", err)
+	}
+	if err := doc.Set("max_diff_length", "lots"); err == nil {
+		t.Error("Set(max_diff_length, lots) = nil, want a type error")
+	}
+
+	// Values are checked when saving, and nothing is written
+	if err := doc.Set("subject_length", "medium"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := doc.Save(); err == nil || !strings.Contains(err.Error(), "subject_length") {
+		t.Errorf("Save() error = %v, want the invalid subject_length", err)
+	}
+	if fileExists(path) {
+		t.Error("Save() wrote a file with problems")
+	}
+
+	writeFile(t, filepath.Join(dir, "list.yaml"), "- model\n")
+	if _, err := OpenDocument(filepath.Join(dir, "list.yaml")); err == nil {
+		t.Error("OpenDocument() of a list = nil, want an error")
+	}
+}
@@ -0,0 +1,299 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ExtendsKey is the key of the files a config file builds on. It is read
+// from files only, before the file's own settings are merged.
+const ExtendsKey = "extends"
+
+// gitRefPrefix starts references to a file in a git repository:
+// git::<repository>//<path>[?ref=<ref>]
+const gitRefPrefix = "git::"
+
+// maxExtendsDepth limits chains of files extending each other
+const maxExtendsDepth = 8
+
+// extendsCacheTTL is how long a file fetched from a remote repository is used
+// before it is fetched again
+const extendsCacheTTL = 24 * time.Hour
+
+//go:embed presets/*.yaml
+var presetFiles embed.FS
+
+// gitTimeout bounds each git command run for extended files, which may run
+// inside a commit hook
+const gitTimeout = 30 * time.Second
+
+// scpLikeRepo matches ssh repositories written as user@host:path
+var scpLikeRepo = regexp.MustCompile(`^[A-Za-z0-9._~-]+@[A-Za-z0-9.-]+:`)
+
+// gitProtocols are the only transports git may use for extended files
+var gitProtocols = []string{"https", "ssh", "file"}
+
+// Presets returns the names of the built-in presets, sorted
+func Presets() []string {
+	entries, err := presetFiles.ReadDir("presets")
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".yaml"))
+	}
+	return names
+}
+
+// PresetTitle returns the first comment line of a built-in preset, e.g.
+// "Angular Commit Message Convention"
+func PresetTitle(name string) string {
+	data, err := presetFiles.ReadFile(path.Join("presets", name+".yaml"))
+	if err != nil {
+		return ""
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	return strings.TrimSpace(strings.TrimPrefix(line, "#"))
+}
+
+// extendsOf returns what a config file extends: one reference or a list
+func extendsOf(tree map[string]interface{}) ([]string, error) {
+	switch value := tree[ExtendsKey].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{value}, nil
+	case []interface{}:
+		refs := make([]string, 0, len(value))
+		for _, item := range value {
+			ref, ok := item.(string)
+			if !ok || ref == "" {
+				return nil, fmt.Errorf("%s must list preset names, paths or git references", ExtendsKey)
+			}
+			refs = append(refs, ref)
+		}
+		return refs, nil
+	default:
+		return nil, fmt.Errorf("%s must be a preset name, a path or a git reference, or a list of them", ExtendsKey)
+	}
+}
+
+// loadExtends reads the file ref points at and returns its source, as shown
+// in origins, and content. Relative paths are relative to the extending file
+// base. stale is set when a remote file could not be fetched and an older
+// copy is used.
+func loadExtends(ref, base string, env map[string]string) (source string, data []byte, stale bool, err error) {
+	switch {
+	case strings.HasPrefix(ref, gitRefPrefix):
+		return loadGitFile(ref, base, env)
+	case isPresetName(ref):
+		data, err := presetFiles.ReadFile(path.Join("presets", ref+".yaml"))
+		if err != nil {
+			return "", nil, false, fmt.Errorf("unknown preset %q (built-in presets: %s)", ref, strings.Join(Presets(), ", "))
+		}
+		return "preset " + ref, data, false, nil
+	}
+
+	file, err := localPath(ref, base, env)
+	if err != nil {
+		return "", nil, false, err
+	}
+	data, err = os.ReadFile(file)
+	if err != nil {
+		return "", nil, false, err
+	}
+	return file, data, false, nil
+}
+
+// isPresetName reports whether ref names a built-in preset rather than a
+// file: a plain name such as "angular"
+func isPresetName(ref string) bool {
+	return !strings.ContainsAny(ref, `/\`) && filepath.Ext(ref) == "" && !strings.HasPrefix(ref, "~")
+}
+
+// localPath resolves a path relative to the extending file base. "~/" is the
+// home directory.
+func localPath(ref, base string, env map[string]string) (string, error) {
+	if rest, ok := strings.CutPrefix(ref, "~/"); ok {
+		if env["HOME"] == "" {
+			return "", fmt.Errorf("cannot resolve %s: HOME is not set", ref)
+		}
+		return filepath.Join(env["HOME"], rest), nil
+	}
+	if filepath.IsAbs(ref) {
+		return ref, nil
+	}
+	if !filepath.IsAbs(base) {
+		return "", fmt.Errorf("cannot resolve the relative path %s in %s", ref, base)
+	}
+	return filepath.Join(filepath.Dir(base), ref), nil
+}
+
+// parseGitRef splits git::<repository>//<path>[?ref=<ref>]
+func parseGitRef(ref string) (repo, file, rev string, err error) {
+	spec := strings.TrimPrefix(ref, gitRefPrefix)
+	if i := strings.LastIndex(spec, "?ref="); i >= 0 {
+		spec, rev = spec[:i], spec[i+len("?ref="):]
+	}
+
+	// The repository may be a URL with "//" after the scheme
+	start := 0
+	if i := strings.Index(spec, "://"); i >= 0 {
+		start = i + len("://")
+	}
+	i := strings.Index(spec[start:], "//")
+	if i < 0 {
+		return "", "", "", fmt.Errorf("invalid git reference %q, want git::<repository>//<path>[?ref=<ref>]", ref)
+	}
+	repo, file = spec[:start+i], spec[start+i+2:]
+	if repo == "" || file == "" {
+		return "", "", "", fmt.Errorf("invalid git reference %q, want git::<repository>//<path>[?ref=<ref>]", ref)
+	}
+	if rev == "" {
+		rev = "HEAD"
+	}
+
+	// Values starting with "-" would be read by git as options
+	for _, value := range []string{repo, file, rev} {
+		if strings.HasPrefix(value, "-") {
+			return "", "", "", fmt.Errorf("invalid git reference %q: %q must not start with -", ref, value)
+		}
+	}
+	if strings.Contains(repo, ":") && !isAllowedRepo(repo) {
+		return "", "", "", fmt.Errorf("invalid git reference %q: use an https://, ssh://, file:// or user@host:path repository", ref)
+	}
+	return repo, file, rev, nil
+}
+
+// isAllowedRepo reports whether a remote repository uses https, ssh or file
+func isAllowedRepo(repo string) bool {
+	for _, protocol := range gitProtocols {
+		if strings.HasPrefix(repo, protocol+"://") {
+			return true
+		}
+	}
+	return scpLikeRepo.MatchString(repo)
+}
+
+// loadGitFile reads a file of another repository. Local repositories are
+// read directly; remote ones are fetched and cached for extendsCacheTTL.
+func loadGitFile(ref, base string, env map[string]string) (string, []byte, bool, error) {
+	repo, file, rev, err := parseGitRef(ref)
+	if err != nil {
+		return "", nil, false, err
+	}
+
+	if !strings.Contains(repo, ":") {
+		dir, err := localPath(repo, base, env)
+		if err != nil {
+			return "", nil, false, err
+		}
+		data, err := gitOutput(dir, "show", "--end-of-options", rev+":"+file)
+		if err != nil {
+			return "", nil, false, fmt.Errorf("cannot read %s: %w", ref, err)
+		}
+		return ref, data, false, nil
+	}
+
+	cache := extendsCachePath(ref, env)
+	if info, err := os.Stat(cache); err == nil && time.Since(info.ModTime()) < extendsCacheTTL {
+		data, err := os.ReadFile(cache)
+		return ref, data, false, err
+	}
+
+	data, fetchErr := fetchGitFile(repo, file, rev)
+	if fetchErr != nil {
+		if data, err := os.ReadFile(cache); err == nil {
+			return ref, data, true, nil
+		}
+		return "", nil, false, fmt.Errorf("cannot fetch %s: %w", ref, fetchErr)
+	}
+	if cache != "" {
+		if err := os.MkdirAll(filepath.Dir(cache), 0755); err == nil {
+			_ = os.WriteFile(cache, data, 0644)
+		}
+	}
+	return ref, data, false, nil
+}
+
+// fetchGitFile fetches one revision of a remote repository into a temporary
+// repository and reads a file of it
+func fetchGitFile(repo, file, rev string) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "gitai-extends-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := gitOutput(dir, "init", "--quiet", "--bare"); err != nil {
+		return nil, err
+	}
+	if _, err := gitOutput(dir, "fetch", "--quiet", "--depth", "1", "--", repo, rev); err != nil {
+		return nil, err
+	}
+	return gitOutput(dir, "show", "--end-of-options", "FETCH_HEAD:"+file)
+}
+
+// extendsCachePath returns the cache file of a remote reference, or "" when
+// there is no cache directory
+func extendsCachePath(ref string, env map[string]string) string {
+	dir := env["XDG_CACHE_HOME"]
+	if dir == "" && env["HOME"] != "" {
+		dir = filepath.Join(env["HOME"], ".cache")
+	}
+	if dir == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(ref))
+	return filepath.Join(dir, "gitai", "extends", hex.EncodeToString(sum[:8])+".yaml")
+}
+
+// gitOutput runs git in dir and returns its output. Only the gitProtocols
+// are allowed, git never prompts for credentials, and the command is killed
+// after gitTimeout. An ssh command the user configured (for a key or a jump
+// host) is kept; otherwise ssh runs in batch mode so it cannot prompt either.
+func gitOutput(dir string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gitTimeout)
+	defer cancel()
+
+	prefix := []string{"-C", dir, "-c", "protocol.allow=never"}
+	for _, protocol := range gitProtocols {
+		prefix = append(prefix, "-c", "protocol."+protocol+".allow=always")
+	}
+	if !hasSSHCommand(dir) {
+		prefix = append(prefix, "-c", "core.sshCommand=ssh -o BatchMode=yes")
+	}
+	cmd := exec.CommandContext(ctx, "git", append(prefix, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	output, err := cmd.Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("git %s timed out after %s", args[0], gitTimeout)
+	}
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+	return output, nil
+}
+
+// hasSSHCommand reports whether the user chose how git runs ssh, through
+// GIT_SSH_COMMAND, GIT_SSH or core.sshCommand
+func hasSSHCommand(dir string) bool {
+	if os.Getenv("GIT_SSH_COMMAND") != "" || os.Getenv("GIT_SSH") != "" {
+		return true
+	}
+	return exec.Command("git", "-C", dir, "config", "--get", "core.sshCommand").Run() == nil
+}
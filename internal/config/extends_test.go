@@ -0,0 +1,285 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPresets(t *testing.T) {
+	want := []string{"angular", "chinese-enterprise", "google", "jira"}
+	if got := Presets(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Presets() = %v, want %v", got, want)
+	}
+
+	for _, name := range want {
+		data, err := presetFiles.ReadFile("presets/" + name + ".yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if problems := lintData(name, data); len(problems) > 0 {
+			t.Errorf("preset %s has problems: %v", name, problems)
+		}
+		// Project settings stay with the project
+		if strings.Contains(string(data), "\nmodel:") || strings.Contains(string(data), "\nscopes:") {
+			t.Errorf("preset %s sets model or scopes", name)
+		}
+	}
+}
+
+func TestResolveExtends(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string // Paths relative to the repository
+		check   func(t *testing.T, r *Resolved)
+		wantErr string
+	}{
+		{
+			name: "preset with local overrides",
+			files: map[string]string{
+				".gitcommit.yaml": "extends: jira\nticket_prefix: AUTH\nrequire_ticket: false\n",
+			},
+			check: func(t *testing.T, r *Resolved) {
+				if r.Config.TicketPattern != `[A-Z]+-\d+` || r.Config.TicketPrefix != "AUTH" || r.Config.WantTicket() {
+					t.Errorf("ticket settings = %q %q %v, want the preset pattern and the local prefix and requirement",
+						r.Config.TicketPattern, r.Config.TicketPrefix, r.Config.WantTicket())
+				}
+				if origin := r.Origin("custom_prompt"); origin.Source != "preset jira" || origin.Layer != LayerRepo {
+					t.Errorf("origin of custom_prompt = %s, want the preset in the repo layer", origin)
+				}
+				if origin := r.Origin("ticket_prefix"); !strings.HasSuffix(origin.Source, ".gitcommit.yaml") {
+					t.Errorf("origin of ticket_prefix = %s, want the repository file", origin)
+				}
+			},
+		},
+		{
+			name: "chain of local files",
+			files: map[string]string{
+				".gitcommit.yaml":        "extends: conventions/team.yaml\nscopes: [api]\n",
+				"conventions/team.yaml":  "extends: [./org.yaml]\nlanguage: zh\n",
+				"conventions/org.yaml":   "language: ja\nsubject_length: short\ncustom_prompt: org\n",
+				"conventions/other.yaml": "model: unused\n",
+			},
+			check: func(t *testing.T, r *Resolved) {
+				c := r.Config
+				if c.Language != "zh" || c.SubjectLength != "short" || c.CustomPrompt != "org" || !reflect.DeepEqual(c.Scopes, []string{"api"}) {
+					t.Errorf("config = %s %s %q %v, want each file to override the one it extends", c.Language, c.SubjectLength, c.CustomPrompt, c.Scopes)
+				}
+				if len(r.Files) != 3 || !strings.HasSuffix(r.Files[0], "org.yaml") || !strings.HasSuffix(r.Files[2], ".gitcommit.yaml") {
+					t.Errorf("files = %v, want the extended files first", r.Files)
+				}
+			},
+		},
+		{
+			name: "later entries win",
+			files: map[string]string{
+				".gitcommit.yaml": "extends: [angular, chinese-enterprise]\n",
+			},
+			check: func(t *testing.T, r *Resolved) {
+				if r.Config.Language != "zh" {
+					t.Errorf("language = %s, want zh from the last preset", r.Config.Language)
+				}
+			},
+		},
+		{
+			name:    "unknown preset",
+			files:   map[string]string{".gitcommit.yaml": "extends: angualr\n"},
+			wantErr: `unknown preset "angualr" (built-in presets: angular, chinese-enterprise, google, jira)`,
+		},
+		{
+			name:    "missing file",
+			files:   map[string]string{".gitcommit.yaml": "extends: ./missing.yaml\n"},
+			wantErr: "missing.yaml",
+		},
+		{
+			name: "cycle",
+			files: map[string]string{
+				".gitcommit.yaml": "extends: a.yaml\n",
+				"a.yaml":          "extends: b.yaml\n",
+				"b.yaml":          "extends: a.yaml\n",
+			},
+			wantErr: "files extend each other",
+		},
+		{
+			name:    "problems in extended files",
+			files:   map[string]string{".gitcommit.yaml": "extends: base.yaml\n", "base.yaml": "modle: llama3\n"},
+			wantErr: `base.yaml:1:1: modle: unknown key "modle"`,
+		},
+		{
+			name:    "git option in the repository",
+			files:   map[string]string{".gitcommit.yaml": "extends: \"git::--upload-pack=touch pwned;:x//f?ref=.\"\n"},
+			wantErr: "must not start with -",
+		},
+		{
+			name:    "invalid value",
+			files:   map[string]string{".gitcommit.yaml": "extends: {name: angular}\n"},
+			wantErr: "extends: must be a preset name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home, root, _ := layeredTree(t, "", "", "")
+			for name, content := range tt.files {
+				writeFile(t, filepath.Join(root, name), content)
+			}
+
+			resolved, err := Resolve(Options{Dir: root, Env: []string{"HOME=" + home}})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Resolve() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			tt.check(t, resolved)
+		})
+	}
+}
+
+func TestParseGitRef(t *testing.T) {
+	tests := []struct {
+		ref             string
+		repo, file, rev string
+		wantErr         bool
+	}{
+		{ref: "git::https://github.com/acme/conventions.git//gitai.yaml?ref=v1.2", repo: "https://github.com/acme/conventions.git", file: "gitai.yaml", rev: "v1.2"},
+		{ref: "git::git@github.com:acme/conventions.git//config/base.yaml", repo: "git@github.com:acme/conventions.git", file: "config/base.yaml", rev: "HEAD"},
+		{ref: "git::../conventions//gitai.yaml?ref=main", repo: "../conventions", file: "gitai.yaml", rev: "main"},
+		{ref: "git::https://github.com/acme/conventions.git", wantErr: true},
+		{ref: "git::../conventions//", wantErr: true},
+		// Nothing may reach git as an option or an unexpected transport
+		{ref: "git::--upload-pack=touch pwned;:x//f?ref=.", wantErr: true},
+		{ref: "git::https://github.com/acme/conventions.git//gitai.yaml?ref=--output=x", wantErr: true},
+		{ref: "git::https://github.com/acme/conventions.git//-gitai.yaml", wantErr: true},
+		{ref: "git::-C/tmp//gitai.yaml", wantErr: true},
+		{ref: "git::ext::sh -c touch% pwned//gitai.yaml", wantErr: true},
+		{ref: "git::http://github.com/acme/conventions.git//gitai.yaml", wantErr: true},
+		{ref: "git::ssh://git@github.com/acme/conventions.git//gitai.yaml", repo: "ssh://git@github.com/acme/conventions.git", file: "gitai.yaml", rev: "HEAD"},
+	}
+	for _, tt := range tests {
+		repo, file, rev, err := parseGitRef(tt.ref)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseGitRef(%q) = %q %q %q, want an error", tt.ref, repo, file, rev)
+			}
+			continue
+		}
+		if err != nil || repo != tt.repo || file != tt.file || rev != tt.rev {
+			t.Errorf("parseGitRef(%q) = %q %q %q %v, want %q %q %q", tt.ref, repo, file, rev, err, tt.repo, tt.file, tt.rev)
+		}
+	}
+}
+
+func TestResolveExtendsGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	// A conventions repository whose v1 tag differs from its branch
+	conventions := filepath.Join(t.TempDir(), "conventions")
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", conventions, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	writeFile(t, filepath.Join(conventions, "gitai.yaml"), "extends: angular\nsubject_length: short\n")
+	run("init", "--quiet")
+	run("add", ".")
+	run("commit", "--quiet", "-m", "conventions")
+	run("tag", "v1")
+	writeFile(t, filepath.Join(conventions, "gitai.yaml"), "subject_length: normal\n")
+	run("commit", "--quiet", "-am", "change")
+
+	cache := t.TempDir()
+	env := []string{"HOME=" + t.TempDir(), "XDG_CACHE_HOME=" + cache}
+	for _, ref := range []string{
+		"git::" + conventions + "//gitai.yaml?ref=v1",
+		"git::file://" + conventions + "//gitai.yaml?ref=v1",
+	} {
+		t.Run(ref, func(t *testing.T) {
+			_, root, _ := layeredTree(t, "", "extends: "+ref+"\nlanguage: ja\n", "")
+			resolved, err := Resolve(Options{Dir: root, Env: env})
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			c := resolved.Config
+			if c.SubjectLength != "short" || c.Language != "ja" || !strings.Contains(c.CustomPrompt, "Angular") {
+				t.Errorf("config = %s %s, want the tagged file, its preset and the local language", c.SubjectLength, c.Language)
+			}
+			if origin := resolved.Origin("subject_length"); origin.Source != ref {
+				t.Errorf("origin of subject_length = %s, want %s", origin, ref)
+			}
+		})
+	}
+
+	// Remote files are cached
+	entries, err := os.ReadDir(filepath.Join(cache, "gitai", "extends"))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("cache = %v, %v, want one file", entries, err)
+	}
+	if err := os.RemoveAll(conventions); err != nil {
+		t.Fatal(err)
+	}
+	_, root, _ := layeredTree(t, "", "extends: git::file://"+conventions+"//gitai.yaml?ref=v1\n", "")
+	if _, err := Resolve(Options{Dir: root, Env: env}); err != nil {
+		t.Errorf("Resolve() with a cached file error = %v", err)
+	}
+
+	_, root, _ = layeredTree(t, "", "extends: git::file://"+conventions+"//gitai.yaml?ref=v2\n", "")
+	_, err = Resolve(Options{Dir: root, Env: env})
+	var validationErr *ValidationError
+	if err == nil || errors.As(err, &validationErr) || !strings.Contains(err.Error(), "cannot fetch") {
+		t.Errorf("Resolve() of a missing repository error = %v, want a fetch error", err)
+	}
+}
+
+func TestHasSSHCommand(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_SSH_COMMAND", "")
+	t.Setenv("GIT_SSH", "")
+
+	repo := t.TempDir()
+	if output, err := exec.Command("git", "-C", repo, "init", "--quiet").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, output)
+	}
+	if hasSSHCommand(repo) {
+		t.Error("hasSSHCommand() = true without any ssh command")
+	}
+
+	t.Run("GIT_SSH_COMMAND", func(t *testing.T) {
+		t.Setenv("GIT_SSH_COMMAND", "ssh -i ~/.ssh/deploy")
+		if !hasSSHCommand(repo) {
+			t.Error("hasSSHCommand() = false, want the variable to count")
+		}
+	})
+	t.Run("GIT_SSH", func(t *testing.T) {
+		t.Setenv("GIT_SSH", "/usr/local/bin/ssh-wrapper")
+		if !hasSSHCommand(repo) {
+			t.Error("hasSSHCommand() = false, want the variable to count")
+		}
+	})
+	t.Run("core.sshCommand", func(t *testing.T) {
+		if output, err := exec.Command("git", "config", "--global", "core.sshCommand", "ssh -J bastion").CombinedOutput(); err != nil {
+			t.Fatalf("git config: %v\n%s", err, output)
+		}
+		if !hasSSHCommand(repo) {
+			t.Error("hasSSHCommand() = false, want the setting to count")
+		}
+	})
+}
@@ -0,0 +1,688 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Layer names, from lowest to highest precedence
+const (
+	LayerDefault = "default" // Built-in defaults
+	LayerUser    = "user"    // $XDG_CONFIG_HOME/gitai/config.yaml or ~/.gitcommit.yaml
+	LayerRepo    = "repo"    // .gitcommit.yaml at the top of the repository
+	LayerDir     = "dir"     // .gitcommit.yaml in subdirectories, e.g. monorepo packages
+	LayerEnv     = "env"     // GITAI_* environment variables
+	LayerFlag    = "flag"    // Command-line flags
+)
+
+// FileNames are the names of repository and directory config files
+var FileNames = []string{".gitcommit.yaml", ".gitcommit.yml"}
+
+// EnvPrefix starts the environment variables that set configuration keys
+const EnvPrefix = "GITAI_"
+
+// Origin tells which layer set a configuration value
+type Origin struct {
+	Layer  string
+	Source string // File, environment variable or flag; empty for defaults
+}
+
+// String returns "layer" or "layer (source)"
+func (o Origin) String() string {
+	if o.Source == "" {
+		return o.Layer
+	}
+	return fmt.Sprintf("%s (%s)", o.Layer, o.Source)
+}
+
+// Override sets one key from outside the config files, e.g. a flag
+type Override struct {
+	Key    string // Dotted key, e.g. "model" or "diff_analysis.enabled"
+	Value  string
+	Source string // Shown as the origin, e.g. "--model"
+}
+
+// ParseOverride parses a "key=value" argument such as -c model=llama3
+func ParseOverride(arg, source string) (Override, error) {
+	key, value, ok := strings.Cut(arg, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return Override{}, fmt.Errorf("invalid %s %q, want key=value", source, arg)
+	}
+	return Override{Key: key, Value: value, Source: source + " " + key}, nil
+}
+
+// Options selects what Resolve reads
+type Options struct {
+	Dir       string     // Directory to resolve from (default: the working directory)
+	Env       []string   // Environment as KEY=VALUE (default: os.Environ())
+	Overrides []Override // Flag values, applied last
+}
+
+// Resolved is a configuration merged from all layers
+type Resolved struct {
+	Config  *Config
+	Files   []string          // Config files merged, lowest precedence first, including those they extend
+	Notes   []string          // Settings earlier versions read differently
+	origins map[string]Origin // Dotted key → layer that set it last
+}
+
+// Origin returns the layer that set key. Keys no layer set are defaults.
+func (r *Resolved) Origin(key string) Origin {
+	for k := key; k != ""; k = parentKey(k) {
+		if origin, ok := r.origins[k]; ok {
+			return origin
+		}
+	}
+	return Origin{Layer: LayerDefault}
+}
+
+// Resolve merges the configuration layers: built-in defaults, the user
+// config, the repository's config, configs in directories between the top of
+// the repository and opts.Dir, GITAI_* environment variables and overrides.
+// Maps merge key by key; lists and scalars replace lower layers.
+func Resolve(opts Options) (*Resolved, error) {
+	opts, env, err := opts.normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := newResolver(env)
+	if err != nil {
+		return nil, err
+	}
+	for _, layer := range configFiles(opts.Dir, env) {
+		if err := r.mergeFile(layer); err != nil {
+			return nil, err
+		}
+	}
+	if len(r.problems) > 0 {
+		return nil, &ValidationError{Problems: r.problems}
+	}
+
+	for _, key := range Keys() {
+		name := EnvName(key.Name)
+		if value, ok := env[name]; ok {
+			if err := r.set(key, value, Origin{Layer: LayerEnv, Source: name}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, override := range opts.Overrides {
+		key, err := lookupOverrideKey(override.Key)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", override.Source, err)
+		}
+		if err := r.set(key, override.Value, Origin{Layer: LayerFlag, Source: override.Source}); err != nil {
+			return nil, err
+		}
+	}
+
+	config, err := r.decode()
+	if err != nil {
+		return nil, err
+	}
+	resolved := &Resolved{Config: config, Files: r.files, Notes: r.notes, origins: r.origins}
+	for key, origin := range r.origins {
+		if origin.Layer == LayerEnv || origin.Layer == LayerFlag {
+			if config.pinned == nil {
+				config.pinned = make(map[string]bool)
+			}
+			config.pinned[key] = true
+		}
+	}
+
+	// Files were checked on their own; values from the environment and flags
+	// are checked here
+	var problems []Problem
+	for _, p := range checkValues(config) {
+		if origin := resolved.Origin(p.Key); origin.Layer == LayerEnv || origin.Layer == LayerFlag {
+			p.File = origin.Source
+			problems = append(problems, p)
+		}
+	}
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	return resolved, nil
+}
+
+// lookupOverrideKey finds the key an override sets, including entries of
+// maps. The closest key is suggested for typos.
+func lookupOverrideKey(name string) (Key, error) {
+	key, err := lookupEditableKey(name)
+	if err == nil && name == ExtendsKey {
+		return Key{}, fmt.Errorf("%s can only be set in config files", ExtendsKey)
+	}
+	if err != nil {
+		var names []string
+		for _, k := range Keys() {
+			names = append(names, k.Name)
+		}
+		if suggestion := closest(name, names); suggestion != "" {
+			return Key{}, fmt.Errorf("%w (did you mean %q?)", err, suggestion)
+		}
+	}
+	return key, err
+}
+
+// Lint checks every config file that applies in opts.Dir, and the values set
+// by the environment and overrides. It returns the files and their problems.
+func Lint(opts Options) ([]string, []Problem, error) {
+	opts, env, err := opts.normalize()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var files []string
+	var problems []Problem
+	for _, layer := range configFiles(opts.Dir, env) {
+		fileProblems, err := LintFile(layer.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		files = append(files, layer.Path)
+		problems = append(problems, fileProblems...)
+	}
+	if len(problems) > 0 {
+		return files, problems, nil
+	}
+
+	// Resolving also checks the files they extend
+	resolved, err := Resolve(opts)
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return files, validationErr.Problems, nil
+	}
+	if err != nil {
+		return files, nil, err
+	}
+	return resolved.Files, nil, nil
+}
+
+// normalize fills in the defaults of opts and returns the environment as a
+// map
+func (opts Options) normalize() (Options, map[string]string, error) {
+	if opts.Dir == "" {
+		dir, err := os.Getwd()
+		if err != nil {
+			return opts, nil, err
+		}
+		opts.Dir = dir
+	}
+	dir, err := filepath.Abs(opts.Dir)
+	if err != nil {
+		return opts, nil, err
+	}
+	opts.Dir = dir
+	if opts.Env == nil {
+		opts.Env = os.Environ()
+	}
+
+	env := make(map[string]string)
+	for _, entry := range opts.Env {
+		if name, value, ok := strings.Cut(entry, "="); ok {
+			env[name] = value
+		}
+	}
+	return opts, env, nil
+}
+
+// fileLayer is a config file and the layer it belongs to
+type fileLayer struct {
+	Layer string
+	Path  string
+}
+
+// configFiles returns the config files that apply in dir, lowest precedence
+// first. Without a repository, a config file in dir is the repo layer.
+func configFiles(dir string, env map[string]string) []fileLayer {
+	var layers []fileLayer
+	seen := make(map[string]bool)
+	add := func(layer, path string) {
+		if abs, err := filepath.Abs(path); err == nil && !seen[abs] {
+			seen[abs] = true
+			layers = append(layers, fileLayer{Layer: layer, Path: path})
+		}
+	}
+
+	for _, path := range UserConfigPaths(env) {
+		if fileExists(path) {
+			add(LayerUser, path)
+		}
+	}
+
+	root := findRepoRoot(dir)
+	if root == "" {
+		root = dir
+	}
+	if path := findConfigFile(root); path != "" {
+		add(LayerRepo, path)
+	}
+
+	// Directories from the top of the repository down to dir
+	var dirs []string
+	for d := filepath.Clean(dir); d != filepath.Clean(root) && strings.HasPrefix(d, root); d = filepath.Dir(d) {
+		dirs = append([]string{d}, dirs...)
+	}
+	for _, d := range dirs {
+		if path := findConfigFile(d); path != "" {
+			add(LayerDir, path)
+		}
+	}
+
+	return layers
+}
+
+// UserConfigPaths returns the user config files, lowest precedence first:
+// the legacy ~/.gitcommit.yaml and $XDG_CONFIG_HOME/gitai/config.yaml
+func UserConfigPaths(env map[string]string) []string {
+	home := env["HOME"]
+	var paths []string
+	if home != "" {
+		if legacy := findConfigFile(home); legacy != "" {
+			paths = append(paths, legacy)
+		}
+	}
+
+	xdg := env["XDG_CONFIG_HOME"]
+	if xdg == "" && home != "" {
+		xdg = filepath.Join(home, ".config")
+	}
+	if xdg != "" {
+		paths = append(paths, filepath.Join(xdg, "gitai", "config.yaml"))
+	}
+	return paths
+}
+
+// GlobalConfigPath returns the user config file to edit:
+// $XDG_CONFIG_HOME/gitai/config.yaml, or ~/.gitcommit.yaml when only that
+// one exists. The file may not exist yet.
+func GlobalConfigPath(opts Options) (string, error) {
+	_, env, err := opts.normalize()
+	if err != nil {
+		return "", err
+	}
+	paths := UserConfigPaths(env)
+	if len(paths) == 0 {
+		return "", fmt.Errorf("cannot find the user config directory: HOME is not set")
+	}
+	xdg := paths[len(paths)-1]
+	if len(paths) > 1 && !fileExists(xdg) && fileExists(paths[0]) {
+		return paths[0], nil
+	}
+	return xdg, nil
+}
+
+// RepoConfigPath returns the config file at the top of the repository that
+// contains opts.Dir, or in opts.Dir outside of repositories. The file may not
+// exist yet.
+func RepoConfigPath(opts Options) (string, error) {
+	opts, _, err := opts.normalize()
+	if err != nil {
+		return "", err
+	}
+	root := findRepoRoot(opts.Dir)
+	if root == "" {
+		root = opts.Dir
+	}
+	if path := findConfigFile(root); path != "" {
+		return path, nil
+	}
+	return filepath.Join(root, FileNames[0]), nil
+}
+
+// findConfigFile returns the config file in dir, or ""
+func findConfigFile(dir string) string {
+	for _, name := range FileNames {
+		if path := filepath.Join(dir, name); fileExists(path) {
+			return path
+		}
+	}
+	return ""
+}
+
+// findRepoRoot returns the closest directory above or at dir that contains
+// .git, or ""
+func findRepoRoot(dir string) string {
+	for d := filepath.Clean(dir); ; d = filepath.Dir(d) {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			return d
+		}
+		if filepath.Dir(d) == d {
+			return ""
+		}
+	}
+}
+
+// fileExists reports whether path is a regular file
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// resolver merges layers into a generic YAML tree
+type resolver struct {
+	tree     map[string]interface{}
+	origins  map[string]Origin
+	env      map[string]string // For paths and caches of extended files
+	files    []string
+	problems []Problem // Problems of the files merged so far
+	notes    []string  // Migration notes of the files merged so far
+}
+
+// newResolver starts from the built-in defaults. Keys without an origin are
+// defaults, so none are recorded for them.
+func newResolver(env map[string]string) (*resolver, error) {
+	data, err := yaml.Marshal(DefaultConfig())
+	if err != nil {
+		return nil, err
+	}
+	r := &resolver{tree: map[string]interface{}{}, origins: make(map[string]Origin), env: env}
+	if err := yaml.Unmarshal(data, &r.tree); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// mergeFile merges a config file into the tree
+func (r *resolver) mergeFile(layer fileLayer) error {
+	data, err := os.ReadFile(layer.Path)
+	if err != nil {
+		return err
+	}
+	return r.mergeData(layer, layer.Path, data, nil)
+}
+
+// mergeData merges config data read from source. The files it extends are
+// merged first, in the same layer, so that its own settings override theirs.
+// chain holds the sources extending this one.
+func (r *resolver) mergeData(layer fileLayer, source string, data []byte, chain []string) error {
+	var tree map[string]interface{}
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return fmt.Errorf("invalid config file format at %s: %w\nCheck .gitcommit.yaml syntax", source, err)
+	}
+
+	// Checking the file on its own reports problems against the right lines
+	r.problems = append(r.problems, lintData(source, data)...)
+	r.notes = append(r.notes, migrationNotes(fileLayer{Layer: layer.Layer, Path: source}, data)...)
+
+	refs, err := extendsOf(tree)
+	if err != nil {
+		refs = nil // Reported by lintData
+	}
+	if len(refs) > 0 && len(chain) >= maxExtendsDepth {
+		return fmt.Errorf("%s: too many levels of %s", source, ExtendsKey)
+	}
+	for _, ref := range refs {
+		extended, extendedData, stale, err := loadExtends(ref, source, r.env)
+		if err != nil {
+			return fmt.Errorf("%s: %s %s: %w", source, ExtendsKey, ref, err)
+		}
+		if extended == source || contains(chain, extended) {
+			return fmt.Errorf("%s: %s %s: files extend each other", source, ExtendsKey, ref)
+		}
+		if stale {
+			r.notes = append(r.notes, fmt.Sprintf("%s: cannot fetch %s, using the copy fetched earlier", source, ref))
+		}
+		if err := r.mergeData(layer, extended, extendedData, append(chain, source)); err != nil {
+			return err
+		}
+	}
+	delete(tree, ExtendsKey)
+
+	merge(r.tree, tree, "", Origin{Layer: layer.Layer, Source: source}, r.origins)
+	r.files = append(r.files, source)
+	return nil
+}
+
+// set sets one key from a string, as given in the environment or a flag
+func (r *resolver) set(key Key, raw string, origin Origin) error {
+	value, err := key.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", origin.Source, err)
+	}
+	r.setValue(key.Name, value, origin)
+	return nil
+}
+
+// setValue sets a dotted key by merging it like a file
+func (r *resolver) setValue(name string, value interface{}, origin Origin) {
+	tree := value
+	parts := strings.Split(name, ".")
+	for i := len(parts) - 1; i >= 0; i-- {
+		tree = map[string]interface{}{parts[i]: tree}
+	}
+	merge(r.tree, tree.(map[string]interface{}), "", origin, r.origins)
+}
+
+// decode turns the merged tree into a Config
+func (r *resolver) decode() (*Config, error) {
+	data, err := yaml.Marshal(r.tree)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return config, nil
+}
+
+// merge deep-merges src into dst and records the origin of every key src sets
+func merge(dst, src map[string]interface{}, prefix string, origin Origin, origins map[string]Origin) {
+	for name, value := range src {
+		key := joinKey(prefix, name)
+		if srcMap, ok := value.(map[string]interface{}); ok && len(srcMap) > 0 {
+			dstMap, ok := dst[name].(map[string]interface{})
+			if !ok {
+				dstMap = make(map[string]interface{})
+				dst[name] = dstMap
+			}
+			merge(dstMap, srcMap, key, origin, origins)
+			continue
+		}
+
+		dst[name] = value
+		for k := range origins {
+			if strings.HasPrefix(k, key+".") {
+				delete(origins, k)
+			}
+		}
+		origins[key] = origin
+	}
+}
+
+// joinKey joins a parent key and a name with "."
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// parentKey returns the key one level up, or ""
+func parentKey(key string) string {
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		return key[:i]
+	}
+	return ""
+}
+
+// Key is a configuration key that can be set on its own
+type Key struct {
+	Name string       // Dotted name, e.g. "diff_analysis.enabled"
+	Type reflect.Type // Go type of the value
+}
+
+// Parse converts a string from the environment or a flag into a value of
+// the key's type. Strings are taken as they are, lists of strings may be
+// comma-separated, and everything else is parsed as YAML, e.g. "true",
+// "[a, b]" or "{secret: warn}".
+func (k Key) Parse(raw string) (interface{}, error) {
+	if k.Type.Kind() == reflect.String {
+		return raw, nil
+	}
+
+	text := strings.TrimSpace(raw)
+	if k.Type.Kind() == reflect.Slice && k.Type.Elem().Kind() == reflect.String && !strings.HasPrefix(text, "[") {
+		var items []interface{}
+		for _, item := range strings.Split(text, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items, nil
+	}
+
+	typed := reflect.New(k.Type)
+	if err := yaml.Unmarshal([]byte(text), typed.Interface()); err != nil {
+		return nil, fmt.Errorf("%q is not a valid %s", raw, typeName(k.Type))
+	}
+	var value interface{}
+	if err := yaml.Unmarshal([]byte(text), &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// typeName describes a Go type in configuration terms
+func typeName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int64:
+		return "number"
+	case reflect.Slice:
+		return "list"
+	case reflect.Map:
+		return "map"
+	default:
+		return t.Kind().String()
+	}
+}
+
+// Keys returns every key of Config in declaration order. Nested sections
+// contribute their fields; lists and maps are single keys.
+func Keys() []Key {
+	return structKeys(reflect.TypeOf(Config{}), "")
+}
+
+// structKeys lists the keys of a struct type
+func structKeys(t reflect.Type, prefix string) []Key {
+	var keys []Key
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		key := joinKey(prefix, name)
+		if field.Type.Kind() == reflect.Struct {
+			keys = append(keys, structKeys(field.Type, key)...)
+			continue
+		}
+		keys = append(keys, Key{Name: key, Type: field.Type})
+	}
+	return keys
+}
+
+// LookupKey finds a key by its dotted name
+func LookupKey(name string) (Key, bool) {
+	for _, key := range Keys() {
+		if key.Name == name {
+			return key, true
+		}
+	}
+	return Key{}, false
+}
+
+// EnvName returns the environment variable for a key, e.g.
+// GITAI_DIFF_ANALYSIS_ENABLED for diff_analysis.enabled
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// Value returns the resolved value of a key, including entries of maps such
+// as changelog.sections.feat, and whether it is set
+func (r *Resolved) Value(key string) (interface{}, bool, error) {
+	if _, err := lookupEditableKey(key); err != nil {
+		return nil, false, err
+	}
+	data, err := yaml.Marshal(r.Config)
+	if err != nil {
+		return nil, false, err
+	}
+	var tree map[string]interface{}
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil, false, err
+	}
+	value := lookup(tree, key)
+	return value, value != nil, nil
+}
+
+// Setting is a resolved value with its origin
+type Setting struct {
+	Key    string
+	Value  interface{}
+	Origin Origin
+}
+
+// Settings lists every key with its value and origin. Entries of maps are
+// listed one by one, since layers merge them separately.
+func (r *Resolved) Settings() ([]Setting, error) {
+	data, err := yaml.Marshal(r.Config)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]interface{}
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+
+	var settings []Setting
+	for _, key := range Keys() {
+		value := lookup(tree, key.Name)
+		if entries, ok := value.(map[string]interface{}); ok && key.Type.Kind() == reflect.Map && len(entries) > 0 {
+			names := make([]string, 0, len(entries))
+			for name := range entries {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				entry := key.Name + "." + name
+				settings = append(settings, Setting{Key: entry, Value: entries[name], Origin: r.Origin(entry)})
+			}
+			continue
+		}
+		if value == nil {
+			value = reflect.Zero(key.Type).Interface()
+		}
+		settings = append(settings, Setting{Key: key.Name, Value: value, Origin: r.Origin(key.Name)})
+	}
+	return settings, nil
+}
+
+// lookup returns the value at a dotted key of a tree, or nil
+func lookup(tree map[string]interface{}, key string) interface{} {
+	var value interface{} = tree
+	for _, part := range strings.Split(key, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[part]
+	}
+	return value
+}
@@ -0,0 +1,414 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeFile creates a file with its directories
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// layeredTree creates a home directory and a monorepo with a package:
+//
+//	home/.config/gitai/config.yaml
+//	repo/.git
+//	repo/.gitcommit.yaml
+//	repo/packages/api/.gitcommit.yaml
+func layeredTree(t *testing.T, user, repo, dir string) (home, root, pkg string) {
+	t.Helper()
+	base := t.TempDir()
+	home = filepath.Join(base, "home")
+	root = filepath.Join(base, "repo")
+	pkg = filepath.Join(root, "packages", "api")
+
+	if err := os.MkdirAll(filepath.Join(root, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(pkg, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if user != "" {
+		writeFile(t, filepath.Join(home, ".config", "gitai", "config.yaml"), user)
+	}
+	if repo != "" {
+		writeFile(t, filepath.Join(root, ".gitcommit.yaml"), repo)
+	}
+	if dir != "" {
+		writeFile(t, filepath.Join(pkg, ".gitcommit.yaml"), dir)
+	}
+	return home, root, pkg
+}
+
+func TestResolveLayers(t *testing.T) {
+	user := "model: user-model\nlanguage: zh\nscopes: [user]\n"
+	repo := "language: ja\nscopes: [api, web]\nchangelog:\n  format: markdown\n  sections:\n    feat: Features\n"
+	dir := "scopes: [handlers]\nchangelog:\n  sections:\n    fix: Fixes\n"
+
+	tests := []struct {
+		name      string
+		atPackage bool
+		env       []string
+		overrides []Override
+		check     func(t *testing.T, r *Resolved)
+	}{
+		{
+			name: "repo overrides user",
+			check: func(t *testing.T, r *Resolved) {
+				if r.Config.Model != "user-model" || r.Config.Language != "ja" {
+					t.Errorf("got model %q language %q", r.Config.Model, r.Config.Language)
+				}
+				if got := r.Origin("language").Layer; got != LayerRepo {
+					t.Errorf("language origin = %s, want repo", got)
+				}
+				if got := r.Origin("model").Layer; got != LayerUser {
+					t.Errorf("model origin = %s, want user", got)
+				}
+				if got := r.Origin("template").Layer; got != LayerDefault {
+					t.Errorf("template origin = %s, want default", got)
+				}
+			},
+		},
+		{
+			name:      "directory overrides repo and lists replace",
+			atPackage: true,
+			check: func(t *testing.T, r *Resolved) {
+				if !reflect.DeepEqual(r.Config.Scopes, []string{"handlers"}) {
+					t.Errorf("scopes = %v, want [handlers]", r.Config.Scopes)
+				}
+				if got := r.Origin("scopes"); got.Layer != LayerDir || !strings.HasSuffix(got.Source, filepath.Join("api", ".gitcommit.yaml")) {
+					t.Errorf("scopes origin = %s", got)
+				}
+			},
+		},
+		{
+			name:      "maps merge key by key",
+			atPackage: true,
+			check: func(t *testing.T, r *Resolved) {
+				want := map[string]string{"feat": "Features", "fix": "Fixes"}
+				if !reflect.DeepEqual(r.Config.Changelog.Sections, want) {
+					t.Errorf("sections = %v, want %v", r.Config.Changelog.Sections, want)
+				}
+				if r.Config.Changelog.Format != "markdown" {
+					t.Errorf("format = %q, want markdown", r.Config.Changelog.Format)
+				}
+				if got := r.Origin("changelog.sections.feat").Layer; got != LayerRepo {
+					t.Errorf("sections.feat origin = %s, want repo", got)
+				}
+				if got := r.Origin("changelog.sections.fix").Layer; got != LayerDir {
+					t.Errorf("sections.fix origin = %s, want dir", got)
+				}
+			},
+		},
+		{
+			name:      "environment overrides files",
+			atPackage: true,
+			env:       []string{"GITAI_LANGUAGE=fr", "GITAI_SCOPES=a, b", "GITAI_DIFF_ANALYSIS_CONTEXT_LINES=5"},
+			check: func(t *testing.T, r *Resolved) {
+				if r.Config.Language != "fr" || r.Config.DiffAnalysis.Context() != 5 {
+					t.Errorf("got language %q context lines %d", r.Config.Language, r.Config.DiffAnalysis.Context())
+				}
+				if !reflect.DeepEqual(r.Config.Scopes, []string{"a", "b"}) {
+					t.Errorf("scopes = %v, want [a b]", r.Config.Scopes)
+				}
+				if got := r.Origin("language"); got.Layer != LayerEnv || got.Source != "GITAI_LANGUAGE" {
+					t.Errorf("language origin = %s", got)
+				}
+				if r.Config.DiffAnalysis.Enabled == nil || !*r.Config.DiffAnalysis.Enabled {
+					t.Error("setting one diff_analysis key reset the others")
+				}
+			},
+		},
+		{
+			name:      "flags override environment",
+			atPackage: true,
+			env:       []string{"GITAI_MODEL=env-model"},
+			overrides: []Override{{Key: "model", Value: "flag-model", Source: "--model"}},
+			check: func(t *testing.T, r *Resolved) {
+				if r.Config.Model != "flag-model" {
+					t.Errorf("model = %q, want flag-model", r.Config.Model)
+				}
+				if got := r.Origin("model").String(); got != "flag (--model)" {
+					t.Errorf("model origin = %s", got)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home, root, pkg := layeredTree(t, user, repo, dir)
+			opts := Options{Dir: root, Env: append([]string{"HOME=" + home}, tt.env...), Overrides: tt.overrides}
+			if tt.atPackage {
+				opts.Dir = pkg
+			}
+			resolved, err := Resolve(opts)
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			tt.check(t, resolved)
+		})
+	}
+}
+
+func TestResolveUserFiles(t *testing.T) {
+	base := t.TempDir()
+	home := filepath.Join(base, "home")
+	xdg := filepath.Join(base, "xdg")
+	writeFile(t, filepath.Join(home, ".gitcommit.yaml"), "model: legacy\nlanguage: de\n")
+	writeFile(t, filepath.Join(xdg, "gitai", "config.yaml"), "model: xdg\n")
+	work := filepath.Join(base, "work")
+	if err := os.MkdirAll(work, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	resolved, err := Resolve(Options{Dir: work, Env: []string{"HOME=" + home, "XDG_CONFIG_HOME=" + xdg}})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if resolved.Config.Model != "xdg" || resolved.Config.Language != "de" {
+		t.Errorf("got model %q language %q, want xdg and de", resolved.Config.Model, resolved.Config.Language)
+	}
+	if len(resolved.Files) != 2 {
+		t.Errorf("files = %v, want the legacy and the XDG file", resolved.Files)
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		repo      string
+		env       []string
+		overrides []Override
+		wantErr   string
+	}{
+		{"bad file", "model: [a, b]\n", nil, nil, "cannot unmarshal"},
+		{"unknown key in file", "subjct_length: short\n", nil, nil, `unknown key "subjct_length"`},
+		{"bad env choice", "", []string{"GITAI_SUBJECT_LENGTH=medium"}, nil, "GITAI_SUBJECT_LENGTH: subject_length"},
+		{"bad env value", "", []string{"GITAI_MAX_DIFF_LENGTH=lots"}, nil, "GITAI_MAX_DIFF_LENGTH"},
+		{"unknown override", "", nil, []Override{{Key: "no_such_key", Value: "1", Source: "-c"}}, "unknown configuration key"},
+		{"misspelled override", "", nil, []Override{{Key: "subject_lenght", Value: "short", Source: "-c subject_lenght"}}, `did you mean "subject_length"?`},
+		{"extends override", "", nil, []Override{{Key: "extends", Value: "angular", Source: "-c extends"}}, "extends can only be set in config files"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home, root, _ := layeredTree(t, "", tt.repo, "")
+			_, err := Resolve(Options{Dir: root, Env: append([]string{"HOME=" + home}, tt.env...), Overrides: tt.overrides})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Resolve() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKeyParse(t *testing.T) {
+	tests := []struct {
+		key  string
+		raw  string
+		want interface{}
+	}{
+		{"model", "llama3:8b", "llama3:8b"},
+		{"detailed_commit", "false", false},
+		{"max_diff_length", "4000", 4000},
+		{"scopes", "api, web", []interface{}{"api", "web"}},
+		{"scopes", "[api]", []interface{}{"api"}},
+		{"changelog.sections", "{feat: New}", map[string]interface{}{"feat": "New"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.raw, func(t *testing.T) {
+			key, ok := LookupKey(tt.key)
+			if !ok {
+				t.Fatalf("LookupKey(%q) not found", tt.key)
+			}
+			got, err := key.Parse(tt.raw)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSettings(t *testing.T) {
+	home, root, _ := layeredTree(t, "", "changelog:\n  sections:\n    feat: Features\n", "")
+	resolved, err := Resolve(Options{Dir: root, Env: []string{"HOME=" + home}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, err := resolved.Settings()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := false
+	for _, s := range settings {
+		if s.Key == "changelog.sections.feat" {
+			found = true
+			if s.Value != "Features" || s.Origin.Layer != LayerRepo {
+				t.Errorf("got %v from %s", s.Value, s.Origin)
+			}
+		}
+	}
+	if !found {
+		t.Error("map entries are not listed one by one")
+	}
+}
+
+func TestParseOverride(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    Override
+		wantErr bool
+	}{
+		{arg: "model=llama3", want: Override{Key: "model", Value: "llama3", Source: "-c model"}},
+		{arg: "custom_prompt=a=b", want: Override{Key: "custom_prompt", Value: "a=b", Source: "-c custom_prompt"}},
+		{arg: "scopes=", want: Override{Key: "scopes", Value: "", Source: "-c scopes"}},
+		{arg: "model", wantErr: true},
+		{arg: "=llama3", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseOverride(tt.arg, "-c")
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseOverride(%q) = %+v, want an error", tt.arg, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseOverride(%q) = %+v, %v, want %+v", tt.arg, got, err, tt.want)
+		}
+	}
+}
+
+func TestEveryKeyHasEnvAndOverride(t *testing.T) {
+	// Valid values of keys whose type alone does not make one
+	samples := map[string]string{
+		"language":                    "de",
+		"languages":                   "de, fr",
+		"types":                       "[{name: feat, desc: Features}]",
+		"subject_length":              "short",
+		"changelog.format":            "markdown",
+		"changelog.sections":          "{feat: Features}",
+		"precheck.rules":              "{todo: off}",
+		"rules":                       "[{when: {branches: [main]}, set: {language: de}}]",
+		"diff_analysis.context_lines": "3",
+	}
+	// Maps record the origin of each entry
+	entries := map[string]string{
+		"changelog.sections": "changelog.sections.feat",
+		"precheck.rules":     "precheck.rules.todo",
+	}
+	byKind := map[reflect.Kind]string{
+		reflect.String: "value",
+		reflect.Ptr:    "true",
+		reflect.Int:    "3",
+		reflect.Int64:  "3",
+		reflect.Slice:  "a, b",
+	}
+
+	home, root, _ := layeredTree(t, "", "", "")
+	for _, key := range Keys() {
+		t.Run(key.Name, func(t *testing.T) {
+			raw, ok := samples[key.Name]
+			if !ok {
+				if raw, ok = byKind[key.Type.Kind()]; !ok {
+					t.Fatalf("no sample value for %s of type %s", key.Name, key.Type)
+				}
+			}
+
+			originKey := key.Name
+			if entry, ok := entries[key.Name]; ok {
+				originKey = entry
+			}
+
+			env := EnvName(key.Name)
+			resolved, err := Resolve(Options{Dir: root, Env: []string{"HOME=" + home, env + "=" + raw}})
+			if err != nil {
+				t.Fatalf("Resolve() with %s error = %v", env, err)
+			}
+			if origin := resolved.Origin(originKey); origin.Layer != LayerEnv || origin.Source != env {
+				t.Errorf("origin = %s, want %s", origin, env)
+			}
+
+			override, err := ParseOverride(key.Name+"="+raw, "-c")
+			if err != nil {
+				t.Fatal(err)
+			}
+			resolved, err = Resolve(Options{Dir: root, Env: []string{"HOME=" + home}, Overrides: []Override{override}})
+			if err != nil {
+				t.Fatalf("Resolve() with -c error = %v", err)
+			}
+			if origin := resolved.Origin(originKey); origin.Layer != LayerFlag {
+				t.Errorf("origin = %s, want the flag layer", origin)
+			}
+		})
+	}
+}
+
+func TestPrecedence(t *testing.T) {
+	// From lowest to highest precedence
+	layers := []string{"user", "repo", "dir", "rule", "env", "-c", "--model"}
+
+	// Every combination of layers setting model: the highest one wins
+	for set := 0; set < 1<<len(layers); set++ {
+		var names []string
+		want := DefaultConfig().Model
+		for i, layer := range layers {
+			if set&(1<<i) != 0 {
+				names = append(names, layer)
+				want = layer
+			}
+		}
+
+		t.Run(strings.Join(names, ","), func(t *testing.T) {
+			has := func(layer string) bool { return contains(names, layer) }
+			file := func(layer string) string {
+				if has(layer) {
+					return "model: " + layer + "\n"
+				}
+				return ""
+			}
+			repo := file("repo")
+			if has("rule") {
+				repo += "rules:\n  - when: {branches: [main]}\n    set: {model: rule}\n"
+			}
+			home, _, pkg := layeredTree(t, file("user"), repo, file("dir"))
+
+			opts := Options{Dir: pkg, Env: []string{"HOME=" + home}}
+			if has("env") {
+				opts.Env = append(opts.Env, "GITAI_MODEL=env")
+			}
+			if has("-c") {
+				opts.Overrides = append(opts.Overrides, Override{Key: "model", Value: "-c", Source: "-c model"})
+			}
+			if has("--model") {
+				opts.Overrides = append(opts.Overrides, Override{Key: "model", Value: "--model", Source: "--model"})
+			}
+
+			resolved, err := Resolve(opts)
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if _, err := resolved.Config.ApplyRules(RuleContext{Branch: "main"}); err != nil {
+				t.Fatalf("ApplyRules() error = %v", err)
+			}
+			if resolved.Config.Model != want {
+				t.Errorf("model = %q, want %q", resolved.Config.Model, want)
+			}
+		})
+	}
+}
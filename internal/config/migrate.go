@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// diffAnalysisSwitches are the diff_analysis settings that earlier versions
+// reset to true when context_lines was missing or 0
+var diffAnalysisSwitches = []string{"enabled", "include_function_names", "include_imports", "smart_truncate"}
+
+// migrationNotes explains settings of a config file that earlier versions
+// read differently. Setting the keys explicitly silences the notes.
+func migrationNotes(layer fileLayer, data []byte) []string {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+	root := doc.Content[0]
+
+	var notes []string
+	if analysis := mappingValue(root, "diff_analysis"); analysis != nil && analysis.Kind == yaml.MappingNode {
+		contextLines := mappingValue(analysis, "context_lines")
+		if contextLines == nil || contextLines.Value == "0" {
+			var disabled []string
+			for _, name := range diffAnalysisSwitches {
+				if value := mappingValue(analysis, name); value != nil && value.Value == "false" {
+					disabled = append(disabled, name)
+				}
+			}
+			if len(disabled) > 0 {
+				notes = append(notes, fmt.Sprintf("%s: diff_analysis %v set to false was ignored by earlier versions because context_lines was not set; it now takes effect", layer.Path, disabled))
+			}
+		}
+		if contextLines != nil && contextLines.Value == "0" {
+			notes = append(notes, fmt.Sprintf("%s: diff_analysis.context_lines: 0 now means no context lines; earlier versions used 3. Set it to 3 to keep the old behavior", layer.Path))
+		}
+	}
+
+	// Complete configs without detailed_commit got messages without a body
+	if layer.Layer != LayerDir && mappingValue(root, "types") != nil && mappingValue(root, "detailed_commit") == nil {
+		notes = append(notes, fmt.Sprintf("%s: detailed_commit is not set; it now defaults to true, earlier versions treated it as false. Set detailed_commit: false to keep messages without a body", layer.Path))
+	}
+	return notes
+}
+
+// mappingValue returns the value of key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMigrationNotes(t *testing.T) {
+	tests := []struct {
+		name    string
+		layer   string
+		content string
+		want    []string // Parts of the notes, in order
+	}{
+		{
+			name:    "current file",
+			layer:   LayerRepo,
+			content: "types:\n  - name: feat\ndetailed_commit: false\ndiff_analysis:\n  enabled: false\n  context_lines: 3\n",
+		},
+		{
+			name:    "analysis switched off without context lines",
+			layer:   LayerRepo,
+			content: "diff_analysis:\n  enabled: false\n  include_imports: false\n",
+			want:    []string{"[enabled include_imports] set to false was ignored"},
+		},
+		{
+			name:    "zero context lines",
+			layer:   LayerRepo,
+			content: "diff_analysis:\n  enabled: true\n  context_lines: 0\n",
+			want:    []string{"context_lines: 0 now means no context lines"},
+		},
+		{
+			name:    "complete config without detailed_commit",
+			layer:   LayerUser,
+			content: "model: llama3\ntypes:\n  - name: feat\n",
+			want:    []string{"detailed_commit is not set"},
+		},
+		{
+			name:    "package config without detailed_commit",
+			layer:   LayerDir,
+			content: "types:\n  - name: feat\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notes := migrationNotes(fileLayer{Layer: tt.layer, Path: ".gitcommit.yaml"}, []byte(tt.content))
+			if len(notes) != len(tt.want) {
+				t.Fatalf("migrationNotes() = %v, want %d notes", notes, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(notes[i], want) || !strings.HasPrefix(notes[i], ".gitcommit.yaml: ") {
+					t.Errorf("note %d = %q, want it to contain %q", i, notes[i], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/xyue92/gitai/internal/ignore"
+	"gopkg.in/yaml.v3"
+)
+
+// Rule changes settings when the commit matches its conditions, e.g. a
+// ticket is required on release branches
+type Rule struct {
+	Name string                 `yaml:"name,omitempty"` // Shown when the rule applies
+	When RuleMatch              `yaml:"when"`           // All given conditions must match
+	Set  map[string]interface{} `yaml:"set,omitempty"`  // Dotted keys and their values
+	Type string                 `yaml:"type,omitempty"` // Commit type to use without asking
+}
+
+// RuleMatch holds the conditions of a rule. Empty conditions match anything.
+type RuleMatch struct {
+	Branches []string `yaml:"branches,omitempty"` // Globs of the current branch, e.g. release/*
+	Paths    []string `yaml:"paths,omitempty"`    // Gitignore-style patterns every changed file must match
+	Types    []string `yaml:"types,omitempty"`    // Commit types
+}
+
+// RuleContext is what rules are matched against
+type RuleContext struct {
+	Branch string   // Empty when HEAD is detached
+	Files  []string // Changed files, relative to the top of the repository
+	Type   string   // Commit type; empty while it is not known yet
+}
+
+// String returns the name of the rule, or its conditions
+func (r Rule) String() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	var conditions []string
+	if len(r.When.Branches) > 0 {
+		conditions = append(conditions, "branch "+strings.Join(r.When.Branches, ", "))
+	}
+	if len(r.When.Paths) > 0 {
+		conditions = append(conditions, "paths "+strings.Join(r.When.Paths, ", "))
+	}
+	if len(r.When.Types) > 0 {
+		conditions = append(conditions, "type "+strings.Join(r.When.Types, ", "))
+	}
+	return strings.Join(conditions, "; ")
+}
+
+// Changes describes what the rule sets, e.g. "require_ticket: true"
+func (r Rule) Changes() string {
+	var changes []string
+	for _, key := range sortedKeys(r.Set) {
+		data, err := yaml.Marshal(r.Set[key])
+		if err != nil {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("%s: %s", key, strings.TrimSpace(string(data))))
+	}
+	if r.Type != "" {
+		changes = append(changes, "type: "+r.Type)
+	}
+	return strings.Join(changes, ", ")
+}
+
+// Matches reports whether the rule applies. Rules with type conditions
+// never match while the type is not known.
+func (r Rule) Matches(ctx RuleContext) bool {
+	if len(r.When.Branches) > 0 && !matchBranch(r.When.Branches, ctx.Branch) {
+		return false
+	}
+	if len(r.When.Paths) > 0 && !matchAllFiles(r.When.Paths, ctx.Files) {
+		return false
+	}
+	if len(r.When.Types) > 0 && (ctx.Type == "" || !contains(r.When.Types, ctx.Type)) {
+		return false
+	}
+	return true
+}
+
+// matchBranch reports whether branch matches one of the globs
+func matchBranch(patterns []string, branch string) bool {
+	if branch == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAllFiles reports whether every file matches one of the patterns
+func matchAllFiles(patterns []string, files []string) bool {
+	matcher, err := ignore.New(patterns)
+	if err != nil || len(files) == 0 {
+		return false
+	}
+	for _, file := range files {
+		if !matcher.Match(file) {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplyRules applies the rules that match ctx, in order, and returns them.
+// Later rules override earlier ones, and keys set by the environment or flags
+// override the rules. Applying the rules again with more of
+// ctx known, e.g. the commit type, gives the same result as applying them once.
+func (c *Config) ApplyRules(ctx RuleContext) ([]Rule, error) {
+	var applied []Rule
+	for _, rule := range c.Rules {
+		if !rule.Matches(ctx) {
+			continue
+		}
+		if err := c.applySettings(rule.Set); err != nil {
+			return nil, fmt.Errorf("rule %q: %w", rule, err)
+		}
+		applied = append(applied, rule)
+	}
+	return applied, nil
+}
+
+// RuleType returns the commit type the last of the applied rules sets, or ""
+func RuleType(applied []Rule) string {
+	for i := len(applied) - 1; i >= 0; i-- {
+		if applied[i].Type != "" {
+			return applied[i].Type
+		}
+	}
+	return ""
+}
+
+// applySettings sets dotted keys of the configuration, keeping the rest
+func (c *Config) applySettings(settings map[string]interface{}) error {
+	if len(settings) == 0 {
+		return nil
+	}
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	r := &resolver{tree: map[string]interface{}{}, origins: make(map[string]Origin)}
+	if err := yaml.Unmarshal(data, &r.tree); err != nil {
+		return err
+	}
+
+	for _, name := range sortedKeys(settings) {
+		key, err := lookupRuleKey(name)
+		if err != nil {
+			return err
+		}
+		if err := checkType(key, settings[name]); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if !c.isPinned(key.Name) {
+			r.setValue(key.Name, settings[name], Origin{})
+		}
+	}
+
+	config, err := r.decode()
+	if err != nil {
+		return err
+	}
+	config.pinned = c.pinned
+	*c = *config
+	return nil
+}
+
+// isPinned reports whether the environment or a flag set key or a section
+// containing it
+func (c *Config) isPinned(key string) bool {
+	for k := key; k != ""; k = parentKey(k) {
+		if c.pinned[k] {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupRuleKey finds a key rules may set: any setting except the rules
+func lookupRuleKey(name string) (Key, error) {
+	if name == "rules" || strings.HasPrefix(name, "rules.") || name == ExtendsKey {
+		return Key{}, fmt.Errorf("rules cannot set %s", name)
+	}
+	return lookupEditableKey(name)
+}
+
+// checkType reports a value that does not fit the type of key
+func checkType(key Key, value interface{}) error {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(data, reflect.New(key.Type).Interface()); err != nil {
+		return fmt.Errorf("%s is not a valid %s", strings.TrimSpace(string(data)), typeName(key.Type))
+	}
+	return nil
+}
+
+// checkRules reports rules that can never apply, set nothing, or set unknown
+// keys or invalid values
+func checkRules(c *Config) []Problem {
+	var problems []Problem
+	add := func(key, format string, args ...interface{}) {
+		problems = append(problems, Problem{Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for i, rule := range c.Rules {
+		prefix := fmt.Sprintf("rules.%d", i)
+		when := rule.When
+		if len(when.Branches) == 0 && len(when.Paths) == 0 && len(when.Types) == 0 {
+			add(prefix, "rule without conditions; set the keys outside of rules instead")
+		}
+		if len(rule.Set) == 0 && rule.Type == "" {
+			add(prefix, "rule sets nothing; add set or type")
+		}
+		for j, pattern := range when.Branches {
+			if _, err := path.Match(pattern, ""); err != nil {
+				add(fmt.Sprintf("%s.when.branches.%d", prefix, j), "invalid pattern %q", pattern)
+			}
+		}
+		for j, pattern := range when.Paths {
+			if _, err := ignore.New([]string{pattern}); err != nil {
+				add(fmt.Sprintf("%s.when.paths.%d", prefix, j), "%v", err)
+			}
+		}
+		if len(c.Types) > 0 {
+			for j, name := range when.Types {
+				if c.GetTypeByName(name) == nil {
+					add(fmt.Sprintf("%s.when.types.%d", prefix, j), "unknown commit type %q", name)
+				}
+			}
+			if rule.Type != "" && c.GetTypeByName(rule.Type) == nil {
+				add(prefix+".type", "unknown commit type %q", rule.Type)
+			}
+		}
+
+		for _, name := range sortedKeys(rule.Set) {
+			setKey := prefix + ".set." + name
+			key, err := lookupRuleKey(name)
+			if err != nil {
+				add(setKey, "%v", err)
+				continue
+			}
+			if err := checkType(key, rule.Set[name]); err != nil {
+				add(setKey, "%v", err)
+				continue
+			}
+			// The value itself, e.g. a supported language
+			applied := DefaultConfig()
+			if err := applied.applySettings(map[string]interface{}{name: rule.Set[name]}); err != nil {
+				add(setKey, "%v", err)
+				continue
+			}
+			for _, p := range checkValues(applied) {
+				if p.Key == name || strings.HasPrefix(p.Key, name+".") {
+					add(setKey, "%s", p.Message)
+				}
+			}
+		}
+	}
+	return problems
+}
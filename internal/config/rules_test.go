@@ -0,0 +1,239 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const rulesYAML = `rules:
+  - name: tickets on release branches
+    when:
+      branches: ["release/*", "hotfix/*"]
+    set:
+      require_ticket: true
+  - when:
+      paths: ["docs/zh/**"]
+    set:
+      language: zh
+  - when:
+      paths: ["packages/cli/"]
+    set:
+      subject_length: short
+      diff_analysis.context_lines: 1
+  - when:
+      paths: ["*.md"]
+    type: docs
+  - when:
+      types: [fix]
+      branches: ["hotfix/*"]
+    set:
+      detailed_commit: false
+`
+
+func TestApplyRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     RuleContext
+		applied []string // Rules that apply
+		check   func(t *testing.T, c *Config)
+	}{
+		{
+			name: "no match",
+			ctx:  RuleContext{Branch: "main", Files: []string{"main.go", "README.md"}},
+			check: func(t *testing.T, c *Config) {
+				if c.WantTicket() || c.Language != "en" || c.SubjectLength != "normal" {
+					t.Errorf("config changed without a matching rule")
+				}
+			},
+		},
+		{
+			name:    "release branch",
+			ctx:     RuleContext{Branch: "release/1.2", Files: []string{"main.go"}},
+			applied: []string{"tickets on release branches"},
+			check: func(t *testing.T, c *Config) {
+				if !c.WantTicket() {
+					t.Error("require_ticket not set on a release branch")
+				}
+			},
+		},
+		{
+			name:    "only Chinese docs",
+			ctx:     RuleContext{Branch: "main", Files: []string{"docs/zh/index.md", "docs/zh/guide/start.md"}},
+			applied: []string{"paths docs/zh/**", "paths *.md"},
+			check: func(t *testing.T, c *Config) {
+				if c.Language != "zh" {
+					t.Errorf("language = %s, want zh", c.Language)
+				}
+			},
+		},
+		{
+			name:    "docs and code",
+			ctx:     RuleContext{Branch: "main", Files: []string{"docs/zh/index.md", "main.go"}},
+			applied: nil,
+		},
+		{
+			name:    "package with nested setting",
+			ctx:     RuleContext{Files: []string{"packages/cli/main.go"}},
+			applied: []string{"paths packages/cli/"},
+			check: func(t *testing.T, c *Config) {
+				if c.SubjectLength != "short" || c.DiffAnalysis.Context() != 1 || !c.DiffAnalysis.IsEnabled() {
+					t.Errorf("subject_length = %q, context_lines = %d, want short and 1 with the rest of diff_analysis kept",
+						c.SubjectLength, c.DiffAnalysis.Context())
+				}
+			},
+		},
+		{
+			name:    "type rules wait for the type",
+			ctx:     RuleContext{Branch: "hotfix/crash", Files: []string{"main.go"}},
+			applied: []string{"tickets on release branches"},
+			check: func(t *testing.T, c *Config) {
+				if !c.WantDetailedCommit() {
+					t.Error("rule for fix applied before the type was known")
+				}
+			},
+		},
+		{
+			name:    "type known",
+			ctx:     RuleContext{Branch: "hotfix/crash", Files: []string{"main.go"}, Type: "fix"},
+			applied: []string{"tickets on release branches", "branch hotfix/*; type fix"},
+			check: func(t *testing.T, c *Config) {
+				if c.WantDetailedCommit() || !c.WantTicket() {
+					t.Error("rules for the branch and the type did not both apply")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".gitcommit.yaml")
+			writeFile(t, path, rulesYAML)
+			c, err := loadFromFile(path)
+			if err != nil {
+				t.Fatalf("loadFromFile() error = %v", err)
+			}
+
+			applied, err := c.ApplyRules(tt.ctx)
+			if err != nil {
+				t.Fatalf("ApplyRules() error = %v", err)
+			}
+			var names []string
+			for _, rule := range applied {
+				names = append(names, rule.String())
+			}
+			if strings.Join(names, "|") != strings.Join(tt.applied, "|") {
+				t.Errorf("applied = %q, want %q", names, tt.applied)
+			}
+			if len(c.Rules) != 5 {
+				t.Errorf("rules = %d after applying, want 5", len(c.Rules))
+			}
+			if tt.check != nil {
+				tt.check(t, c)
+			}
+		})
+	}
+}
+
+func TestApplyRulesPrecedence(t *testing.T) {
+	home, root, _ := layeredTree(t, "", rulesYAML, "")
+	resolved, err := Resolve(Options{
+		Dir:       root,
+		Env:       []string{"HOME=" + home, "GITAI_SUBJECT_LENGTH=normal"},
+		Overrides: []Override{{Key: "language", Value: "ja", Source: "--language"}},
+	})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	c := resolved.Config
+	applied, err := c.ApplyRules(RuleContext{Files: []string{"docs/zh/a.md", "packages/cli/README.md"}})
+	if err != nil {
+		t.Fatalf("ApplyRules() error = %v", err)
+	}
+	if len(applied) != 1 {
+		t.Fatalf("applied = %v, want the rule for Markdown only", applied)
+	}
+
+	c.Rules[1].When.Paths = []string{"**"}
+	c.Rules[2].When.Paths = []string{"**"}
+	if _, err := c.ApplyRules(RuleContext{Files: []string{"main.go"}}); err != nil {
+		t.Fatalf("ApplyRules() error = %v", err)
+	}
+	if c.Language != "ja" || c.SubjectLength != "normal" {
+		t.Errorf("language = %s, subject_length = %s, want the flag and the environment to win over rules", c.Language, c.SubjectLength)
+	}
+	if c.DiffAnalysis.Context() != 1 {
+		t.Errorf("context_lines = %d, want 1 from the rule", c.DiffAnalysis.Context())
+	}
+}
+
+func TestRuleType(t *testing.T) {
+	c := &Config{Rules: []Rule{
+		{When: RuleMatch{Paths: []string{"*.md"}}, Type: "docs"},
+		{When: RuleMatch{Paths: []string{"CHANGELOG.md"}}, Type: "chore", Set: map[string]interface{}{"language": "en"}},
+	}}
+
+	applied, err := c.ApplyRules(RuleContext{Files: []string{"CHANGELOG.md"}})
+	if err != nil {
+		t.Fatalf("ApplyRules() error = %v", err)
+	}
+	if got := RuleType(applied); got != "chore" {
+		t.Errorf("RuleType() = %q, want the type of the last rule", got)
+	}
+	if got := applied[1].Changes(); got != "language: en, type: chore" {
+		t.Errorf("Changes() = %q", got)
+	}
+	if got := RuleType(nil); got != "" {
+		t.Errorf("RuleType(nil) = %q, want empty", got)
+	}
+}
+
+func TestLintRules(t *testing.T) {
+	content := `types:
+  - name: feat
+  - name: docs
+rules:
+  - set:
+      language: zh
+  - when:
+      branches: ["release/["]
+  - when:
+      types: [fix]
+    set:
+      languag: zh
+      subject_length: medium
+      max_diff_length: lots
+      rules: []
+`
+	want := []struct {
+		line int
+		key  string
+		msg  string
+	}{
+		{5, "rules.0", "rule without conditions"},
+		{7, "rules.1", "rule sets nothing"},
+		{8, "rules.1.when.branches.0", "invalid pattern"},
+		{10, "rules.2.when.types.0", `unknown commit type "fix"`},
+		{12, "rules.2.set.languag", `unknown configuration key "languag"`},
+		{13, "rules.2.set.subject_length", `"medium" is not one of short, normal`},
+		{14, "rules.2.set.max_diff_length", "lots is not a valid number"},
+		{15, "rules.2.set.rules", "rules cannot set rules"},
+	}
+
+	path := filepath.Join(t.TempDir(), ".gitcommit.yaml")
+	writeFile(t, path, content)
+	problems, err := LintFile(path)
+	if err != nil {
+		t.Fatalf("LintFile() error = %v", err)
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("LintFile() = %v, want %d problems", problems, len(want))
+	}
+	for i, w := range want {
+		p := problems[i]
+		if p.Line != w.line || p.Key != w.key || !strings.Contains(p.Message, w.msg) {
+			t.Errorf("problem %d = %s, want key %s with %q", i, p, w.key, w.msg)
+		}
+	}
+}
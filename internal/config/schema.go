@@ -0,0 +1,13 @@
+package config
+
+import _ "embed"
+
+// schema is the JSON Schema of config files, for validation in editors
+//
+//go:embed schema.json
+var schema []byte
+
+// Schema returns the JSON Schema of .gitcommit.yaml files
+func Schema() []byte {
+	return schema
+}
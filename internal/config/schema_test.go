@@ -0,0 +1,91 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// schemaNode is the part of a JSON Schema the tests look at
+type schemaNode struct {
+	Properties map[string]schemaNode `json:"properties"`
+	Enum       []string              `json:"enum"`
+}
+
+func loadSchema(t *testing.T) (schemaNode, map[string]schemaNode) {
+	t.Helper()
+	var doc struct {
+		schemaNode
+		Definitions map[string]schemaNode `json:"definitions"`
+	}
+	if err := json.Unmarshal(Schema(), &doc); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	return doc.schemaNode, doc.Definitions
+}
+
+func TestSchemaCoversKeys(t *testing.T) {
+	root, _ := loadSchema(t)
+
+	// Every key of Config is in the schema
+	for _, key := range Keys() {
+		node := root
+		for _, part := range strings.Split(key.Name, ".") {
+			child, ok := node.Properties[part]
+			if !ok {
+				t.Errorf("schema has no property for %s", key.Name)
+				break
+			}
+			node = child
+		}
+	}
+
+	// Every property of the schema is a key of Config
+	var walk func(node schemaNode, prefix string, typ reflect.Type)
+	walk = func(node schemaNode, prefix string, typ reflect.Type) {
+		for name, child := range node.Properties {
+			key := joinKey(prefix, name)
+			if key == ExtendsKey {
+				continue // Read from files only
+			}
+			field, ok := fieldByTag(typ, name)
+			if !ok {
+				t.Errorf("schema property %s is not a configuration key", key)
+				continue
+			}
+			if field.Kind() == reflect.Struct {
+				walk(child, key, field)
+			}
+		}
+	}
+	walk(root, "", reflect.TypeOf(Config{}))
+}
+
+func TestSchemaEnums(t *testing.T) {
+	root, definitions := loadSchema(t)
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"subject_length", root.Properties["subject_length"].Enum, SubjectLengths},
+		{"changelog.format", root.Properties["changelog"].Properties["format"].Enum, ChangelogFormats},
+		{"language", definitions["language"].Enum, LanguageCodes()},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("schema enum of %s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+// fieldByTag returns the type of the field of t with the YAML name
+func fieldByTag(t reflect.Type, name string) (reflect.Type, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0] == name {
+			return t.Field(i).Type, true
+		}
+	}
+	return nil, false
+}
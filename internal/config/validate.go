@@ -0,0 +1,355 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xyue92/gitai/internal/i18n"
+	"github.com/xyue92/gitai/internal/ignore"
+	"gopkg.in/yaml.v3"
+)
+
+// Allowed values of enumerated settings
+var (
+	SubjectLengths   = []string{"short", "normal"}
+	ChangelogFormats = []string{"keepachangelog", "markdown"}
+	PrecheckLevels   = []string{"off", "warn", "block"}
+)
+
+// typeErrorLinePattern splits the messages of yaml.TypeError
+var typeErrorLinePattern = regexp.MustCompile(`^line (\d+): (.*)$`)
+
+// Problem is a mistake in the configuration
+type Problem struct {
+	File    string // Empty for values from the environment or flags
+	Line    int    // 0 when the position is unknown
+	Column  int
+	Key     string // Dotted key; list items are numbered, e.g. "types.2.name"
+	Message string
+}
+
+// String returns "file:line:column: key: message", leaving out what is unknown
+func (p Problem) String() string {
+	var b strings.Builder
+	if p.File != "" {
+		b.WriteString(p.File)
+		if p.Line > 0 {
+			fmt.Fprintf(&b, ":%d:%d", p.Line, p.Column)
+		}
+		b.WriteString(": ")
+	}
+	if p.Key != "" {
+		b.WriteString(p.Key + ": ")
+	}
+	b.WriteString(p.Message)
+	return b.String()
+}
+
+// ValidationError is returned when the configuration has problems
+type ValidationError struct {
+	Problems []Problem
+}
+
+// Error lists the problems, one per line
+func (e *ValidationError) Error() string {
+	lines := []string{"invalid configuration:"}
+	for _, p := range e.Problems {
+		lines = append(lines, "  "+p.String())
+	}
+	lines = append(lines, "Run 'gitai config lint' to check your config files")
+	return strings.Join(lines, "\n")
+}
+
+// LintFile checks a config file: YAML syntax, unknown keys, value types and
+// the values themselves. Problems carry the line they were found on. The
+// error is only set when the file cannot be read.
+func LintFile(path string) ([]Problem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return lintData(path, data), nil
+}
+
+// lintData checks the content of a config file
+func lintData(path string, data []byte) []Problem {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return []Problem{{File: path, Message: strings.TrimPrefix(err.Error(), "yaml: ")}}
+	}
+	if len(doc.Content) == 0 {
+		return nil // Empty file
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return []Problem{{File: path, Line: root.Line, Column: root.Column, Message: "the configuration must be a map of keys to values"}}
+	}
+
+	problems := unknownKeys(root, reflect.TypeOf(Config{}), "")
+
+	var config Config
+	if err := root.Decode(&config); err != nil {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return append(problems, Problem{File: path, Message: err.Error()})
+		}
+		for _, message := range typeErr.Errors {
+			p := Problem{Message: message}
+			if m := typeErrorLinePattern.FindStringSubmatch(message); m != nil {
+				p.Line, _ = strconv.Atoi(m[1])
+				p.Message = m[2]
+			}
+			problems = append(problems, p)
+		}
+	} else {
+		for _, p := range checkValues(&config) {
+			if node := findNode(root, p.Key); node != nil {
+				p.Line, p.Column = node.Line, node.Column
+			}
+			problems = append(problems, p)
+		}
+	}
+
+	for i := range problems {
+		problems[i].File = path
+	}
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].Line < problems[j].Line })
+	return problems
+}
+
+// unknownKeys reports the keys of a mapping node that t does not have
+func unknownKeys(node *yaml.Node, t reflect.Type, prefix string) []Problem {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var problems []Problem
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := make(map[string]reflect.Type)
+		var names []string
+		for i := 0; i < t.NumField(); i++ {
+			name := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+			if name != "" && name != "-" {
+				fields[name] = t.Field(i).Type
+				names = append(names, name)
+			}
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valueNode := node.Content[i], node.Content[i+1]
+			key := joinKey(prefix, keyNode.Value)
+			if key == ExtendsKey {
+				problems = append(problems, checkExtends(valueNode)...)
+				continue
+			}
+			fieldType, ok := fields[keyNode.Value]
+			if !ok {
+				message := fmt.Sprintf("unknown key %q", keyNode.Value)
+				if suggestion := closest(keyNode.Value, names); suggestion != "" {
+					message += fmt.Sprintf(" (did you mean %q?)", suggestion)
+				}
+				problems = append(problems, Problem{Line: keyNode.Line, Column: keyNode.Column, Key: key, Message: message})
+				continue
+			}
+			problems = append(problems, unknownKeys(valueNode, fieldType, key)...)
+		}
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			problems = append(problems, unknownKeys(node.Content[i+1], t.Elem(), joinKey(prefix, node.Content[i].Value))...)
+		}
+	case t.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for i, item := range node.Content {
+			problems = append(problems, unknownKeys(item, t.Elem(), joinKey(prefix, strconv.Itoa(i)))...)
+		}
+	}
+	return problems
+}
+
+// checkExtends reports an extends value that is not a string or a list of
+// strings
+func checkExtends(node *yaml.Node) []Problem {
+	items := []*yaml.Node{node}
+	if node.Kind == yaml.SequenceNode {
+		items = node.Content
+	}
+	for _, item := range items {
+		if item.Kind != yaml.ScalarNode || item.Tag != "!!str" || item.Value == "" {
+			return []Problem{{Line: node.Line, Column: node.Column, Key: ExtendsKey, Message: "must be a preset name, a path or a git reference, or a list of them"}}
+		}
+	}
+	return nil
+}
+
+// closest returns the name within two edits of word, or ""
+func closest(word string, names []string) string {
+	best, bestDistance := "", 3
+	for _, name := range names {
+		if d := editDistance(word, name); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+// findNode returns the value node of a dotted key, or the closest node above
+// it that exists
+func findNode(root *yaml.Node, key string) *yaml.Node {
+	node := root
+	for _, part := range strings.Split(key, ".") {
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == part {
+					next = node.Content[i+1]
+				}
+			}
+		case yaml.SequenceNode:
+			if i, err := strconv.Atoi(part); err == nil && i < len(node.Content) {
+				next = node.Content[i]
+			}
+		}
+		if next == nil {
+			return node
+		}
+		node = next
+	}
+	return node
+}
+
+// Validate checks the values of a configuration, e.g. after merging
+func (c *Config) Validate() error {
+	if problems := checkValues(c); len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// checkValues reports values that are out of range, unsupported or invalid
+// regular expressions. Empty values are left to the defaults.
+func checkValues(c *Config) []Problem {
+	var problems []Problem
+	add := func(key, format string, args ...interface{}) {
+		problems = append(problems, Problem{Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if c.SubjectLength != "" && !contains(SubjectLengths, c.SubjectLength) {
+		add("subject_length", "%q is not one of %s", c.SubjectLength, strings.Join(SubjectLengths, ", "))
+	}
+	if c.Language != "" && !supportedLanguage(c.Language) {
+		add("language", "unsupported language %q (supported: %s)", c.Language, strings.Join(LanguageCodes(), ", "))
+	}
+	for i, lang := range c.Languages {
+		if !supportedLanguage(lang) {
+			add(fmt.Sprintf("languages.%d", i), "unsupported language %q (supported: %s)", lang, strings.Join(LanguageCodes(), ", "))
+		}
+	}
+	if c.TicketPattern != "" {
+		if _, err := regexp.Compile(c.TicketPattern); err != nil {
+			add("ticket_pattern", "invalid regular expression: %v", err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i, t := range c.Types {
+		switch {
+		case t.Name == "":
+			add(fmt.Sprintf("types.%d", i), "commit type without a name")
+		case seen[t.Name]:
+			add(fmt.Sprintf("types.%d.name", i), "duplicate commit type %q", t.Name)
+		}
+		seen[t.Name] = true
+	}
+
+	if c.MaxDiffLength < 0 {
+		add("max_diff_length", "must not be negative")
+	}
+	if c.DiffAnalysis.Context() < 0 {
+		add("diff_analysis.context_lines", "must not be negative")
+	}
+	if c.Changelog.Format != "" && !contains(ChangelogFormats, c.Changelog.Format) {
+		add("changelog.format", "%q is not one of %s", c.Changelog.Format, strings.Join(ChangelogFormats, ", "))
+	}
+	if c.Precheck.MaxBinaryKB < 0 {
+		add("precheck.max_binary_kb", "must not be negative")
+	}
+	for _, rule := range sortedKeys(c.Precheck.Rules) {
+		if level := c.Precheck.Rules[rule]; !contains(PrecheckLevels, strings.ToLower(level)) {
+			add("precheck.rules."+rule, "%q is not one of %s", level, strings.Join(PrecheckLevels, ", "))
+		}
+	}
+	for i, pattern := range c.Precheck.Exclude {
+		if _, err := ignore.New([]string{pattern}); err != nil {
+			add(fmt.Sprintf("precheck.exclude.%d", i), "%v", err)
+		}
+	}
+	for i, pattern := range c.Redact.Allow {
+		if _, err := regexp.Compile(pattern); err != nil {
+			add(fmt.Sprintf("redact.allow.%d", i), "invalid regular expression: %v", err)
+		}
+	}
+	for i, pattern := range c.Ignore {
+		if _, err := ignore.New([]string{pattern}); err != nil {
+			add(fmt.Sprintf("ignore.%d", i), "%v", err)
+		}
+	}
+	return append(problems, checkRules(c)...)
+}
+
+// LanguageCodes returns the supported language codes, sorted
+func LanguageCodes() []string {
+	return sortedKeys(i18n.SupportedLanguages)
+}
+
+// supportedLanguage reports whether code, or a common spelling of it such as
+// "zh-CN", is a supported language
+func supportedLanguage(code string) bool {
+	return i18n.IsSupported(i18n.NormalizeLanguageCode(code))
+}
+
+// contains reports whether list contains s
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLintFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []Problem // Only Line, Key and a part of Message are compared
+	}{
+		{
+			name:    "valid",
+			content: "model: llama3\nlanguage: zh-CN\nsubject_length: short\nticket_pattern: '[A-Z]+-\\d+'\n",
+		},
+		{
+			name:    "empty file",
+			content: "",
+		},
+		{
+			name:    "unknown key with suggestion",
+			content: "model: llama3\nsubjct_length: short\n",
+			want:    []Problem{{Line: 2, Key: "subjct_length", Message: `did you mean "subject_length"`}},
+		},
+		{
+			name:    "unknown nested key",
+			content: "changelog:\n  formatt: markdown\ntypes:\n  - name: feat\n    emojii: x\n",
+			want: []Problem{
+				{Line: 2, Key: "changelog.formatt", Message: "unknown key"},
+				{Line: 5, Key: "types.0.emojii", Message: "unknown key"},
+			},
+		},
+		{
+			name:    "invalid choices",
+			content: "language: klingon\nsubject_length: medium\nchangelog:\n  format: html\n",
+			want: []Problem{
+				{Line: 1, Key: "language", Message: "unsupported language"},
+				{Line: 2, Key: "subject_length", Message: `"medium" is not one of short, normal`},
+				{Line: 4, Key: "changelog.format", Message: "is not one of"},
+			},
+		},
+		{
+			name:    "bad regular expressions",
+			content: "ticket_pattern: '[A-Z+'\nredact:\n  allow:\n    - '(unclosed'\n",
+			want: []Problem{
+				{Line: 1, Key: "ticket_pattern", Message: "invalid regular expression"},
+				{Line: 4, Key: "redact.allow.0", Message: "invalid regular expression"},
+			},
+		},
+		{
+			name:    "duplicate types",
+			content: "types:\n  - name: feat\n  - name: fix\n  - name: feat\n",
+			want:    []Problem{{Line: 4, Key: "types.2.name", Message: `duplicate commit type "feat"`}},
+		},
+		{
+			name:    "precheck levels",
+			content: "precheck:\n  rules:\n    secret: loud\n    todo: OFF\n",
+			want:    []Problem{{Line: 3, Key: "precheck.rules.secret", Message: `"loud" is not one of off, warn, block`}},
+		},
+		{
+			name:    "wrong type",
+			content: "model: llama3\nmax_diff_length: lots\n",
+			want:    []Problem{{Line: 2, Message: "cannot unmarshal"}},
+		},
+		{
+			name:    "syntax error",
+			content: "model: [llama3\n",
+			want:    []Problem{{Message: "did not find expected"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".gitcommit.yaml")
+			writeFile(t, path, tt.content)
+
+			problems, err := LintFile(path)
+			if err != nil {
+				t.Fatalf("LintFile() error = %v", err)
+			}
+			if len(problems) != len(tt.want) {
+				t.Fatalf("LintFile() = %v, want %d problems", problems, len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := problems[i]
+				if got.File != path || got.Line != want.Line || got.Key != want.Key || !strings.Contains(got.Message, want.Message) {
+					t.Errorf("problem %d = %s, want line %d, key %q, message containing %q", i, got, want.Line, want.Key, want.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Errorf("default configuration is invalid: %v", err)
+	}
+
+	cfg := DefaultConfig()
+	cfg.Languages = []string{"en", "xx"}
+	cfg.MaxDiffLength = -1
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate() = nil, want problems")
+	}
+	for _, want := range []string{`languages.1: unsupported language "xx"`, "max_diff_length: must not be negative"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() = %v, want %q", err, want)
+		}
+	}
+}
+
+func TestLint(t *testing.T) {
+	home, root, pkg := layeredTree(t, "", "model: llama3\n", "scopez: [api]\n")
+
+	files, problems, err := Lint(Options{Dir: pkg, Env: []string{"HOME=" + home}})
+	if err != nil {
+		t.Fatalf("Lint() error = %v", err)
+	}
+	if len(files) != 2 || files[0] != filepath.Join(root, ".gitcommit.yaml") {
+		t.Errorf("files = %v, want the repo and the package config", files)
+	}
+	if len(problems) != 1 || problems[0].Key != "scopez" || problems[0].Line != 1 {
+		t.Errorf("problems = %v, want the unknown key of the package config", problems)
+	}
+
+	_, problems, err = Lint(Options{Dir: root, Env: []string{"HOME=" + home, "GITAI_LANGUAGE=xx"}})
+	if err != nil {
+		t.Fatalf("Lint() error = %v", err)
+	}
+	if len(problems) != 1 || problems[0].File != "GITAI_LANGUAGE" {
+		t.Errorf("problems = %v, want the environment variable", problems)
+	}
+}
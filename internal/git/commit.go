@@ -2,20 +2,196 @@ package git
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"runtime"
+	"strings"
 )
 
+// CommitOptions controls how a commit is created
+type CommitOptions struct {
+	Amend       bool     // Replace HEAD instead of creating a new commit
+	MessageOnly bool     // With Amend, change only the message and ignore staged changes
+	Args        []string // Extra `git commit` arguments passed through verbatim (e.g. --signoff, -S)
+}
+
+// SkipPrepareEnv is set for commits gitai makes, so the prepare-commit-msg
+// hook keeps the message. Other hooks, such as the pre-commit check, still run.
+const SkipPrepareEnv = "GITAI_SKIP_PREPARE"
+
+// conflictingCommitArgs are git commit flags that would replace the generated message
+var conflictingCommitArgs = []string{
+	"-m", "--message", "-F", "--file", "-C", "--reuse-message",
+	"-c", "--reedit-message", "--fixup", "--squash", "--amend",
+}
+
 // CommitWithMessage creates a git commit with the given message
 func CommitWithMessage(message string) error {
-	cmd := exec.Command("git", "commit", "-m", message)
-	output, err := cmd.CombinedOutput()
+	return CommitWithOptions(message, CommitOptions{})
+}
+
+// CommitWithOptions creates a git commit by writing the message to a file and
+// running `git commit -F`, so multi-line messages survive intact. Git hooks run
+// as usual and the terminal is attached so GPG pinentry and hook output work.
+func CommitWithOptions(message string, opts CommitOptions) error {
+	if err := ValidateCommitArgs(opts.Args); err != nil {
+		return err
+	}
+
+	path, cleanup, err := writeMessageFile(message)
 	if err != nil {
-		return fmt.Errorf("failed to commit: %w\nOutput: %s", err, string(output))
+		return err
+	}
+	defer cleanup()
+
+	cmd := exec.Command("git", CommitArgs(path, opts)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = commitEnv(opts)
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git commit failed: %w", err)
+	}
+
+	return nil
+}
+
+// CommitArgs returns the full `git commit` argument list for a message file.
+// Passthrough arguments come last so they can override gitai's defaults.
+func CommitArgs(messageFile string, opts CommitOptions) []string {
+	args := []string{"commit", "-F", messageFile, "--cleanup=whitespace"}
+	if opts.Amend {
+		args = append(args, "--amend")
+		if opts.MessageOnly {
+			args = append(args, "--only")
+		}
 	}
+	return append(args, opts.Args...)
+}
 
+// ValidateCommitArgs rejects passthrough flags that conflict with the generated message
+func ValidateCommitArgs(args []string) error {
+	for _, arg := range args {
+		name := arg
+		if idx := strings.Index(arg, "="); idx != -1 {
+			name = arg[:idx]
+		}
+		for _, conflicting := range conflictingCommitArgs {
+			if name == conflicting || (len(conflicting) == 2 && strings.HasPrefix(arg, conflicting) && !strings.HasPrefix(arg, "--")) {
+				return fmt.Errorf("git flag %s conflicts with the generated message", arg)
+			}
+		}
+	}
 	return nil
 }
 
+// HasCommitArg reports whether a passthrough flag is present (with or without a value)
+func HasCommitArg(args []string, flag string) bool {
+	for _, arg := range args {
+		if arg == flag || strings.HasPrefix(arg, flag+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// SigningInfo describes whether a commit will be GPG signed
+type SigningInfo struct {
+	Enabled bool
+	Key     string
+}
+
+// GetSigningInfo resolves commit signing from commit.gpgsign, user.signingkey
+// and any -S/--gpg-sign/--no-gpg-sign passthrough flags (last flag wins)
+func GetSigningInfo(args []string) SigningInfo {
+	info := SigningInfo{
+		Enabled: getConfigBool("commit.gpgsign"),
+		Key:     getConfigValue("user.signingkey"),
+	}
+
+	for _, arg := range args {
+		switch {
+		case arg == "--no-gpg-sign":
+			info.Enabled = false
+		case arg == "-S" || arg == "--gpg-sign":
+			info.Enabled = true
+		case strings.HasPrefix(arg, "--gpg-sign="):
+			info.Enabled = true
+			info.Key = strings.TrimPrefix(arg, "--gpg-sign=")
+		case strings.HasPrefix(arg, "-S"):
+			info.Enabled = true
+			info.Key = strings.TrimPrefix(arg, "-S")
+		}
+	}
+
+	return info
+}
+
+// commitEnv returns the environment for git commit. The prepare-commit-msg
+// hook is skipped to avoid regenerating the message, and GPG_TTY is set so pinentry can prompt.
+func commitEnv(opts CommitOptions) []string {
+	return gitEnv(GetSigningInfo(opts.Args).Enabled)
+}
+
+// gitEnv returns the environment for git commands that create objects,
+// setting GPG_TTY when the object will be signed
+func gitEnv(signing bool) []string {
+	env := append(os.Environ(), SkipPrepareEnv+"=1")
+
+	if os.Getenv("GPG_TTY") == "" && runtime.GOOS != "windows" && signing {
+		ttyCmd := exec.Command("tty")
+		ttyCmd.Stdin = os.Stdin
+		if output, err := ttyCmd.Output(); err == nil {
+			env = append(env, "GPG_TTY="+strings.TrimSpace(string(output)))
+		}
+	}
+
+	return env
+}
+
+// writeMessageFile writes the commit message to a temp file and returns its
+// path along with a cleanup function
+func writeMessageFile(message string) (string, func(), error) {
+	file, err := os.CreateTemp("", "gitai-msg-*.txt")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create message file: %w", err)
+	}
+
+	if !strings.HasSuffix(message, "\n") {
+		message += "\n"
+	}
+
+	if _, err := file.WriteString(message); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", nil, fmt.Errorf("failed to write message file: %w", err)
+	}
+	file.Close()
+
+	return file.Name(), func() { os.Remove(file.Name()) }, nil
+}
+
+// getConfigValue returns a git config value or an empty string
+func getConfigValue(key string) string {
+	cmd := exec.Command("git", "config", "--get", key)
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// getConfigBool returns a git config boolean, normalized by git itself
+func getConfigBool(key string) bool {
+	cmd := exec.Command("git", "config", "--type=bool", "--get", key)
+	output, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(output)) == "true"
+}
+
 // GetLastCommit returns the last commit hash and message
 func GetLastCommit() (string, error) {
 	cmd := exec.Command("git", "log", "-1", "--pretty=format:%H %s")
@@ -48,6 +48,25 @@ type CommitPattern struct {
 
 // AnalyzeCommitHistory analyzes commit history and returns statistics
 func AnalyzeCommitHistory(limit int) (*CommitStats, error) {
+	commits, err := LogCommits("", fmt.Sprintf("-%d", limit))
+	if err != nil {
+		return nil, err
+	}
+	return AnalyzeCommits(commits), nil
+}
+
+// AnalyzeCommitRange returns statistics for the non-merge commits of a
+// revision range, e.g. the commits of a release
+func AnalyzeCommitRange(revRange string) (*CommitStats, error) {
+	commits, err := LogCommits(revRange, "--no-merges")
+	if err != nil {
+		return nil, err
+	}
+	return AnalyzeCommits(commits), nil
+}
+
+// AnalyzeCommits computes statistics for the given commits
+func AnalyzeCommits(commits []Commit) *CommitStats {
 	stats := &CommitStats{
 		TypeDistribution:  make(map[string]int),
 		ScopeDistribution: make(map[string]int),
@@ -58,18 +77,8 @@ func AnalyzeCommitHistory(limit int) (*CommitStats, error) {
 		DayDistribution:   make(map[string]int),
 	}
 
-	// Get commits with detailed format
-	// Format: %H|%an|%s|%b|%ad
-	cmd := exec.Command("git", "log", fmt.Sprintf("-%d", limit),
-		"--pretty=format:%H|%an|%s|%b|%ad", "--date=iso")
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, err
-	}
-
-	commits := strings.Split(strings.TrimSpace(string(output)), "\n")
-	if len(commits) == 1 && commits[0] == "" {
-		return stats, nil
+	if len(commits) == 0 {
+		return stats
 	}
 
 	stats.TotalCommits = len(commits)
@@ -81,7 +90,7 @@ func AnalyzeCommitHistory(limit int) (*CommitStats, error) {
 
 	var totalLength int
 	longest := ""
-	shortest := commits[0]
+	shortest := commits[0].Subject
 
 	now := time.Now()
 	last30Days := now.AddDate(0, 0, -30)
@@ -89,16 +98,9 @@ func AnalyzeCommitHistory(limit int) (*CommitStats, error) {
 	dayCommits := make(map[string]int)
 
 	for _, commit := range commits {
-		parts := strings.Split(commit, "|")
-		if len(parts) < 5 {
-			continue
-		}
-
-		// hash := parts[0]
-		author := parts[1]
-		subject := parts[2]
-		body := parts[3]
-		dateStr := parts[4]
+		author := commit.Author
+		subject := commit.Subject
+		body := commit.Body
 
 		// Author stats
 		stats.AuthorStats[author]++
@@ -113,9 +115,8 @@ func AnalyzeCommitHistory(limit int) (*CommitStats, error) {
 			shortest = subject
 		}
 
-		// Parse date
-		commitDate, err := time.Parse("2006-01-02 15:04:05 -0700", dateStr)
-		if err == nil {
+		// Date distribution
+		if commitDate := commit.Date; !commitDate.IsZero() {
 			// Time distribution (hour of day)
 			hour := fmt.Sprintf("%02d:00", commitDate.Hour())
 			stats.TimeDistribution[hour]++
@@ -174,7 +175,7 @@ func AnalyzeCommitHistory(limit int) (*CommitStats, error) {
 	// Analyze trends
 	stats.RecentTrends = analyzeTrends(dayCommits, now, last30Days, last7Days)
 
-	return stats, nil
+	return stats
 }
 
 // analyzeTrends analyzes recent commit trends
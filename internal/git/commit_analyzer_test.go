@@ -3,6 +3,7 @@ package git
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestDetectCommitLanguage(t *testing.T) {
@@ -368,3 +369,33 @@ func TestFormatStatsReport(t *testing.T) {
 		t.Error("Report should contain commit types")
 	}
 }
+
+func TestAnalyzeCommits(t *testing.T) {
+	date := time.Date(2026, 1, 5, 14, 30, 0, 0, time.UTC)
+	commits := []Commit{
+		{Author: "alice", Subject: "feat(api): add pagination", Body: "Adds cursors.\n\nRefs: PROJ-1", Date: date},
+		{Author: "bob", Subject: "fix: handle nil user", Date: date},
+		{Author: "alice", Subject: "update docs"},
+	}
+
+	stats := AnalyzeCommits(commits)
+	if stats.TotalCommits != 3 {
+		t.Errorf("TotalCommits = %d, want 3", stats.TotalCommits)
+	}
+	if stats.AuthorStats["alice"] != 2 || stats.AuthorStats["bob"] != 1 {
+		t.Errorf("AuthorStats = %v", stats.AuthorStats)
+	}
+	if stats.WithBody != 1 || stats.WithScope != 1 {
+		t.Errorf("WithBody = %d, WithScope = %d, want 1, 1", stats.WithBody, stats.WithScope)
+	}
+	if stats.TypeDistribution["feat"] != 1 || stats.TypeDistribution["fix"] != 1 {
+		t.Errorf("TypeDistribution = %v", stats.TypeDistribution)
+	}
+	if stats.DayDistribution["Monday"] != 2 {
+		t.Errorf("DayDistribution = %v, want 2 commits on Monday", stats.DayDistribution)
+	}
+
+	if empty := AnalyzeCommits(nil); empty.TotalCommits != 0 || empty.AuthorStats == nil {
+		t.Errorf("AnalyzeCommits(nil) = %+v", empty)
+	}
+}
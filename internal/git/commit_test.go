@@ -0,0 +1,51 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateCommitArgs(t *testing.T) {
+	tests := []struct {
+		args    []string
+		wantErr bool
+	}{
+		{[]string{"--signoff", "-S", "--no-verify"}, false},
+		{[]string{"--author=Jane <jane@example.com>", "--date=now"}, false},
+		{[]string{"--allow-empty", "--cleanup=strip"}, false},
+		{[]string{"-m", "message"}, true},
+		{[]string{"-mmessage"}, true},
+		{[]string{"--message=hi"}, true},
+		{[]string{"-F", "file.txt"}, true},
+		{[]string{"--fixup=HEAD"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			err := ValidateCommitArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCommitArgs(%v) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCommitArgs(t *testing.T) {
+	got := strings.Join(CommitArgs("msg.txt", CommitOptions{Args: []string{"--signoff"}}), " ")
+	want := "commit -F msg.txt --cleanup=whitespace --signoff"
+	if got != want {
+		t.Errorf("CommitArgs() = %q, want %q", got, want)
+	}
+}
+
+func TestGitEnv(t *testing.T) {
+	t.Setenv("GITAI_HOOK", "")
+	env := strings.Join(gitEnv(false), "\n")
+	if !strings.Contains(env, SkipPrepareEnv+"=1") {
+		t.Errorf("gitEnv() does not set %s", SkipPrepareEnv)
+	}
+	// GITAI_HOOK=0 would also skip the pre-commit check
+	if strings.Contains(env, "GITAI_HOOK=0") {
+		t.Error("gitEnv() disables every GitAI hook")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -20,6 +21,12 @@ type ProjectContext struct {
 
 // GetProjectContext collects context information about the current project
 func GetProjectContext() (ProjectContext, error) {
+	return GetProjectContextFor(StagedSource())
+}
+
+// GetProjectContextFor collects project context with changed files and stats
+// taken from the given diff source
+func GetProjectContextFor(src DiffSource) (ProjectContext, error) {
 	ctx := ProjectContext{}
 
 	// Get project name (from directory name or git remote)
@@ -32,19 +39,19 @@ func GetProjectContext() (ProjectContext, error) {
 	}
 
 	// Get current branch
-	branch, err := getCurrentBranch()
+	branch, err := CurrentBranch()
 	if err == nil {
 		ctx.BranchName = branch
 	}
 
 	// Get changed files
-	files, err := GetChangedFiles()
+	files, err := src.ChangedFiles()
 	if err == nil {
 		ctx.ChangedFiles = files
 	}
 
 	// Get diff stats
-	stats, err := GetDiffStats()
+	stats, err := src.Stats()
 	if err == nil {
 		ctx.DiffStats = stats
 	}
@@ -101,8 +108,9 @@ func getRecentCommits(n int) ([]string, error) {
 	return commits, nil
 }
 
-// getCurrentBranch returns the name of the current branch
-func getCurrentBranch() (string, error) {
+// CurrentBranch returns the name of the current branch, or "" when HEAD is
+// detached
+func CurrentBranch() (string, error) {
 	cmd := exec.Command("git", "branch", "--show-current")
 	output, err := cmd.Output()
 	if err != nil {
@@ -145,3 +153,61 @@ func getReadmeSnippet(maxChars int) string {
 
 	return ""
 }
+
+// ListFiles returns the files tracked in the repository, relative to its top
+func ListFiles() ([]string, error) {
+	root, err := GetRepoRoot()
+	if err != nil {
+		return nil, err
+	}
+	output, err := exec.Command("git", "-C", root, "ls-files").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	trimmed := strings.TrimSpace(string(output))
+	if trimmed == "" {
+		return nil, nil
+	}
+	return strings.Split(trimmed, "\n"), nil
+}
+
+// scopeContainers are directories whose subdirectories are the project's
+// components, e.g. packages/api in a monorepo
+var scopeContainers = map[string]bool{
+	"packages": true, "apps": true, "services": true, "libs": true, "modules": true,
+	"plugins": true, "components": true, "internal": true, "pkg": true, "src": true,
+}
+
+// nonScopeDirs are directories that are not components
+var nonScopeDirs = map[string]bool{
+	"vendor": true, "node_modules": true, "third_party": true, "dist": true, "build": true,
+	"target": true, "bin": true, "out": true, "testdata": true,
+}
+
+// InferScopes suggests commit scopes from the directories of files: the
+// top-level directories, or the subdirectories of containers such as
+// packages/ and internal/
+func InferScopes(files []string) []string {
+	seen := make(map[string]bool)
+	var scopes []string
+	add := func(name string) {
+		if !seen[name] && !nonScopeDirs[name] && !strings.HasPrefix(name, ".") {
+			seen[name] = true
+			scopes = append(scopes, name)
+		}
+	}
+
+	for _, file := range files {
+		parts := strings.Split(filepath.ToSlash(file), "/")
+		if len(parts) < 2 {
+			continue // Files at the top have no scope
+		}
+		if scopeContainers[parts[0]] && len(parts) > 2 {
+			add(parts[1])
+			continue
+		}
+		add(parts[0])
+	}
+	sort.Strings(scopes)
+	return scopes
+}
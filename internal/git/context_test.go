@@ -0,0 +1,28 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInferScopes(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  []string
+	}{
+		{"top-level directories", []string{"README.md", "api/server.go", "web/index.ts", "api/routes.go"}, []string{"api", "web"}},
+		{"monorepo packages", []string{"packages/auth/index.ts", "packages/ui/button.tsx", "apps/web/main.ts"}, []string{"auth", "ui", "web"}},
+		{"go layout", []string{"cmd/root.go", "internal/config/config.go", "internal/git/diff.go", "main.go"}, []string{"cmd", "config", "git"}},
+		{"skipped directories", []string{".github/workflows/ci.yml", "vendor/x/y.go", "node_modules/a/b.js", "docs/guide.md"}, []string{"docs"}},
+		{"no directories", []string{"main.go"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InferScopes(tt.files); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InferScopes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
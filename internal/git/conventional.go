@@ -0,0 +1,165 @@
+package git
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// conventionalSubjectPattern matches "type(scope)!: description"
+var conventionalSubjectPattern = regexp.MustCompile(`^(\w+)(\(([^)]+)\))?(!)?:\s*(.+)$`)
+
+// ConventionalSubject is the parsed subject line of a Conventional Commit
+type ConventionalSubject struct {
+	Type        string
+	Scope       string
+	Breaking    bool // Marked with "!" before the colon
+	Description string
+}
+
+// ParseConventionalSubject parses a Conventional Commits subject line.
+// It returns false if the subject does not follow the format.
+func ParseConventionalSubject(subject string) (ConventionalSubject, bool) {
+	matches := conventionalSubjectPattern.FindStringSubmatch(strings.TrimSpace(subject))
+	if matches == nil {
+		return ConventionalSubject{}, false
+	}
+
+	return ConventionalSubject{
+		Type:        strings.ToLower(matches[1]),
+		Scope:       matches[3],
+		Breaking:    matches[4] == "!",
+		Description: strings.TrimSpace(matches[5]),
+	}, true
+}
+
+// vagueDescriptions are descriptions that say nothing about the change
+var vagueDescriptions = map[string]bool{
+	"wip": true, "fix": true, "fixes": true, "fix stuff": true, "fixed": true,
+	"update": true, "updates": true, "changes": true, "stuff": true, "misc": true,
+	"tmp": true, "temp": true, "test": true, "more": true, "cleanup": true,
+	"minor": true, "minor changes": true, "small fix": true, "asdf": true, ".": true,
+}
+
+// LintOptions configures LintSubject
+type LintOptions struct {
+	Types     []string // Allowed commit types (empty allows any)
+	MaxLength int      // Maximum subject length (0 disables the check)
+}
+
+// LintSubject returns the problems found in a commit subject line.
+// An empty result means the subject passes.
+func LintSubject(subject string, opts LintOptions) []string {
+	var problems []string
+	subject = strings.TrimSpace(subject)
+
+	if opts.MaxLength > 0 && len([]rune(subject)) > opts.MaxLength {
+		problems = append(problems, "subject is longer than "+strconv.Itoa(opts.MaxLength)+" characters")
+	}
+
+	description := subject
+	parsed, ok := ParseConventionalSubject(subject)
+	if ok {
+		description = parsed.Description
+		if len(opts.Types) > 0 && !contains(opts.Types, parsed.Type) {
+			problems = append(problems, "unknown type '"+parsed.Type+"'")
+		}
+	} else {
+		problems = append(problems, "not in Conventional Commits format")
+	}
+
+	// Words are not counted: one word can be precise, and CJK text has no spaces
+	if trimmed := strings.TrimRight(description, ".!"); trimmed == "" || vagueDescriptions[strings.ToLower(trimmed)] {
+		problems = append(problems, "description is too vague")
+	}
+	if strings.HasSuffix(description, ".") {
+		problems = append(problems, "subject ends with a period")
+	}
+
+	return problems
+}
+
+// footerPattern matches a git trailer style footer ("Token: value" or "Token #value")
+var footerPattern = regexp.MustCompile(`^(BREAKING CHANGE|BREAKING-CHANGE|[A-Za-z][\w-]*)(?:: | #)(.*)$`)
+
+// Footer is a single "Token: value" footer of a commit message
+type Footer struct {
+	Token string
+	Value string
+}
+
+// ConventionalCommit is a fully parsed Conventional Commits message
+type ConventionalCommit struct {
+	ConventionalSubject
+	Body           string
+	Footers        []Footer
+	BreakingChange string // Text of the BREAKING CHANGE footer, or the description if only "!" was used
+}
+
+// IsBreaking reports whether the commit introduces a breaking change
+func (c ConventionalCommit) IsBreaking() bool {
+	return c.Breaking || c.BreakingChange != ""
+}
+
+// Footer returns the value of the first footer with the given token (case-insensitive)
+func (c ConventionalCommit) Footer(token string) string {
+	for _, f := range c.Footers {
+		if strings.EqualFold(f.Token, token) {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// ParseConventionalMessage parses a full commit message into subject, body and
+// footers. It returns false if the subject does not follow Conventional Commits.
+func ParseConventionalMessage(message string) (ConventionalCommit, bool) {
+	message = strings.TrimSpace(strings.ReplaceAll(message, "\r\n", "\n"))
+	parts := strings.SplitN(message, "\n", 2)
+
+	subject, ok := ParseConventionalSubject(parts[0])
+	if !ok {
+		return ConventionalCommit{}, false
+	}
+	commit := ConventionalCommit{ConventionalSubject: subject}
+
+	if len(parts) == 2 {
+		commit.Body, commit.Footers = splitFooters(strings.TrimSpace(parts[1]))
+	}
+
+	for _, f := range commit.Footers {
+		if f.Token == "BREAKING CHANGE" || f.Token == "BREAKING-CHANGE" {
+			commit.BreakingChange = f.Value
+			break
+		}
+	}
+	if commit.BreakingChange == "" && commit.Breaking {
+		commit.BreakingChange = commit.Description
+	}
+
+	return commit, true
+}
+
+// splitFooters separates the trailing footer paragraph from the body
+func splitFooters(text string) (string, []Footer) {
+	paragraphs := strings.Split(text, "\n\n")
+	last := paragraphs[len(paragraphs)-1]
+
+	var footers []Footer
+	for _, line := range strings.Split(last, "\n") {
+		if matches := footerPattern.FindStringSubmatch(line); matches != nil {
+			footers = append(footers, Footer{Token: matches[1], Value: strings.TrimSpace(matches[2])})
+			continue
+		}
+		if len(footers) == 0 {
+			// The last paragraph is not a footer block
+			return text, nil
+		}
+		// Continuation of a multi-line footer value
+		f := &footers[len(footers)-1]
+		f.Value = strings.TrimSpace(f.Value + "\n" + line)
+	}
+
+	body := strings.TrimSpace(strings.Join(paragraphs[:len(paragraphs)-1], "\n\n"))
+	return body, footers
+}
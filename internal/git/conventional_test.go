@@ -0,0 +1,128 @@
+package git
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseConventionalSubject(t *testing.T) {
+	tests := []struct {
+		subject string
+		wantOK  bool
+		want    ConventionalSubject
+	}{
+		{"feat(auth): add login", true, ConventionalSubject{Type: "feat", Scope: "auth", Description: "add login"}},
+		{"fix: handle nil user", true, ConventionalSubject{Type: "fix", Description: "handle nil user"}},
+		{"refactor(api)!: drop v1 endpoints", true, ConventionalSubject{Type: "refactor", Scope: "api", Breaking: true, Description: "drop v1 endpoints"}},
+		{"wip", false, ConventionalSubject{}},
+		{"Merge branch 'main'", false, ConventionalSubject{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.subject, func(t *testing.T) {
+			got, ok := ParseConventionalSubject(tt.subject)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseConventionalSubject(%q) ok = %v, want %v", tt.subject, ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("ParseConventionalSubject(%q) = %+v, want %+v", tt.subject, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintSubject(t *testing.T) {
+	opts := LintOptions{Types: []string{"feat", "fix", "docs"}, MaxLength: 50}
+
+	tests := []struct {
+		subject string
+		want    int
+	}{
+		{"feat(auth): add OAuth2 login flow", 0},
+		{"fix: handle nil user in session lookup", 0},
+		{"wip", 2},
+		{"fix stuff", 2},
+		{"fix: update", 1},
+		{"feat: 添加登录令牌校验", 0},
+		{"fix: 修复会话过期后的空指针", 0},
+		{"fix: deduplicate", 0},
+		{"fix: stuff", 1},
+		{"chore: bump dependency versions", 1},
+		{"docs: describe the new config layering.", 1},
+		{"feat: " + strings.Repeat("very ", 10) + "long subject", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.subject, func(t *testing.T) {
+			if got := LintSubject(tt.subject, opts); len(got) != tt.want {
+				t.Errorf("LintSubject(%q) = %v, want %d problems", tt.subject, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConventionalMessage(t *testing.T) {
+	tests := []struct {
+		name         string
+		message      string
+		wantOK       bool
+		wantBody     string
+		wantFooters  []Footer
+		wantBreaking string
+	}{
+		{
+			name:    "subject only",
+			message: "fix: handle nil user",
+			wantOK:  true,
+		},
+		{
+			name:        "body and footers",
+			message:     "feat(auth): add OAuth2 login\n\nUsers can now sign in with Google.\n\nRefs: PROJ-123\nReviewed-by: Jane",
+			wantOK:      true,
+			wantBody:    "Users can now sign in with Google.",
+			wantFooters: []Footer{{"Refs", "PROJ-123"}, {"Reviewed-by", "Jane"}},
+		},
+		{
+			name:         "breaking change footer with continuation",
+			message:      "refactor(api): drop v1 endpoints\n\nBREAKING CHANGE: the /v1 routes are gone\nuse /v2 instead\nCloses #42",
+			wantOK:       true,
+			wantFooters:  []Footer{{"BREAKING CHANGE", "the /v1 routes are gone\nuse /v2 instead"}, {"Closes", "42"}},
+			wantBreaking: "the /v1 routes are gone\nuse /v2 instead",
+		},
+		{
+			name:         "breaking marker only",
+			message:      "feat!: require Go 1.22",
+			wantOK:       true,
+			wantBreaking: "require Go 1.22",
+		},
+		{
+			name:     "body without footers",
+			message:  "docs: explain config\n\nFirst paragraph.\n\nSecond paragraph.",
+			wantOK:   true,
+			wantBody: "First paragraph.\n\nSecond paragraph.",
+		},
+		{
+			name:    "not conventional",
+			message: "Update stuff\n\nRefs: PROJ-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseConventionalMessage(tt.message)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseConventionalMessage() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got.Body != tt.wantBody {
+				t.Errorf("Body = %q, want %q", got.Body, tt.wantBody)
+			}
+			if !reflect.DeepEqual(got.Footers, tt.wantFooters) {
+				t.Errorf("Footers = %+v, want %+v", got.Footers, tt.wantFooters)
+			}
+			if got.BreakingChange != tt.wantBreaking {
+				t.Errorf("BreakingChange = %q, want %q", got.BreakingChange, tt.wantBreaking)
+			}
+		})
+	}
+}
@@ -8,76 +8,22 @@ import (
 
 // GetStagedDiff returns the diff of staged changes
 func GetStagedDiff() (string, error) {
-	cmd := exec.Command("git", "diff", "--cached")
-	output, err := cmd.Output()
-	if err != nil {
-		return "", fmt.Errorf("failed to get git diff: %w", err)
-	}
-
-	diff := string(output)
-	if strings.TrimSpace(diff) == "" {
-		return "", fmt.Errorf("no staged changes found\nStage your changes first:\n  $ git add <files>")
-	}
-
-	return diff, nil
+	return StagedSource().Diff()
 }
 
 // GetChangedFiles returns list of files with staged changes
 func GetChangedFiles() ([]string, error) {
-	cmd := exec.Command("git", "diff", "--cached", "--name-only")
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get changed files: %w", err)
-	}
-
-	files := strings.Split(strings.TrimSpace(string(output)), "\n")
-	if len(files) == 1 && files[0] == "" {
-		return []string{}, nil
-	}
-
-	return files, nil
+	return StagedSource().ChangedFiles()
 }
 
 // GetDiffStats returns statistics about the changes
 func GetDiffStats() (string, error) {
-	cmd := exec.Command("git", "diff", "--cached", "--stat")
-	output, err := cmd.Output()
-	if err != nil {
-		return "", fmt.Errorf("failed to get diff stats: %w", err)
-	}
-
-	return string(output), nil
+	return StagedSource().Stats()
 }
 
 // GetChangedFilesWithStats returns files with their change statistics
 func GetChangedFilesWithStats() ([]FileChange, error) {
-	cmd := exec.Command("git", "diff", "--cached", "--numstat")
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get file stats: %w", err)
-	}
-
-	var changes []FileChange
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-
-	for _, line := range lines {
-		if line == "" {
-			continue
-		}
-
-		parts := strings.Fields(line)
-		if len(parts) < 3 {
-			continue
-		}
-
-		changes = append(changes, FileChange{
-			File:      parts[2],
-			Additions: parts[0],
-			Deletions: parts[1],
-		})
-	}
-
-	return changes, nil
+	return StagedSource().FilesWithStats()
 }
 
 // FileChange represents statistics for a single file
@@ -94,6 +40,24 @@ func IsGitRepository() bool {
 	return err == nil
 }
 
+// GetGitDir returns the absolute path of the repository's .git directory
+func GetGitDir() (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--absolute-git-dir").Output()
+	if err != nil {
+		return "", fmt.Errorf("not a git repository")
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetRepoRoot returns the absolute path of the working tree's top directory
+func GetRepoRoot() (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", fmt.Errorf("not a git repository")
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
 // CommitTypeHint represents a suggested commit type based on file analysis
 type CommitTypeHint struct {
 	Type  string
@@ -196,3 +160,17 @@ func detectFileType(file string) string {
 
 	return "chore"
 }
+
+// StagedFileSize returns the size in bytes of a file as it is staged
+func StagedFileSize(path string) (int64, error) {
+	output, err := exec.Command("git", "cat-file", "-s", ":"+path).Output()
+	if err != nil {
+		return 0, fmt.Errorf("%s is not staged", path)
+	}
+
+	var size int64
+	if _, err := fmt.Sscan(strings.TrimSpace(string(output)), &size); err != nil {
+		return 0, fmt.Errorf("unexpected size of %s: %w", path, err)
+	}
+	return size, nil
+}
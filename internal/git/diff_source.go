@@ -0,0 +1,319 @@
+package git
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// emptyTreeHash is git's well-known hash of the empty tree, used as the
+// parent when diffing a root commit
+const emptyTreeHash = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// DiffSourceKind identifies where a diff comes from
+type DiffSourceKind int
+
+const (
+	SourceStaged   DiffSourceKind = iota // Staged changes (git diff --cached)
+	SourceAmend                          // HEAD plus staged changes, for amending
+	SourceCommit                         // A single existing commit
+	SourceRange                          // A revision range (A..B or A...B)
+	SourceWorkTree                       // Unstaged working tree changes
+	SourcePatch                          // A patch supplied as text (file, email or stdin)
+)
+
+// DiffSource describes which changes a commit message should describe.
+// Every command that analyzes changes goes through a DiffSource, so the same
+// analyzer and prompt pipeline works for staged changes, history and patches.
+type DiffSource struct {
+	Kind  DiffSourceKind
+	Rev   string // Commit to describe (SourceCommit) or range (SourceRange)
+	Patch string // Patch contents (SourcePatch)
+	Label string // Where the patch came from, for display (SourcePatch)
+}
+
+// StagedSource returns a source for the currently staged changes
+func StagedSource() DiffSource {
+	return DiffSource{Kind: SourceStaged}
+}
+
+// AmendSource returns a source covering HEAD's changes plus anything staged
+func AmendSource() DiffSource {
+	return DiffSource{Kind: SourceAmend, Rev: "HEAD"}
+}
+
+// CommitSource returns a source for the changes introduced by a single commit
+func CommitSource(rev string) DiffSource {
+	return DiffSource{Kind: SourceCommit, Rev: rev}
+}
+
+// RangeSource returns a source for the net changes of a revision range
+func RangeSource(revRange string) DiffSource {
+	return DiffSource{Kind: SourceRange, Rev: revRange}
+}
+
+// WorkTreeSource returns a source for unstaged working tree changes
+func WorkTreeSource() DiffSource {
+	return DiffSource{Kind: SourceWorkTree}
+}
+
+// PatchSource returns a source for a patch given as text
+func PatchSource(patch, label string) DiffSource {
+	return DiffSource{Kind: SourcePatch, Patch: patch, Label: label}
+}
+
+// PatchFileSource reads a patch from a file ("-" reads stdin)
+func PatchFileSource(path string) (DiffSource, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+		path = "stdin"
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return DiffSource{}, fmt.Errorf("failed to read patch: %w", err)
+	}
+
+	return PatchSource(string(data), path), nil
+}
+
+// IsGitBacked reports whether the source needs a git repository
+func (s DiffSource) IsGitBacked() bool {
+	return s.Kind != SourcePatch
+}
+
+// Describe returns a short human-readable label for the source
+func (s DiffSource) Describe() string {
+	switch s.Kind {
+	case SourceAmend:
+		return "HEAD + staged changes"
+	case SourceCommit:
+		return "commit " + s.Rev
+	case SourceRange:
+		return "range " + s.Rev
+	case SourceWorkTree:
+		return "working tree changes"
+	case SourcePatch:
+		return "patch from " + s.Label
+	default:
+		return "staged changes"
+	}
+}
+
+// diffArgs returns the `git diff` arguments selecting this source's changes
+func (s DiffSource) diffArgs() ([]string, error) {
+	switch s.Kind {
+	case SourceAmend:
+		return []string{"--cached", parentOf("HEAD")}, nil
+	case SourceCommit:
+		if s.Rev == "" {
+			return nil, fmt.Errorf("no revision given")
+		}
+		if _, err := ResolveRev(s.Rev); err != nil {
+			return nil, err
+		}
+		return []string{parentOf(s.Rev), s.Rev}, nil
+	case SourceRange:
+		if err := checkRev(s.Rev); err != nil {
+			return nil, err
+		}
+		if !strings.Contains(s.Rev, "..") {
+			return nil, fmt.Errorf("invalid range %q (expected A..B or A...B)", s.Rev)
+		}
+		return []string{s.Rev}, nil
+	case SourceWorkTree:
+		return []string{}, nil
+	default:
+		return []string{"--cached"}, nil
+	}
+}
+
+// Diff returns the full diff for this source
+func (s DiffSource) Diff() (string, error) {
+	diff, err := s.run()
+	if err != nil {
+		return "", fmt.Errorf("failed to get git diff: %w", err)
+	}
+
+	if strings.TrimSpace(diff) == "" {
+		switch s.Kind {
+		case SourceStaged:
+			return "", fmt.Errorf("no staged changes found\nStage your changes first:\n  $ git add <files>")
+		case SourceWorkTree:
+			return "", fmt.Errorf("no unstaged changes found")
+		}
+		return "", fmt.Errorf("no changes found in %s", s.Describe())
+	}
+
+	return diff, nil
+}
+
+// ChangedFiles returns the list of files changed in this source
+func (s DiffSource) ChangedFiles() ([]string, error) {
+	output, err := s.run("--name-only", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get changed files: %w", err)
+	}
+
+	// Paths end with NUL and are not quoted
+	files := []string{}
+	for _, file := range strings.Split(output, "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// Stats returns `git diff --stat` output for this source
+func (s DiffSource) Stats() (string, error) {
+	output, err := s.run("--stat")
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff stats: %w", err)
+	}
+
+	return output, nil
+}
+
+// FilesWithStats returns per-file change statistics for this source
+func (s DiffSource) FilesWithStats() ([]FileChange, error) {
+	output, err := s.run("--numstat", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file stats: %w", err)
+	}
+
+	return parseNumstat(output), nil
+}
+
+// run executes git diff for this source with extra format arguments.
+// Patches are summarized with `git apply`, which also works outside a repository.
+func (s DiffSource) run(extra ...string) (string, error) {
+	if s.Kind == SourcePatch {
+		return s.runPatch(extra...)
+	}
+
+	args, err := s.diffArgs()
+	if err != nil {
+		return "", err
+	}
+
+	// "--" keeps revisions that match a file name from being read as paths
+	cmdArgs := append([]string{"diff"}, extra...)
+	cmdArgs = append(append(cmdArgs, args...), "--")
+
+	output, err := exec.Command("git", cmdArgs...).Output()
+	if err != nil {
+		return "", err
+	}
+
+	return string(output), nil
+}
+
+// runPatch returns the patch itself or its stats computed by git apply
+func (s DiffSource) runPatch(extra ...string) (string, error) {
+	if len(extra) == 0 {
+		return s.Patch, nil
+	}
+
+	args := []string{"apply"}
+	switch extra[0] {
+	case "--name-only":
+		args = append(args, "--numstat", "-z")
+	default:
+		args = append(args, extra...)
+	}
+
+	// git apply ignores paths outside the current subdirectory, so run from the top
+	cmd := exec.Command("git", append(args, "-")...)
+	cmd.Stdin = strings.NewReader(s.Patch)
+	if root, err := exec.Command("git", "rev-parse", "--show-toplevel").Output(); err == nil {
+		cmd.Dir = strings.TrimSpace(string(root))
+	}
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("not a valid patch: %w", err)
+	}
+
+	if extra[0] == "--name-only" {
+		var names []string
+		for _, fc := range parseNumstat(string(output)) {
+			names = append(names, fc.File)
+		}
+		return strings.Join(names, "\x00"), nil
+	}
+
+	return string(output), nil
+}
+
+// parseNumstat parses `git diff --numstat -z` output. Each file is
+// "added<TAB>deleted<TAB>path" ending with NUL; renames leave the path empty
+// and are followed by the old and the new path. Renamed files get the new path.
+func parseNumstat(output string) []FileChange {
+	var changes []FileChange
+	fields := strings.Split(output, "\x00")
+
+	for i := 0; i < len(fields); i++ {
+		parts := strings.SplitN(strings.TrimLeft(fields[i], "\n"), "\t", 3)
+		if len(parts) < 3 {
+			continue
+		}
+
+		file := parts[2]
+		if file == "" {
+			if i+2 >= len(fields) {
+				break
+			}
+			file = fields[i+2]
+			i += 2
+		}
+
+		changes = append(changes, FileChange{
+			File:      file,
+			Additions: parts[0],
+			Deletions: parts[1],
+		})
+	}
+
+	return changes
+}
+
+// checkRev rejects revisions that git would read as options, such as
+// "--output=<file>" given to --rev or --range
+func checkRev(rev string) error {
+	if strings.HasPrefix(rev, "-") {
+		return fmt.Errorf("invalid revision %q: must not start with -", rev)
+	}
+	return nil
+}
+
+// ResolveRev resolves a revision to its full commit hash
+func ResolveRev(rev string) (string, error) {
+	if err := checkRev(rev); err != nil {
+		return "", err
+	}
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("unknown revision: %s", rev)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// HasParent reports whether a commit has a parent (false for root commits)
+func HasParent(rev string) bool {
+	_, err := ResolveRev(rev + "^")
+	return err == nil
+}
+
+// parentOf returns the first parent of rev, or the empty tree for root commits
+func parentOf(rev string) string {
+	if HasParent(rev) {
+		return rev + "^"
+	}
+	return emptyTreeHash
+}
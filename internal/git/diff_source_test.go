@@ -0,0 +1,74 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseNumstat(t *testing.T) {
+	// As printed by git diff --numstat -z: renames, brace renames and
+	// paths git would otherwise quote
+	output := "3\t1\tmain.go\x00-\t-\tlogo.png\x00" +
+		"2\t2\t\x00old.go\x00new.go\x00" +
+		"0\t0\t\x00src/a.go\x00src/b.go\x00" +
+		"1\t0\tspäce é\t.go\x00"
+
+	got := parseNumstat(output)
+	want := []FileChange{
+		{File: "main.go", Additions: "3", Deletions: "1"},
+		{File: "logo.png", Additions: "-", Deletions: "-"},
+		{File: "new.go", Additions: "2", Deletions: "2"},
+		{File: "src/b.go", Additions: "0", Deletions: "0"},
+		{File: "späce é\t.go", Additions: "1", Deletions: "0"},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("parseNumstat() = %d files, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("parseNumstat()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPatchSource(t *testing.T) {
+	patch := `diff --git a/main.go b/main.go
+index 123..456 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,4 @@
+ package main
+ 
++import "fmt"
+ func main() {}
+`
+
+	src := PatchSource(patch, "test.patch")
+
+	diff, err := src.Diff()
+	if err != nil || diff != patch {
+		t.Fatalf("Diff() = %q, %v; want the patch itself", diff, err)
+	}
+
+	files, err := src.ChangedFiles()
+	if err != nil {
+		t.Fatalf("ChangedFiles() error = %v", err)
+	}
+	if len(files) != 1 || files[0] != "main.go" {
+		t.Errorf("ChangedFiles() = %v, want [main.go]", files)
+	}
+}
+
+func TestDiffArgsRejectsOptions(t *testing.T) {
+	sources := []DiffSource{
+		CommitSource("--output=/tmp/gitai-diff"),
+		RangeSource("--output=/tmp/gitai-diff..HEAD"),
+		RangeSource("-p..HEAD"),
+	}
+	for _, src := range sources {
+		if _, err := src.diffArgs(); err == nil || !strings.Contains(err.Error(), "must not start with -") {
+			t.Errorf("%s: diffArgs() error = %v, want a rejected revision", src.Describe(), err)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Commit is a single commit read from git log
+type Commit struct {
+	Hash    string
+	Author  string
+	Email   string
+	Date    time.Time
+	Subject string
+	Body    string
+	Parents []string
+}
+
+// ShortHash returns the abbreviated commit hash
+func (c Commit) ShortHash() string {
+	return shortHash(c.Hash)
+}
+
+// Message returns the full commit message (subject and body)
+func (c Commit) Message() string {
+	if c.Body == "" {
+		return c.Subject
+	}
+	return c.Subject + "\n\n" + c.Body
+}
+
+// IsMerge reports whether the commit has more than one parent
+func (c Commit) IsMerge() bool {
+	return len(c.Parents) > 1
+}
+
+// logFormat separates fields with \x1f and records with \x1e so that
+// multi-line bodies can be parsed safely
+const logFormat = "%H%x1f%P%x1f%an%x1f%ae%x1f%aI%x1f%s%x1f%b%x1e"
+
+// LogCommits returns the commits selected by revRange (e.g. "v1.0.0..HEAD"),
+// newest first. Extra arguments are passed to git log (e.g. "--no-merges").
+func LogCommits(revRange string, extra ...string) ([]Commit, error) {
+	args := append([]string{"log", "--format=" + logFormat}, extra...)
+	if revRange != "" {
+		args = append(args, revRange)
+	}
+
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commits for %s: %w", revRange, err)
+	}
+
+	return parseLog(string(output)), nil
+}
+
+// parseLog parses git log output produced with logFormat
+func parseLog(output string) []Commit {
+	var commits []Commit
+
+	for _, record := range strings.Split(output, "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+
+		fields := strings.Split(record, "\x1f")
+		if len(fields) < 7 {
+			continue
+		}
+
+		commit := Commit{
+			Hash:    fields[0],
+			Parents: strings.Fields(fields[1]),
+			Author:  fields[2],
+			Email:   fields[3],
+			Subject: fields[5],
+			Body:    strings.TrimSpace(fields[6]),
+		}
+		if date, err := time.Parse(time.RFC3339, fields[4]); err == nil {
+			commit.Date = date
+		}
+
+		commits = append(commits, commit)
+	}
+
+	return commits
+}
+
+// LatestTag returns the most recent tag reachable from rev, or "" if there is none
+func LatestTag(rev string) string {
+	output, err := exec.Command("git", "describe", "--tags", "--abbrev=0", rev).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// CommitDate returns the committer date of rev
+func CommitDate(rev string) (time.Time, error) {
+	output, err := exec.Command("git", "log", "-1", "--format=%cI", rev).Output()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unknown revision: %s", rev)
+	}
+	return time.Parse(time.RFC3339, strings.TrimSpace(string(output)))
+}
+
+// MergeBase returns the best common ancestor of two revisions
+func MergeBase(a, b string) (string, error) {
+	output, err := exec.Command("git", "merge-base", a, b).Output()
+	if err != nil {
+		return "", fmt.Errorf("no common ancestor of %s and %s", a, b)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// DefaultBranch returns the branch pull requests usually target: the remote
+// HEAD of origin (e.g. "origin/main"), else a local main or master branch
+func DefaultBranch() (string, error) {
+	output, err := exec.Command("git", "symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD").Output()
+	if err == nil {
+		return strings.TrimSpace(string(output)), nil
+	}
+
+	for _, branch := range []string{"main", "master"} {
+		if exec.Command("git", "rev-parse", "--verify", "--quiet", "refs/heads/"+branch).Run() == nil {
+			return branch, nil
+		}
+	}
+
+	return "", fmt.Errorf("could not determine the default branch\nPass the target branch explicitly:\n  $ gitai pr --base main")
+}
@@ -0,0 +1,140 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Cleanup modes supported by git's commit.cleanup setting
+const (
+	CleanupDefault    = "default"
+	CleanupStrip      = "strip"
+	CleanupWhitespace = "whitespace"
+	CleanupVerbatim   = "verbatim"
+	CleanupScissors   = "scissors"
+)
+
+// scissorsMarker is the text git places between the comment char and the
+// cut line when using the scissors cleanup mode
+const scissorsMarker = " ------------------------ >8 ------------------------"
+
+// GetEditor returns the editor command git would use for commit messages.
+// Resolution follows git itself: $GIT_EDITOR, core.editor, $VISUAL, $EDITOR, vi.
+func GetEditor() string {
+	cmd := exec.Command("git", "var", "GIT_EDITOR")
+	output, err := cmd.Output()
+	if err == nil {
+		if editor := strings.TrimSpace(string(output)); editor != "" {
+			return editor
+		}
+	}
+
+	// Fallback when git var is unavailable (e.g. outside a repository)
+	for _, env := range []string{"GIT_EDITOR", "VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(env)); editor != "" {
+			return editor
+		}
+	}
+
+	return "vi"
+}
+
+// GetCleanupMode returns the configured commit.cleanup mode (default: "default")
+func GetCleanupMode() string {
+	cmd := exec.Command("git", "config", "--get", "commit.cleanup")
+	output, err := cmd.Output()
+	if err != nil {
+		return CleanupDefault
+	}
+
+	mode := strings.ToLower(strings.TrimSpace(string(output)))
+	switch mode {
+	case CleanupStrip, CleanupWhitespace, CleanupVerbatim, CleanupScissors, CleanupDefault:
+		return mode
+	default:
+		return CleanupDefault
+	}
+}
+
+// GetCommentChar returns the configured core.commentChar (default: "#")
+func GetCommentChar() string {
+	cmd := exec.Command("git", "config", "--get", "core.commentChar")
+	output, err := cmd.Output()
+	if err != nil {
+		return "#"
+	}
+
+	char := strings.TrimSpace(string(output))
+	if char == "" || char == "auto" {
+		return "#"
+	}
+	return char
+}
+
+// ScissorsLine returns the scissors line for the given comment char
+func ScissorsLine(commentChar string) string {
+	return commentChar + scissorsMarker
+}
+
+// CleanupMessage applies git's commit.cleanup semantics to an edited message.
+// The "default" mode behaves like "strip", as git does when the message was edited.
+func CleanupMessage(message, mode, commentChar string) string {
+	if commentChar == "" {
+		commentChar = "#"
+	}
+
+	switch mode {
+	case CleanupVerbatim:
+		return message
+	case CleanupWhitespace:
+		return cleanupWhitespace(message)
+	case CleanupScissors:
+		return cleanupWhitespace(cutAtScissors(message, commentChar))
+	default:
+		// strip: scissors are honored too, so editor templates can always use them
+		message = cutAtScissors(message, commentChar)
+		var kept []string
+		for _, line := range strings.Split(message, "\n") {
+			if strings.HasPrefix(line, commentChar) {
+				continue
+			}
+			kept = append(kept, line)
+		}
+		return cleanupWhitespace(strings.Join(kept, "\n"))
+	}
+}
+
+// cutAtScissors drops the scissors line and everything below it
+func cutAtScissors(message, commentChar string) string {
+	scissors := ScissorsLine(commentChar)
+	lines := strings.Split(message, "\n")
+	for i, line := range lines {
+		if strings.TrimRight(line, " \t\r") == scissors {
+			return strings.Join(lines[:i], "\n")
+		}
+	}
+	return message
+}
+
+// cleanupWhitespace strips trailing whitespace, collapses consecutive blank
+// lines, and removes leading and trailing blank lines
+func cleanupWhitespace(message string) string {
+	var result []string
+	blank := false
+
+	for _, line := range strings.Split(message, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			blank = len(result) > 0
+			continue
+		}
+		if blank {
+			result = append(result, "")
+			blank = false
+		}
+		result = append(result, line)
+	}
+
+	return strings.Join(result, "\n")
+}
@@ -0,0 +1,51 @@
+package git
+
+import "testing"
+
+func TestCleanupMessage(t *testing.T) {
+	edited := "\n\nfeat: add login   \n\n\n- support OAuth\n# a comment\n\n" +
+		ScissorsLine("#") + "\n# Everything below is ignored\ndiff stats\n"
+
+	tests := []struct {
+		name string
+		mode string
+		want string
+	}{
+		{
+			name: "strip removes comments and scissors",
+			mode: CleanupStrip,
+			want: "feat: add login\n\n- support OAuth",
+		},
+		{
+			name: "default behaves like strip",
+			mode: CleanupDefault,
+			want: "feat: add login\n\n- support OAuth",
+		},
+		{
+			name: "scissors keeps comments above the cut",
+			mode: CleanupScissors,
+			want: "feat: add login\n\n- support OAuth\n# a comment",
+		},
+		{
+			name: "verbatim keeps everything",
+			mode: CleanupVerbatim,
+			want: edited,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CleanupMessage(edited, tt.mode, "#"); got != tt.want {
+				t.Errorf("CleanupMessage(%q) = %q, want %q", tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCleanupMessageCommentChar(t *testing.T) {
+	got := CleanupMessage("fix: typo\n; comment\n# kept", CleanupStrip, ";")
+	want := "fix: typo\n# kept"
+	if got != want {
+		t.Errorf("CleanupMessage() = %q, want %q", got, want)
+	}
+}
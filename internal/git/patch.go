@@ -0,0 +1,202 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// hunkHeaderPattern matches "@@ -a,b +c,d @@ context" and captures the context
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+\d+(?:,\d+)? @@ ?(.*)$`)
+
+// Patterns used to find the name a hunk belongs to
+var (
+	identifierPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+	goReceiverPattern = regexp.MustCompile(`^func\s*\([^)]*\)`)
+)
+
+// symbolKeywords are skipped when looking for the defined name in a hunk header
+var symbolKeywords = map[string]bool{
+	"func": true, "function": true, "def": true, "fn": true, "class": true,
+	"type": true, "struct": true, "interface": true, "enum": true, "impl": true,
+	"pub": true, "async": true, "static": true, "public": true, "private": true,
+	"protected": true, "export": true, "default": true, "const": true, "var": true,
+	"let": true, "void": true, "int": true, "final": true, "abstract": true,
+}
+
+// Hunk is a single hunk of a file patch. Changes that cannot be split
+// (binary files, mode changes, pure renames) are represented by one hunk
+// with an empty Header that stands for the whole file.
+type Hunk struct {
+	ID     int    // Position in the patch, keeps hunks in their original order
+	File   string // Path of the file the hunk belongs to
+	Header string // "@@ ... @@" line, empty for whole-file changes
+	Symbol string // Enclosing function or type from the hunk header, if any
+	Lines  []string
+}
+
+// Describe returns a short label for the hunk, e.g. "cmd/root.go @@ Execute"
+func (h Hunk) Describe() string {
+	if h.Header == "" {
+		return h.File
+	}
+	if h.Symbol != "" {
+		return fmt.Sprintf("%s @@ %s", h.File, h.Symbol)
+	}
+	return fmt.Sprintf("%s %s", h.File, strings.SplitN(h.Header, " @@", 2)[0]+" @@")
+}
+
+// FilePatch is the part of a patch touching one file
+type FilePatch struct {
+	Path   string
+	Header []string // "diff --git" line and extended headers up to the first hunk
+	Hunks  []Hunk
+}
+
+// Patch is a parsed unified diff split into files and hunks
+type Patch struct {
+	Files []FilePatch
+}
+
+// GetStagedPatch returns the staged changes as a patch that git apply accepts
+func GetStagedPatch() (string, error) {
+	output, err := exec.Command("git", "diff", "--cached", "--binary", "--no-color", "--no-ext-diff").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get staged patch: %w", err)
+	}
+
+	return string(output), nil
+}
+
+// ParsePatch splits a unified diff (as produced by git diff) into files and hunks
+func ParsePatch(text string) *Patch {
+	patch := &Patch{}
+	var file *FilePatch
+	var hunk *Hunk
+	nextID := 0
+
+	flushHunk := func() {
+		if file != nil && hunk != nil {
+			file.Hunks = append(file.Hunks, *hunk)
+		}
+		hunk = nil
+	}
+	flushFile := func() {
+		flushHunk()
+		if file == nil {
+			return
+		}
+		if len(file.Hunks) == 0 {
+			file.Hunks = []Hunk{{ID: nextID, File: file.Path}}
+			nextID++
+		}
+		patch.Files = append(patch.Files, *file)
+		file = nil
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flushFile()
+			file = &FilePatch{Path: pathFromDiffLine(line), Header: []string{line}}
+		case file == nil:
+			// Ignore anything before the first file (e.g. mail headers)
+			continue
+		case strings.HasPrefix(line, "@@ "):
+			flushHunk()
+			hunk = &Hunk{ID: nextID, File: file.Path, Header: line}
+			if matches := hunkHeaderPattern.FindStringSubmatch(line); matches != nil {
+				hunk.Symbol = extractSymbol(matches[1])
+			}
+			nextID++
+		case hunk != nil:
+			hunk.Lines = append(hunk.Lines, line)
+		default:
+			if strings.HasPrefix(line, "+++ b/") {
+				file.Path = strings.TrimPrefix(line, "+++ b/")
+			}
+			file.Header = append(file.Header, line)
+		}
+	}
+	flushFile()
+
+	return patch
+}
+
+// pathFromDiffLine extracts the new path from a "diff --git a/x b/y" line
+func pathFromDiffLine(line string) string {
+	rest := strings.TrimPrefix(line, "diff --git ")
+	if idx := strings.LastIndex(rest, " b/"); idx >= 0 {
+		return rest[idx+3:]
+	}
+	return rest
+}
+
+// extractSymbol returns the name defined by a hunk header context, e.g.
+// "func (s *Server) Start(ctx context.Context) error {" gives "Start"
+func extractSymbol(context string) string {
+	context = goReceiverPattern.ReplaceAllString(strings.TrimSpace(context), "")
+
+	for _, word := range identifierPattern.FindAllString(context, -1) {
+		if !symbolKeywords[word] {
+			return word
+		}
+	}
+	return ""
+}
+
+// Hunks returns every hunk of the patch in order
+func (p *Patch) Hunks() []Hunk {
+	var hunks []Hunk
+	for _, f := range p.Files {
+		hunks = append(hunks, f.Hunks...)
+	}
+	return hunks
+}
+
+// Select builds a patch containing only the given hunks, in original order
+func (p *Patch) Select(hunks []Hunk) string {
+	selected := make(map[int]bool, len(hunks))
+	for _, h := range hunks {
+		selected[h.ID] = true
+	}
+
+	var b strings.Builder
+	for _, f := range p.Files {
+		var chosen []Hunk
+		for _, h := range f.Hunks {
+			if selected[h.ID] {
+				chosen = append(chosen, h)
+			}
+		}
+		if len(chosen) == 0 {
+			continue
+		}
+
+		for _, line := range f.Header {
+			b.WriteString(line + "\n")
+		}
+		for _, h := range chosen {
+			if h.Header == "" {
+				continue
+			}
+			b.WriteString(h.Header + "\n")
+			for _, line := range h.Lines {
+				b.WriteString(line + "\n")
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// StagePatch applies a patch to the index only, leaving the working tree alone
+func StagePatch(patch string) error {
+	cmd := exec.Command("git", "apply", "--cached", "--recount", "-")
+	cmd.Stdin = strings.NewReader(patch)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to stage patch: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+const samplePatch = `diff --git a/cmd/root.go b/cmd/root.go
+index 1111111..2222222 100644
+--- a/cmd/root.go
++++ b/cmd/root.go
+@@ -10,6 +10,7 @@ func Execute() error {
+ 	a := 1
++	b := 2
+ 	return nil
+@@ -40,3 +41,4 @@ func (c *Command) Run(args []string) error {
+ 	x := 1
++	y := 2
+ 	return nil
+diff --git a/docs/usage.md b/docs/usage.md
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/docs/usage.md
+@@ -0,0 +1,2 @@
++# Usage
++Run it.
+diff --git a/logo.png b/logo.png
+index 4444444..5555555 100644
+GIT binary patch
+literal 10
+Rcmb=ZU|?WiVPIep1^@wv0XP5v
+`
+
+func TestParsePatch(t *testing.T) {
+	patch := ParsePatch(samplePatch)
+
+	if len(patch.Files) != 3 {
+		t.Fatalf("ParsePatch() returned %d files, want 3", len(patch.Files))
+	}
+
+	hunks := patch.Hunks()
+	want := []struct {
+		file   string
+		symbol string
+		whole  bool
+	}{
+		{"cmd/root.go", "Execute", false},
+		{"cmd/root.go", "Run", false},
+		{"docs/usage.md", "", false},
+		{"logo.png", "", true},
+	}
+	if len(hunks) != len(want) {
+		t.Fatalf("Hunks() returned %d hunks, want %d", len(hunks), len(want))
+	}
+	for i, w := range want {
+		h := hunks[i]
+		if h.ID != i || h.File != w.file || h.Symbol != w.symbol || (h.Header == "") != w.whole {
+			t.Errorf("hunk %d = {ID:%d File:%q Symbol:%q Header:%q}, want file %q symbol %q whole %v",
+				i, h.ID, h.File, h.Symbol, h.Header, w.file, w.symbol, w.whole)
+		}
+	}
+}
+
+func TestPatchSelect(t *testing.T) {
+	patch := ParsePatch(samplePatch)
+	hunks := patch.Hunks()
+
+	// Selecting everything reproduces the original patch
+	if got := patch.Select(hunks); got != samplePatch {
+		t.Errorf("Select(all) changed the patch:\n%s", got)
+	}
+
+	// Selecting the second hunk keeps the file header and drops the first hunk
+	got := patch.Select(hunks[1:2])
+	if !strings.HasPrefix(got, "diff --git a/cmd/root.go b/cmd/root.go\n") {
+		t.Errorf("Select() lost the file header:\n%s", got)
+	}
+	if strings.Contains(got, "b := 2") || !strings.Contains(got, "y := 2") {
+		t.Errorf("Select() = %q, want only the second hunk", got)
+	}
+	if strings.Contains(got, "docs/usage.md") {
+		t.Errorf("Select() included unselected files:\n%s", got)
+	}
+
+	// Whole-file changes keep their binary payload
+	if got := patch.Select(hunks[3:]); !strings.Contains(got, "GIT binary patch") {
+		t.Errorf("Select() lost the binary patch:\n%s", got)
+	}
+}
+
+func TestExtractSymbol(t *testing.T) {
+	tests := []struct {
+		context string
+		want    string
+	}{
+		{"func (s *Server) Start(ctx context.Context) error {", "Start"},
+		{"func main() {", "main"},
+		{"type Config struct {", "Config"},
+		{"class UserService extends Base {", "UserService"},
+		{"def handle_request(self, req):", "handle_request"},
+		{"public static void main(String[] args) {", "main"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.context, func(t *testing.T) {
+			if got := extractSymbol(tt.context); got != tt.want {
+				t.Errorf("extractSymbol(%q) = %q, want %q", tt.context, got, tt.want)
+			}
+		})
+	}
+}
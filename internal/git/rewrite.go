@@ -0,0 +1,196 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// RebaseTodoCommand is the hidden gitai subcommand used as GIT_SEQUENCE_EDITOR
+// when rewording commits. It rewrites the rebase todo list non-interactively.
+const RebaseTodoCommand = "__rebase-todo"
+
+// GetCommitMessage returns the full message of a commit
+func GetCommitMessage(rev string) (string, error) {
+	cmd := exec.Command("git", "log", "-1", "--format=%B", rev)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read message of %s: %w", rev, err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// IsHead reports whether rev resolves to the current HEAD commit
+func IsHead(rev string) bool {
+	head, err := ResolveRev("HEAD")
+	if err != nil {
+		return false
+	}
+	sha, err := ResolveRev(rev)
+	return err == nil && sha == head
+}
+
+// IsAncestorOfHead reports whether rev is reachable from HEAD
+func IsAncestorOfHead(rev string) bool {
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", rev, "HEAD")
+	return cmd.Run() == nil
+}
+
+// RewordCommits replaces the messages of existing commits, keyed by full hash,
+// without touching their content. It runs a non-interactive rebase where gitai
+// acts as the sequence editor and adds an `exec git commit --amend --only`
+// after each commit to reword. On failure the rebase is aborted.
+func RewordCommits(messages map[string]string) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	shas := make([]string, 0, len(messages))
+	for sha := range messages {
+		if !IsAncestorOfHead(sha) {
+			return fmt.Errorf("commit %s is not an ancestor of HEAD", shortHash(sha))
+		}
+		shas = append(shas, sha)
+	}
+
+	// Work in a temp dir holding one message file per commit plus the map
+	dir, err := os.MkdirTemp("", "gitai-reword-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	messageFiles := make(map[string]string)
+	for i, sha := range shas {
+		path := filepath.Join(dir, fmt.Sprintf("msg-%d.txt", i))
+		message := strings.TrimRight(messages[sha], "\n") + "\n"
+		if err := os.WriteFile(path, []byte(message), 0600); err != nil {
+			return fmt.Errorf("failed to write message file: %w", err)
+		}
+		messageFiles[sha] = path
+	}
+
+	mapPath := filepath.Join(dir, "messages.json")
+	data, err := json.Marshal(messageFiles)
+	if err != nil {
+		return fmt.Errorf("failed to encode message map: %w", err)
+	}
+	if err := os.WriteFile(mapPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write message map: %w", err)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate gitai executable: %w", err)
+	}
+
+	// Rebase from the parent of the oldest commit (or the root)
+	args := []string{"rebase", "-i", "--autostash", "--rebase-merges"}
+	base, err := oldestCommit(shas)
+	if err != nil {
+		return err
+	}
+	if HasParent(base) {
+		args = append(args, base+"^")
+	} else {
+		args = append(args, "--root")
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(),
+		"GIT_SEQUENCE_EDITOR="+shellQuote(exe)+" "+RebaseTodoCommand+" "+shellQuote(mapPath),
+		"GIT_EDITOR=true",
+		SkipPrepareEnv+"=1",
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		exec.Command("git", "rebase", "--abort").Run()
+		return fmt.Errorf("rebase failed and was aborted: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
+// RewriteRebaseTodoFile rewrites a rebase todo file in place using the
+// message map written by RewordCommits
+func RewriteRebaseTodoFile(mapPath, todoPath string) error {
+	data, err := os.ReadFile(mapPath)
+	if err != nil {
+		return err
+	}
+
+	var messageFiles map[string]string
+	if err := json.Unmarshal(data, &messageFiles); err != nil {
+		return fmt.Errorf("invalid message map: %w", err)
+	}
+
+	todo, err := os.ReadFile(todoPath)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(todoPath, []byte(RewriteRebaseTodo(string(todo), messageFiles)), 0644)
+}
+
+// RewriteRebaseTodo adds an exec line after every picked commit whose hash
+// matches a key in messageFiles, amending that commit's message from the file
+func RewriteRebaseTodo(todo string, messageFiles map[string]string) string {
+	var out []string
+
+	for _, line := range strings.Split(todo, "\n") {
+		out = append(out, line)
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 || (fields[0] != "pick" && fields[0] != "p") {
+			continue
+		}
+
+		for sha, path := range messageFiles {
+			if strings.HasPrefix(sha, fields[1]) {
+				out = append(out, "exec git commit --amend --only --no-verify --allow-empty --cleanup=whitespace -F "+shellQuote(path))
+				break
+			}
+		}
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// oldestCommit returns the commit among shas that all others descend from
+func oldestCommit(shas []string) (string, error) {
+	if len(shas) == 1 {
+		return shas[0], nil
+	}
+
+	args := append([]string{"merge-base", "--octopus"}, shas...)
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find common base: %w", err)
+	}
+
+	base := strings.TrimSpace(string(output))
+	for _, sha := range shas {
+		if sha == base {
+			return base, nil
+		}
+	}
+
+	return "", fmt.Errorf("commits to reword are not on a single line of history")
+}
+
+// shellQuote quotes a string for use in a POSIX shell command line
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// shortHash abbreviates a commit hash for display
+func shortHash(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
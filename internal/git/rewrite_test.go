@@ -0,0 +1,48 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRewriteRebaseTodo(t *testing.T) {
+	todo := `label onto
+
+# Branch: main
+reset onto
+pick 1a2b3c4 wip
+pick 5d6e7f8 fix stuff
+p 9a8b7c6 more wip
+
+# Rebase 0123abc..9a8b7c6 onto 0123abc`
+
+	messageFiles := map[string]string{
+		"5d6e7f8aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa": "/tmp/msg 1.txt",
+		"9a8b7c6bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb": "/tmp/it's.txt",
+	}
+
+	got := RewriteRebaseTodo(todo, messageFiles)
+	lines := strings.Split(got, "\n")
+
+	want := map[string]string{
+		"pick 5d6e7f8 fix stuff": "exec git commit --amend --only --no-verify --allow-empty --cleanup=whitespace -F '/tmp/msg 1.txt'",
+		"p 9a8b7c6 more wip":     `exec git commit --amend --only --no-verify --allow-empty --cleanup=whitespace -F '/tmp/it'\''s.txt'`,
+	}
+
+	execCount := 0
+	for i, line := range lines {
+		if strings.HasPrefix(line, "exec ") {
+			execCount++
+			continue
+		}
+		if expected, ok := want[line]; ok {
+			if i+1 >= len(lines) || lines[i+1] != expected {
+				t.Errorf("line after %q = %q, want %q", line, lines[i+1], expected)
+			}
+		}
+	}
+
+	if execCount != 2 {
+		t.Errorf("RewriteRebaseTodo() added %d exec lines, want 2", execCount)
+	}
+}
@@ -0,0 +1,136 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Operation is a git operation that is waiting for its commit
+type Operation int
+
+const (
+	OperationNone   Operation = iota
+	OperationMerge            // git merge (MERGE_HEAD)
+	OperationRevert           // git revert (REVERT_HEAD)
+)
+
+// mergeSubjectPattern extracts what was merged from git's default merge subject
+var mergeSubjectPattern = regexp.MustCompile(`^Merge (?:remote-tracking )?(?:branch|tag|commit)(?:es)? '([^']+)'`)
+
+// SequencerState describes a merge or revert in progress
+type SequencerState struct {
+	Operation Operation
+	Heads     []string // MERGE_HEAD commits (several for octopus merges), or the REVERT_HEAD commit
+	Message   string   // Message prepared by git (MERGE_MSG)
+	Conflicts []string // Files that had conflicts, as listed by git in MERGE_MSG
+}
+
+// GetSequencerState reads the merge or revert state of the repository.
+// Operation is OperationNone when neither is in progress.
+func GetSequencerState() (SequencerState, error) {
+	gitDir, err := GetGitDir()
+	if err != nil {
+		return SequencerState{}, err
+	}
+
+	state := SequencerState{}
+	if heads := readStateFile(gitDir, "MERGE_HEAD"); heads != "" {
+		state.Operation = OperationMerge
+		state.Heads = strings.Fields(heads)
+	} else if head := readStateFile(gitDir, "REVERT_HEAD"); head != "" {
+		state.Operation = OperationRevert
+		state.Heads = []string{strings.TrimSpace(head)}
+	} else {
+		return state, nil
+	}
+
+	state.Message = readStateFile(gitDir, "MERGE_MSG")
+	state.Conflicts = ParseConflicts(state.Message, GetCommentChar())
+	return state, nil
+}
+
+// readStateFile returns the contents of a file in the git directory, or ""
+func readStateFile(gitDir, name string) string {
+	data, err := os.ReadFile(filepath.Join(gitDir, name))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// ParseConflicts returns the files git listed under "Conflicts:" in a merge
+// message. Newer git versions write the list as comments, older ones as text.
+func ParseConflicts(message, commentChar string) []string {
+	var conflicts []string
+	inList := false
+	for _, line := range strings.Split(message, "\n") {
+		line = strings.TrimPrefix(line, commentChar)
+		if strings.TrimSpace(line) == "Conflicts:" {
+			inList = true
+			continue
+		}
+		if !inList {
+			continue
+		}
+
+		path := strings.TrimSpace(line)
+		if path == "" && len(conflicts) == 0 {
+			continue
+		}
+		if path == "" || (!strings.HasPrefix(line, "\t") && !strings.HasPrefix(line, " ")) {
+			break
+		}
+		conflicts = append(conflicts, path)
+	}
+	return conflicts
+}
+
+// MergeSubject returns the subject git prepared for a merge (the first line
+// of MERGE_MSG) and the branch, tag or commit it names
+func MergeSubject(message string) (string, string) {
+	subject := strings.TrimSpace(strings.SplitN(strings.TrimSpace(message), "\n", 2)[0])
+	if matches := mergeSubjectPattern.FindStringSubmatch(subject); matches != nil {
+		return subject, matches[1]
+	}
+	return subject, ""
+}
+
+// FormatMergeMessage builds a merge commit message from git's subject, a
+// summary of what the merge brings in and the conflicts that were resolved
+func FormatMergeMessage(subject, summary string, conflicts []string) string {
+	var b strings.Builder
+	b.WriteString(subject)
+	b.WriteString("\n")
+
+	if summary = strings.TrimSpace(summary); summary != "" {
+		b.WriteString("\n")
+		b.WriteString(summary)
+		b.WriteString("\n")
+	}
+
+	if len(conflicts) > 0 {
+		b.WriteString("\nConflicts resolved:\n")
+		for _, path := range conflicts {
+			b.WriteString(fmt.Sprintf("- %s\n", path))
+		}
+	}
+
+	return b.String()
+}
+
+// RevertMessage returns the message for reverting commit: the reverted
+// subject with a revert type and git's "This reverts commit" line.
+// Reverting a revert restores the original subject.
+func RevertMessage(commit Commit) string {
+	subject := commit.Subject
+	if parsed, ok := ParseConventionalSubject(subject); ok && parsed.Type == "revert" {
+		subject = parsed.Description
+	} else {
+		subject = "revert: " + subject
+	}
+
+	return fmt.Sprintf("%s\n\nThis reverts commit %s.\n", subject, commit.Hash)
+}
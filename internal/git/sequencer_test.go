@@ -0,0 +1,83 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseConflicts(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []string
+	}{
+		{"none", "Merge branch 'feature'\n", nil},
+		{
+			"commented",
+			"Merge branch 'feature'\n\n# Conflicts:\n#\tcmd/root.go\n#\tREADME.md\n#\n# It looks like you may be committing a merge.\n",
+			[]string{"cmd/root.go", "README.md"},
+		},
+		{
+			"plain",
+			"Merge branch 'feature'\n\nConflicts:\n\tgo.mod\n",
+			[]string{"go.mod"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseConflicts(tt.message, "#"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseConflicts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeSubject(t *testing.T) {
+	tests := []struct {
+		message     string
+		wantSubject string
+		wantBranch  string
+	}{
+		{"Merge branch 'feature/login' into develop\n\n# Conflicts:\n", "Merge branch 'feature/login' into develop", "feature/login"},
+		{"Merge remote-tracking branch 'origin/main'\n", "Merge remote-tracking branch 'origin/main'", "origin/main"},
+		{"Merge tag 'v1.2.0'\n", "Merge tag 'v1.2.0'", "v1.2.0"},
+		{"Custom merge\n", "Custom merge", ""},
+	}
+
+	for _, tt := range tests {
+		subject, branch := MergeSubject(tt.message)
+		if subject != tt.wantSubject || branch != tt.wantBranch {
+			t.Errorf("MergeSubject(%q) = %q, %q, want %q, %q", tt.message, subject, branch, tt.wantSubject, tt.wantBranch)
+		}
+	}
+}
+
+func TestFormatMergeMessage(t *testing.T) {
+	got := FormatMergeMessage("Merge branch 'feature'", "- adds login\n", []string{"a.go", "b.go"})
+	want := "Merge branch 'feature'\n\n- adds login\n\nConflicts resolved:\n- a.go\n- b.go\n"
+	if got != want {
+		t.Errorf("FormatMergeMessage() = %q, want %q", got, want)
+	}
+
+	if got := FormatMergeMessage("Merge branch 'feature'", "", nil); got != "Merge branch 'feature'\n" {
+		t.Errorf("FormatMergeMessage() without summary = %q", got)
+	}
+}
+
+func TestRevertMessage(t *testing.T) {
+	tests := []struct {
+		subject string
+		want    string
+	}{
+		{"feat(auth): add login", "revert: feat(auth): add login\n\nThis reverts commit abc123.\n"},
+		{"wip", "revert: wip\n\nThis reverts commit abc123.\n"},
+		{"revert: feat(auth): add login", "feat(auth): add login\n\nThis reverts commit abc123.\n"},
+	}
+
+	for _, tt := range tests {
+		if got := RevertMessage(Commit{Hash: "abc123", Subject: tt.subject}); got != tt.want {
+			t.Errorf("RevertMessage(%q) = %q, want %q", tt.subject, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SplitGroup is a set of hunks meant to become one commit
+type SplitGroup struct {
+	Type  string // Suggested commit type (may be empty)
+	Label string // Why the hunks belong together, for display
+	Hunks []Hunk
+}
+
+// Files returns the distinct files touched by the group, in hunk order
+func (g SplitGroup) Files() []string {
+	var files []string
+	seen := make(map[string]bool)
+	for _, h := range g.Hunks {
+		if !seen[h.File] {
+			seen[h.File] = true
+			files = append(files, h.File)
+		}
+	}
+	return files
+}
+
+// GroupByTypeHints proposes one group per file type hint (docs, test, ci, ...)
+func GroupByTypeHints(patch *Patch) []SplitGroup {
+	var files []string
+	for _, f := range patch.Files {
+		files = append(files, f.Path)
+	}
+
+	var groups []SplitGroup
+	for _, hint := range AnalyzeFileTypes(files) {
+		group := SplitGroup{Type: hint.Type, Label: hint.Type + " files"}
+		for _, f := range patch.Files {
+			if contains(hint.Files, f.Path) {
+				group.Hunks = append(group.Hunks, f.Hunks...)
+			}
+		}
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// ClusterHunks proposes groups by clustering hunks that touch the same symbol,
+// in one file or across files (e.g. a function and its callers or its tests),
+// so unrelated hunks of one file can land in different commits. Hunks without
+// a symbol join the first symbol of their file, or else the other such hunks
+// in their directory.
+func ClusterHunks(patch *Patch) []SplitGroup {
+	hunks := patch.Hunks()
+	if len(hunks) == 0 {
+		return nil
+	}
+
+	// Union-find over hunk positions
+	parent := make([]int, len(hunks))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(a, b int) {
+		if ra, rb := find(a), find(b); ra != rb {
+			if ra < rb {
+				parent[rb] = ra
+			} else {
+				parent[ra] = rb
+			}
+		}
+	}
+
+	bySymbol := make(map[string]int)
+	byFile := make(map[string]int)
+	for i, h := range hunks {
+		if h.Symbol == "" {
+			continue
+		}
+		if first, ok := bySymbol[h.Symbol]; ok {
+			union(first, i)
+		} else {
+			bySymbol[h.Symbol] = i
+		}
+		if _, ok := byFile[h.File]; !ok {
+			byFile[h.File] = i
+		}
+	}
+
+	byDir := make(map[string]int)
+	for i, h := range hunks {
+		if h.Symbol != "" {
+			continue
+		}
+		if first, ok := byFile[h.File]; ok {
+			union(first, i)
+			continue
+		}
+		dir := path.Dir(h.File)
+		if first, ok := byDir[dir]; ok {
+			union(first, i)
+		} else {
+			byDir[dir] = i
+		}
+	}
+
+	// Collect clusters in order of their first hunk
+	clusters := make(map[int][]Hunk)
+	var roots []int
+	for i, h := range hunks {
+		root := find(i)
+		if _, ok := clusters[root]; !ok {
+			roots = append(roots, root)
+		}
+		clusters[root] = append(clusters[root], h)
+	}
+	sort.Ints(roots)
+
+	groups := make([]SplitGroup, 0, len(roots))
+	for _, root := range roots {
+		group := SplitGroup{Hunks: clusters[root]}
+		group.Label = clusterLabel(group)
+		group.Type = DominantType(group.Files())
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// clusterLabel names a cluster after its directories and symbols, e.g.
+// "internal/auth, cmd (Login)"
+func clusterLabel(group SplitGroup) string {
+	var dirs, symbols []string
+	for _, file := range group.Files() {
+		dir := path.Dir(file)
+		if dir == "." {
+			dir = "(root)"
+		}
+		if !contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	for _, h := range group.Hunks {
+		if h.Symbol != "" && !contains(symbols, h.Symbol) {
+			symbols = append(symbols, h.Symbol)
+		}
+	}
+
+	label := strings.Join(dirs, ", ")
+	if len(symbols) > 0 {
+		label += " (" + strings.Join(symbols, ", ") + ")"
+	}
+	return label
+}
+
+// DominantType returns the commit type suggested for most of the files,
+// or "" if there are none
+func DominantType(files []string) string {
+	best, bestCount := "", 0
+	for _, hint := range AnalyzeFileTypes(files) {
+		if len(hint.Files) > bestCount {
+			best, bestCount = hint.Type, len(hint.Files)
+		}
+	}
+	return best
+}
+
+// splitBackupMessage is the stash message used for index backups
+const splitBackupMessage = "gitai split backup"
+
+// IndexBackup records the branch head and staged tree before a split so the
+// original state can be restored if anything fails partway
+type IndexBackup struct {
+	Head  string // Commit HEAD pointed to (empty on an unborn branch)
+	Tree  string // Tree object written from the index
+	Stash string // Stash commit holding the index and working tree (empty on an unborn branch)
+}
+
+// BackupIndex saves the current index as a tree object and stores a stash
+// entry for it, so the split can still be undone after gitai exits
+func BackupIndex() (*IndexBackup, error) {
+	tree, err := exec.Command("git", "write-tree").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to back up the index: %w", err)
+	}
+
+	backup := &IndexBackup{Tree: strings.TrimSpace(string(tree))}
+	head, err := ResolveRev("HEAD")
+	if err != nil {
+		// Stashes need a commit to hang off; the tree alone is enough to restore
+		return backup, nil
+	}
+	backup.Head = head
+
+	stash, err := exec.Command("git", "stash", "create", splitBackupMessage).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to back up the index: %w", err)
+	}
+	backup.Stash = strings.TrimSpace(string(stash))
+	if backup.Stash == "" {
+		return backup, nil
+	}
+
+	message := fmt.Sprintf("%s (HEAD %s)", splitBackupMessage, shortHash(head))
+	if output, err := exec.Command("git", "stash", "store", "-m", message, backup.Stash).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to store the index backup: %s", strings.TrimSpace(string(output)))
+	}
+
+	return backup, nil
+}
+
+// UndoCommands returns the git commands that restore the state saved by the backup
+func (b *IndexBackup) UndoCommands() []string {
+	if b.Head == "" {
+		return []string{"git read-tree " + b.Tree}
+	}
+
+	index := b.Tree
+	if b.Stash != "" {
+		index = shortHash(b.Stash) + "^2"
+	}
+	return []string{
+		"git reset --soft " + shortHash(b.Head),
+		"git read-tree " + index,
+	}
+}
+
+// Restore moves the branch back to the original head and restores the index.
+// Commits created since the backup are dropped; the working tree is untouched.
+func (b *IndexBackup) Restore() error {
+	if b.Head != "" {
+		if output, err := exec.Command("git", "reset", "--soft", b.Head).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to reset to %s: %s", shortHash(b.Head), strings.TrimSpace(string(output)))
+		}
+	} else if HasCommits() {
+		// Unborn branch: remove the commits created since the backup
+		branch, _ := exec.Command("git", "symbolic-ref", "HEAD").Output()
+		if output, err := exec.Command("git", "update-ref", "-d", strings.TrimSpace(string(branch))).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to reset branch: %s", strings.TrimSpace(string(output)))
+		}
+	}
+
+	if output, err := exec.Command("git", "read-tree", b.Tree).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to restore the index: %s", strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// WriteSplitPatches writes one patch file per group to dir, replacing the
+// patches of a previous split, and returns their paths
+func WriteSplitPatches(dir string, patch *Patch, groups []SplitGroup) ([]string, error) {
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, fmt.Errorf("failed to clear %s: %w", dir, err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	var paths []string
+	for i, group := range groups {
+		name := fmt.Sprintf("%02d-%s.patch", i+1, group.Type)
+		if group.Type == "" {
+			name = fmt.Sprintf("%02d.patch", i+1)
+		}
+
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(patch.Select(group.Hunks)), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file, err)
+		}
+		paths = append(paths, file)
+	}
+
+	return paths, nil
+}
+
+// ResetIndex unstages everything, leaving the working tree untouched
+func ResetIndex() error {
+	args := []string{"read-tree", "--empty"}
+	if HasCommits() {
+		args = []string{"read-tree", "HEAD"}
+	}
+	if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to reset the index: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// HasCommits reports whether HEAD points to a commit
+func HasCommits() bool {
+	_, err := ResolveRev("HEAD")
+	return err == nil
+}
@@ -0,0 +1,105 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+const splitPatch = `diff --git a/internal/auth/login.go b/internal/auth/login.go
+--- a/internal/auth/login.go
++++ b/internal/auth/login.go
+@@ -1,3 +1,4 @@ func Login() error {
++	check()
+diff --git a/internal/auth/token.go b/internal/auth/token.go
+--- a/internal/auth/token.go
++++ b/internal/auth/token.go
+@@ -1,3 +1,4 @@ func Refresh() error {
++	renew()
+diff --git a/cmd/login.go b/cmd/login.go
+--- a/cmd/login.go
++++ b/cmd/login.go
+@@ -5,3 +5,4 @@ func Login() error {
++	log()
+diff --git a/README.md b/README.md
+--- a/README.md
++++ b/README.md
+@@ -1,2 +1,3 @@
++More docs
+`
+
+func TestGroupByTypeHints(t *testing.T) {
+	groups := GroupByTypeHints(ParsePatch(splitPatch))
+
+	if len(groups) != 2 {
+		t.Fatalf("GroupByTypeHints() returned %d groups, want 2", len(groups))
+	}
+	if groups[0].Type != "feat" || len(groups[0].Hunks) != 3 {
+		t.Errorf("groups[0] = %s with %d hunks, want feat with 3", groups[0].Type, len(groups[0].Hunks))
+	}
+	if groups[1].Type != "docs" || !reflect.DeepEqual(groups[1].Files(), []string{"README.md"}) {
+		t.Errorf("groups[1] = %s %v, want docs [README.md]", groups[1].Type, groups[1].Files())
+	}
+}
+
+func TestClusterHunks(t *testing.T) {
+	groups := ClusterHunks(ParsePatch(splitPatch))
+
+	// internal/auth and cmd share the Login symbol, Refresh in the same
+	// directory is unrelated and README stands alone
+	if len(groups) != 3 {
+		t.Fatalf("ClusterHunks() returned %d groups, want 3: %+v", len(groups), groups)
+	}
+
+	wantFiles := []string{"internal/auth/login.go", "cmd/login.go"}
+	if got := groups[0].Files(); !reflect.DeepEqual(got, wantFiles) {
+		t.Errorf("groups[0].Files() = %v, want %v", got, wantFiles)
+	}
+	if groups[0].Label != "internal/auth, cmd (Login)" {
+		t.Errorf("groups[0].Label = %q", groups[0].Label)
+	}
+	if got := groups[1].Files(); !reflect.DeepEqual(got, []string{"internal/auth/token.go"}) {
+		t.Errorf("groups[1].Files() = %v, want [internal/auth/token.go]", got)
+	}
+	if groups[2].Type != "docs" || groups[2].Label != "(root)" {
+		t.Errorf("groups[2] = %s %q, want docs (root)", groups[2].Type, groups[2].Label)
+	}
+}
+
+func TestClusterHunksInOneFile(t *testing.T) {
+	patch := ParsePatch(`diff --git a/server/server.go b/server/server.go
+--- a/server/server.go
++++ b/server/server.go
+@@ -1,4 +1,5 @@
+ import (
++	"time"
+@@ -20,3 +21,4 @@ func (s *Server) Start() error {
++	s.started = time.Now()
+@@ -80,3 +82,4 @@ func parseConfig(path string) (*Config, error) {
++	path = filepath.Clean(path)
+diff --git a/server/doc.go b/server/doc.go
+--- a/server/doc.go
++++ b/server/doc.go
+@@ -1,2 +1,3 @@
++// Package server runs the API
+`)
+	groups := ClusterHunks(patch)
+
+	// The import hunk goes with the first function of its file, the hunk of
+	// doc.go has no symbol and no sibling in its file, so it stands alone
+	want := [][]int{{0, 1}, {2}, {3}}
+	if len(groups) != len(want) {
+		t.Fatalf("ClusterHunks() returned %d groups, want %d: %+v", len(groups), len(want), groups)
+	}
+	for i, group := range groups {
+		var ids []int
+		for _, h := range group.Hunks {
+			ids = append(ids, h.ID)
+		}
+		if !reflect.DeepEqual(ids, want[i]) {
+			t.Errorf("groups[%d] hunks = %v, want %v", i, ids, want[i])
+		}
+	}
+	if groups[1].Label != "server (parseConfig)" {
+		t.Errorf("groups[1].Label = %q, want %q", groups[1].Label, "server (parseConfig)")
+	}
+}
@@ -0,0 +1,151 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// squashCommitPattern matches the "commit <sha>" lines git merge --squash
+// writes to SQUASH_MSG
+var squashCommitPattern = regexp.MustCompile(`(?m)^commit ([0-9a-f]{7,64})\s*$`)
+
+// perCommitTrailers identify a single commit and are dropped when squashing
+var perCommitTrailers = map[string]bool{
+	"change-id": true,
+}
+
+// squashTypePriority orders the types a squashed commit can take; the first
+// type present among the commits wins
+var squashTypePriority = []string{"feat", "fix", "perf", "refactor", "revert"}
+
+// ReadSquashMessage returns the SQUASH_MSG of a squash merge in progress, or
+// "" if there is none
+func ReadSquashMessage() (string, error) {
+	gitDir, err := GetGitDir()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(filepath.Join(gitDir, "SQUASH_MSG"))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read SQUASH_MSG: %w", err)
+	}
+	return string(data), nil
+}
+
+// ParseSquashMessage returns the hashes of the commits listed in a SQUASH_MSG,
+// newest first as git lists them
+func ParseSquashMessage(message string) []string {
+	var hashes []string
+	for _, match := range squashCommitPattern.FindAllStringSubmatch(message, -1) {
+		hashes = append(hashes, match[1])
+	}
+	return hashes
+}
+
+// ParseTrailers returns the trailers in the last paragraph of any commit
+// message, Conventional or not
+func ParseTrailers(message string) []Footer {
+	message = strings.TrimSpace(strings.ReplaceAll(message, "\r\n", "\n"))
+	parts := strings.SplitN(message, "\n", 2)
+	if len(parts) < 2 {
+		return nil
+	}
+
+	_, footers := splitFooters(strings.TrimSpace(parts[1]))
+	return footers
+}
+
+// SquashTrailers returns the trailers a squashed commit should keep: every
+// trailer of the commits (without duplicates, in order) except per-commit ones
+// and breaking change notes, followed by a Co-authored-by trailer for each
+// author other than the committer.
+func SquashTrailers(commits []Commit, committerEmail string) []Footer {
+	var trailers []Footer
+	seen := make(map[string]bool)
+	add := func(f Footer) {
+		key := strings.ToLower(f.Token) + ":" + strings.ToLower(f.Value)
+		if !seen[key] {
+			seen[key] = true
+			trailers = append(trailers, f)
+		}
+	}
+
+	for _, commit := range commits {
+		for _, f := range ParseTrailers(commit.Message()) {
+			token := strings.ToLower(f.Token)
+			if perCommitTrailers[token] || token == "breaking change" || token == "breaking-change" {
+				continue
+			}
+			add(f)
+		}
+	}
+
+	for _, commit := range commits {
+		if commit.Email == "" || strings.EqualFold(commit.Email, committerEmail) {
+			continue
+		}
+		add(Footer{Token: "Co-authored-by", Value: fmt.Sprintf("%s <%s>", commit.Author, commit.Email)})
+	}
+
+	return trailers
+}
+
+// SquashType returns the commit type a squash of commits should have: feat,
+// fix, perf, refactor or revert if any commit has it, else the most common
+// Conventional type. It returns "" if no commit is conventional.
+func SquashType(commits []Commit) string {
+	counts := make(map[string]int)
+	best := ""
+	for _, commit := range commits {
+		parsed, ok := ParseConventionalSubject(commit.Subject)
+		if !ok {
+			continue
+		}
+		counts[parsed.Type]++
+		if best == "" || counts[parsed.Type] > counts[best] {
+			best = parsed.Type
+		}
+	}
+
+	for _, commitType := range squashTypePriority {
+		if counts[commitType] > 0 {
+			return commitType
+		}
+	}
+	return best
+}
+
+// AppendTrailers adds the trailers message does not contain yet. They join an
+// existing trailer block, or start a new paragraph.
+func AppendTrailers(message string, trailers []Footer) string {
+	message = strings.TrimRight(message, "\n")
+
+	var missing []string
+	for _, f := range trailers {
+		line := f.Token + ": " + f.Value
+		if !strings.Contains(message, line) {
+			missing = append(missing, line)
+		}
+	}
+	if len(missing) == 0 {
+		return message + "\n"
+	}
+
+	separator := "\n\n"
+	if len(ParseTrailers(message)) > 0 {
+		separator = "\n"
+	}
+	return message + separator + strings.Join(missing, "\n") + "\n"
+}
+
+// GetUserEmail returns the configured user.email
+func GetUserEmail() string {
+	return getConfigValue("user.email")
+}
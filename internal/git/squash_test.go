@@ -0,0 +1,104 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSquashMessage(t *testing.T) {
+	message := `Squashed commit of the following:
+
+commit 3f2a1b4c5d6e7f8091a2b3c4d5e6f708192a3b4c
+Author: Jane <jane@example.com>
+Date:   Mon Jan 5 10:00:00 2026 +0100
+
+    wip
+
+commit 0a1b2c3d4e5f60718293a4b5c6d7e8f901234567
+Author: Jane <jane@example.com>
+Date:   Mon Jan 5 09:00:00 2026 +0100
+
+    feat: add login
+`
+
+	want := []string{"3f2a1b4c5d6e7f8091a2b3c4d5e6f708192a3b4c", "0a1b2c3d4e5f60718293a4b5c6d7e8f901234567"}
+	if got := ParseSquashMessage(message); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSquashMessage() = %v, want %v", got, want)
+	}
+	if got := ParseSquashMessage("feat: add login"); got != nil {
+		t.Errorf("ParseSquashMessage() = %v, want nil", got)
+	}
+}
+
+func TestSquashTrailers(t *testing.T) {
+	commits := []Commit{
+		{Subject: "feat: add login", Body: "Refs: PROJ-1\nSigned-off-by: Jane <jane@example.com>", Author: "Jane", Email: "jane@example.com"},
+		{Subject: "wip", Body: "Change-Id: I123\nSigned-off-by: Jane <jane@example.com>", Author: "Bob", Email: "bob@example.com"},
+		{Subject: "fix!: tokens", Body: "BREAKING CHANGE: tokens expire", Author: "Jane", Email: "jane@example.com"},
+	}
+
+	want := []Footer{
+		{Token: "Refs", Value: "PROJ-1"},
+		{Token: "Signed-off-by", Value: "Jane <jane@example.com>"},
+		{Token: "Co-authored-by", Value: "Bob <bob@example.com>"},
+	}
+	if got := SquashTrailers(commits, "JANE@example.com"); !reflect.DeepEqual(got, want) {
+		t.Errorf("SquashTrailers() = %v, want %v", got, want)
+	}
+}
+
+func TestSquashType(t *testing.T) {
+	tests := []struct {
+		subjects []string
+		want     string
+	}{
+		{[]string{"wip", "docs: a", "fix: b", "feat: c"}, "feat"},
+		{[]string{"docs: a", "fix: b"}, "fix"},
+		{[]string{"docs: a", "test: b", "test: c"}, "test"},
+		{[]string{"wip", "more wip"}, ""},
+	}
+
+	for _, tt := range tests {
+		var commits []Commit
+		for _, subject := range tt.subjects {
+			commits = append(commits, Commit{Subject: subject})
+		}
+		if got := SquashType(commits); got != tt.want {
+			t.Errorf("SquashType(%v) = %q, want %q", tt.subjects, got, tt.want)
+		}
+	}
+}
+
+func TestAppendTrailers(t *testing.T) {
+	trailers := []Footer{{Token: "Refs", Value: "PROJ-1"}, {Token: "Signed-off-by", Value: "Jane <jane@example.com>"}}
+
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{
+			name:    "new block",
+			message: "feat: add login\n\n- adds OAuth2\n",
+			want:    "feat: add login\n\n- adds OAuth2\n\nRefs: PROJ-1\nSigned-off-by: Jane <jane@example.com>\n",
+		},
+		{
+			name:    "existing block",
+			message: "feat: add login\n\nReviewed-by: Bob",
+			want:    "feat: add login\n\nReviewed-by: Bob\nRefs: PROJ-1\nSigned-off-by: Jane <jane@example.com>\n",
+		},
+		{
+			name:    "already present",
+			message: "feat: add login\n\nRefs: PROJ-1\nSigned-off-by: Jane <jane@example.com>",
+			want:    "feat: add login\n\nRefs: PROJ-1\nSigned-off-by: Jane <jane@example.com>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AppendTrailers(tt.message, trailers); got != tt.want {
+				t.Errorf("AppendTrailers() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
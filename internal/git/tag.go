@@ -0,0 +1,59 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ListTags returns the tags reachable from rev (all tags if rev is empty)
+func ListTags(rev string) ([]string, error) {
+	args := []string{"tag", "--list"}
+	if rev != "" {
+		args = append(args, "--merged", rev)
+	}
+
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	return strings.Fields(string(output)), nil
+}
+
+// TagExists reports whether a tag with the given name exists
+func TagExists(name string) bool {
+	return exec.Command("git", "rev-parse", "--verify", "--quiet", "refs/tags/"+name).Run() == nil
+}
+
+// CreateTag creates an annotated tag on rev with the given message. With sign
+// the tag is GPG signed (git tag -s); tag.gpgSign is honored by git itself.
+func CreateTag(name, rev, message string, sign bool) error {
+	if TagExists(name) {
+		return fmt.Errorf("tag %s already exists", name)
+	}
+
+	path, cleanup, err := writeMessageFile(message)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	args := []string{"tag", "--annotate", "--cleanup=verbatim", "-F", path}
+	if sign {
+		args = append(args, "--sign")
+	}
+	args = append(args, name, rev)
+
+	cmd := exec.Command("git", args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = gitEnv(sign || getConfigBool("tag.gpgsign"))
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to create tag %s: %w", name, err)
+	}
+
+	return nil
+}
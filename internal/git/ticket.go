@@ -2,6 +2,7 @@ package git
 
 import (
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -15,7 +16,8 @@ func ExtractTicketFromBranch(branchName, pattern string) string {
 		return ""
 	}
 
-	// If custom pattern provided, use it
+	// If custom pattern provided, use it. Invalid patterns are rejected when
+	// the configuration is loaded.
 	if pattern != "" {
 		re, err := regexp.Compile(pattern)
 		if err == nil {
@@ -65,3 +67,68 @@ func FormatTicketNumber(ticket, prefix string) string {
 
 	return ticket
 }
+
+// Generic ticket references inside commit messages, used when no
+// ticket_pattern or ticket_prefix is configured
+var (
+	ticketKeyPattern   = regexp.MustCompile(`\b([A-Z]{2,}[A-Z0-9]*)-\d+\b`) // PROJ-123, GH-42
+	issueNumberPattern = regexp.MustCompile(`(?:^|[\s(\[])(#\d+)\b`)        // #123
+)
+
+// notTicketKeys are well-known names that are followed by a number, such as
+// UTF-8 or SHA-256, and are not ticket keys
+var notTicketKeys = map[string]bool{
+	"AES": true, "ANSI": true, "ASCII": true, "BASE": true, "CRC": true, "CVE": true, "CWE": true,
+	"DES": true, "ECMA": true, "ES": true, "GMT": true, "HTTP": true, "HTTPS": true, "IEEE": true,
+	"IPV": true, "ISO": true, "MD": true, "PEP": true, "RFC": true, "RSA": true, "SHA": true,
+	"SSL": true, "TLS": true, "UCS": true, "UTC": true, "UTF": true, "WCAG": true,
+}
+
+// ExtractTickets returns the distinct ticket references in text, in order of
+// appearance. Only pattern (ticket_pattern) or, without one, references with
+// prefix (ticket_prefix) are found when they are set; otherwise keys of two or
+// more letters such as PROJ-123, except names like UTF-8, and #123.
+func ExtractTickets(text, pattern, prefix string) []string {
+	var tickets []string
+	seen := make(map[string]bool)
+	add := func(ticket string) {
+		if !seen[ticket] {
+			seen[ticket] = true
+			tickets = append(tickets, ticket)
+		}
+	}
+
+	// Invalid patterns are rejected when the configuration is loaded
+	if pattern != "" {
+		if re, err := regexp.Compile(pattern); err == nil {
+			for _, match := range re.FindAllString(text, -1) {
+				add(match)
+			}
+		}
+		return tickets
+	}
+	if prefix != "" {
+		re := regexp.MustCompile(`\b` + regexp.QuoteMeta(prefix) + `-\d+\b`)
+		for _, match := range re.FindAllString(text, -1) {
+			add(match)
+		}
+		return tickets
+	}
+
+	// Both kinds in order of appearance
+	var matches [][]int
+	matches = append(matches, ticketKeyPattern.FindAllStringSubmatchIndex(text, -1)...)
+	matches = append(matches, issueNumberPattern.FindAllStringSubmatchIndex(text, -1)...)
+	sort.Slice(matches, func(i, j int) bool { return matches[i][2] < matches[j][2] })
+	for _, match := range matches {
+		if key := text[match[2]:match[3]]; !notTicketKeys[key] {
+			if strings.HasPrefix(key, "#") {
+				add(key)
+			} else {
+				add(text[match[0]:match[1]])
+			}
+		}
+	}
+
+	return tickets
+}
@@ -0,0 +1,33 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractTickets(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		pattern string
+		prefix  string
+		want    []string
+	}{
+		{"jira key", "feat: add login [PROJ-123]", "", "", []string{"PROJ-123"}},
+		{"issue numbers", "fix: crash (#42)\n\nCloses #7 and #42", "", "", []string{"#42", "#7"}},
+		{"mixed", "Refs: #9, ABC-1", "", "", []string{"#9", "ABC-1"}},
+		{"only the custom pattern", "task_55 relates to OPS-2 and #3", `task_\d+`, "", []string{"task_55"}},
+		{"only the prefix", "OPS-2 relates to AUTH-7 and #3", "", "AUTH", []string{"AUTH-7"}},
+		{"no anchors inside words", "color#123 and utf-8", "", "", nil},
+		{"names with numbers", "use UTF-8 and SHA-256 for ISO-8601 dates over HTTP-2, see RFC-3339", "", "", nil},
+		{"single letters", "fix X-1 and B-52 in PROJ-1", "", "", []string{"PROJ-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractTickets(tt.text, tt.pattern, tt.prefix); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractTickets(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
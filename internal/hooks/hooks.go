@@ -212,8 +212,61 @@ if [ "$GITAI_HOOK" = "0" ] || [ "$GITAI_HOOK" = "false" ]; then
     exit 0
 fi
 
-# Skip if committing with -m, --amend, merge, or squash
-if [ "$COMMIT_SOURCE" = "message" ] || [ "$COMMIT_SOURCE" = "merge" ] || [ "$COMMIT_SOURCE" = "squash" ]; then
+# Commits made by gitai already have their message
+if [ "$GITAI_SKIP_PREPARE" = "1" ]; then
+    exit 0
+fi
+
+# Squash merge (git merge --squash): replace git's list of squashed commits
+# with one consolidated message
+if [ "$COMMIT_SOURCE" = "squash" ]; then
+    if ! command -v gitai >/dev/null 2>&1; then
+        exit 0
+    fi
+
+    echo "🤖 Consolidating squashed commits with GitAI..." >&2
+    if GENERATED_MSG=$(gitai squash-message --quiet 2>&1); then
+        echo "$GENERATED_MSG" > "$COMMIT_MSG_FILE"
+        echo "✅ Squash message generated. Edit in your editor if needed" >&2
+    else
+        echo "⚠️  GitAI generation failed, keeping git's squash message" >&2
+        echo "   Error: $GENERATED_MSG" >&2
+    fi
+    exit 0
+fi
+
+# Revert: while git revert is in progress (REVERT_HEAD), git prepares
+# 'Revert "..."' with "This reverts commit <sha>.". Only that default text is
+# replaced, never a message the user wrote (-m, -F or a revert they edited).
+REVERTED=""
+if [ "$COMMIT_SOURCE" != "message" ] && git rev-parse -q --verify REVERT_HEAD >/dev/null 2>&1 &&
+    head -n 1 "$COMMIT_MSG_FILE" | grep -q '^Revert "'; then
+    REVERTED=$(sed -n 's/^This reverts commit \([0-9a-f]*\).*/\1/p' "$COMMIT_MSG_FILE" | head -n 1)
+fi
+
+# Merge (MERGE_HEAD) or revert: describe it from the operation's state
+if [ "$COMMIT_SOURCE" = "merge" ] || [ -n "$REVERTED" ]; then
+    if ! command -v gitai >/dev/null 2>&1; then
+        exit 0
+    fi
+
+    echo "🤖 Describing merge/revert with GitAI..." >&2
+    if [ -n "$REVERTED" ]; then
+        GENERATE_ARGS="--revert $REVERTED"
+    else
+        GENERATE_ARGS=""
+    fi
+    if GENERATED_MSG=$(gitai generate --quiet $GENERATE_ARGS 2>&1); then
+        echo "$GENERATED_MSG" > "$COMMIT_MSG_FILE"
+    else
+        echo "⚠️  GitAI generation failed, keeping git's message" >&2
+        echo "   Error: $GENERATED_MSG" >&2
+    fi
+    exit 0
+fi
+
+# Skip if committing with -m or --amend
+if [ "$COMMIT_SOURCE" = "message" ]; then
     exit 0
 fi
 
@@ -273,7 +326,7 @@ fi
 
 # Basic validation (optional - can be customized)
 # Check if message follows conventional commits format
-if ! echo "$COMMIT_MSG" | grep -qE '^(feat|fix|docs|style|refactor|perf|test|chore|ci|build)(\(.+\))?: .+'; then
+if ! echo "$COMMIT_MSG" | grep -qE '^(feat|fix|docs|style|refactor|perf|test|chore|ci|build|revert)(\(.+\))?: .+'; then
     echo "⚠️  Commit message doesn't follow Conventional Commits format" >&2
     echo "   Expected: <type>(<scope>): <subject>" >&2
     echo "   Example: feat(auth): add user login" >&2
@@ -290,22 +343,30 @@ exit 0
 // getPreCommitTemplate returns pre-commit hook template
 func (hm *HookManager) getPreCommitTemplate() string {
 	return `#!/bin/sh
-# GitAI - Pre-commit Hook
-# This hook runs before commit to check for issues
+# GitAI - Pre-commit Check
+# Scans the staged changes for debug prints, new TODOs, large binaries,
+# conflict markers and secrets. Rules set to "block" in precheck.rules stop
+# the commit, "warn" rules are only reported.
+#
+# Use GITAI_HOOK=0 to disable: GITAI_HOOK=0 git commit
+# Skip the check once with: git commit --no-verify
 
-# Check if there are staged changes
-if git diff --cached --quiet; then
-    echo "⚠️  No staged changes to commit" >&2
-    exit 1
+# Allow disabling hook with environment variable
+if [ "$GITAI_HOOK" = "0" ] || [ "$GITAI_HOOK" = "false" ]; then
+    exit 0
 fi
 
-# Additional checks can be added here:
-# - Linting
-# - Formatting
-# - Tests
-# etc.
+# Check if gitai is available
+if ! command -v gitai >/dev/null 2>&1; then
+    exit 0
+fi
 
-exit 0
+# Nothing staged (e.g. git commit --allow-empty)
+if git diff --cached --quiet 2>/dev/null; then
+    exit 0
+fi
+
+exec gitai check
 `
 }
 
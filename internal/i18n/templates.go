@@ -227,3 +227,33 @@ func GetMultilingualInstructions(languages []string) string {
 
 	return instruction
 }
+
+// NotesHeadings holds the section headings of release notes in one language
+type NotesHeadings struct {
+	Highlights   string
+	Upgrade      string
+	Contributors string
+}
+
+// notesHeadings holds the release notes headings of each supported language
+var notesHeadings = map[string]NotesHeadings{
+	"en": {Highlights: "Highlights", Upgrade: "Upgrade notes", Contributors: "Thanks to our contributors"},
+	"zh": {Highlights: "亮点", Upgrade: "升级说明", Contributors: "感谢贡献者"},
+	"ja": {Highlights: "ハイライト", Upgrade: "アップグレードに関する注意", Contributors: "コントリビューターの皆さんに感謝します"},
+	"ko": {Highlights: "주요 변경 사항", Upgrade: "업그레이드 안내", Contributors: "기여해 주신 분들께 감사드립니다"},
+	"de": {Highlights: "Highlights", Upgrade: "Hinweise zum Upgrade", Contributors: "Danke an alle Mitwirkenden"},
+	"fr": {Highlights: "Points forts", Upgrade: "Notes de mise à jour", Contributors: "Merci à nos contributeurs"},
+	"es": {Highlights: "Novedades", Upgrade: "Notas de actualización", Contributors: "Gracias a quienes contribuyeron"},
+	"pt": {Highlights: "Destaques", Upgrade: "Notas de atualização", Contributors: "Obrigado a quem contribuiu"},
+	"ru": {Highlights: "Главное", Upgrade: "Заметки по обновлению", Contributors: "Спасибо участникам"},
+	"it": {Highlights: "In evidenza", Upgrade: "Note di aggiornamento", Contributors: "Grazie a chi ha contribuito"},
+}
+
+// GetNotesHeadings returns the release notes headings for a language code,
+// falling back to English
+func GetNotesHeadings(langCode string) NotesHeadings {
+	if headings, ok := notesHeadings[NormalizeLanguageCode(langCode)]; ok {
+		return headings
+	}
+	return notesHeadings["en"]
+}
@@ -0,0 +1,288 @@
+package ignore
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// FileName is the ignore file read from the top of the repository
+const FileName = ".gitaiignore"
+
+// rule is one compiled ignore pattern
+type rule struct {
+	pattern  *regexp.Regexp
+	negate   bool // "!pattern" re-includes paths
+	dirOnly  bool // "pattern/" only matches directories
+	anchored bool // Patterns with a slash match from the top, others match any name
+}
+
+// Matcher decides which paths must not be sent to the model. Patterns use
+// .gitignore syntax: "*", "?", "[a-z]" and "**" globs, a trailing "/" for
+// directories, a leading "/" to anchor at the top and "!" to re-include.
+// The last matching pattern wins.
+type Matcher struct {
+	rules []rule
+}
+
+// New compiles patterns into a matcher. Empty lines and lines starting with
+// "#" are skipped.
+func New(patterns []string) (*Matcher, error) {
+	m := &Matcher{}
+	for _, pattern := range patterns {
+		if err := m.add(pattern); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Load reads the .gitaiignore file in root, if there is one, followed by the
+// configured patterns
+func Load(root string, patterns []string) (*Matcher, error) {
+	var all []string
+	file, err := os.Open(filepath.Join(root, FileName))
+	if err == nil {
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			all = append(all, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", FileName, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", FileName, err)
+	}
+
+	return New(append(all, patterns...))
+}
+
+// add compiles one pattern
+func (m *Matcher) add(pattern string) error {
+	line := strings.TrimRight(pattern, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil
+	}
+
+	var r rule
+	if strings.HasPrefix(line, "!") {
+		r.negate = true
+		line = line[1:]
+	}
+	line = strings.TrimPrefix(line, `\`) // "\#file" and "\!file" match literally
+	if strings.HasSuffix(line, "/") {
+		r.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if strings.Contains(line, "/") {
+		r.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return fmt.Errorf("invalid ignore pattern %q", pattern)
+	}
+
+	re, err := regexp.Compile("^" + globToRegexp(line) + "$")
+	if err != nil {
+		return fmt.Errorf("invalid ignore pattern %q: %w", pattern, err)
+	}
+	r.pattern = re
+	m.rules = append(m.rules, r)
+	return nil
+}
+
+// globToRegexp translates a glob into a regular expression
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**") && i+3 == len(glob):
+			b.WriteString("(/.*)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// Empty reports whether the matcher has no patterns
+func (m *Matcher) Empty() bool {
+	return m == nil || len(m.rules) == 0
+}
+
+// Match reports whether path, relative to the top of the repository, is
+// ignored. A path is also ignored when one of its directories is.
+func (m *Matcher) Match(path string) bool {
+	if m.Empty() {
+		return false
+	}
+	path = strings.TrimPrefix(filepath.ToSlash(path), "./")
+
+	ignored := false
+	for _, r := range m.rules {
+		if r.matches(path) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// matches reports whether the rule matches path or one of its directories
+func (r rule) matches(path string) bool {
+	parts := strings.Split(path, "/")
+	for i := range parts {
+		isDir := i < len(parts)-1
+		if r.dirOnly && !isDir {
+			continue
+		}
+		candidate := parts[i]
+		if r.anchored {
+			candidate = strings.Join(parts[:i+1], "/")
+		}
+		if r.pattern.MatchString(candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// Exclusion is a file whose content was removed from a diff
+type Exclusion struct {
+	Path  string
+	Lines int // Lines added and removed
+}
+
+// FilterDiff replaces the content of ignored files in a unified diff with a
+// "N lines changed in <path>" summary. The file headers stay, so the file
+// still counts as changed, added or deleted.
+func (m *Matcher) FilterDiff(diff string) (string, []Exclusion) {
+	if m.Empty() {
+		return diff, nil
+	}
+
+	var b strings.Builder
+	var exclusions []Exclusion
+	for _, section := range splitFiles(diff) {
+		path, other := diffPaths(section)
+		if path == "" || !(m.Match(path) || (other != "" && m.Match(other))) {
+			b.WriteString(section)
+			continue
+		}
+
+		header, lines := summarizeFile(section)
+		b.WriteString(header)
+		b.WriteString(Summary(path, lines))
+		b.WriteString("\n")
+		exclusions = append(exclusions, Exclusion{Path: path, Lines: lines})
+	}
+	return b.String(), exclusions
+}
+
+// Summary is the neutral text that stands in for an ignored file's changes
+func Summary(path string, lines int) string {
+	if lines == 1 {
+		return fmt.Sprintf("1 line changed in %s", path)
+	}
+	return fmt.Sprintf("%d lines changed in %s", lines, path)
+}
+
+// splitFiles splits a diff into one section per file, keeping the text
+// before the first file (e.g. a patch's mail header) as its own section
+func splitFiles(diff string) []string {
+	var sections []string
+	start := 0
+	for i := 0; i < len(diff); {
+		end := strings.IndexByte(diff[i:], '\n')
+		next := len(diff)
+		if end >= 0 {
+			next = i + end + 1
+		}
+		if strings.HasPrefix(diff[i:], "diff --git ") && i > start {
+			sections = append(sections, diff[start:i])
+			start = i
+		}
+		i = next
+	}
+	if start < len(diff) {
+		sections = append(sections, diff[start:])
+	}
+	return sections
+}
+
+// diffPaths returns the new and old path of a file section, or "" for text
+// that is not a file diff. The old path differs only for renames.
+func diffPaths(section string) (string, string) {
+	if !strings.HasPrefix(section, "diff --git ") {
+		return "", ""
+	}
+
+	var path, old string
+	for _, line := range strings.Split(section, "\n") {
+		switch {
+		case strings.HasPrefix(line, "rename from "):
+			old = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "+++ b/"):
+			path = strings.TrimPrefix(line, "+++ b/")
+		case strings.HasPrefix(line, "--- a/") && path == "":
+			path = strings.TrimPrefix(line, "--- a/")
+		case strings.HasPrefix(line, "@@"):
+			return path, old
+		}
+	}
+
+	if path == "" {
+		// No content lines (binary files, pure renames): use the header
+		fields := strings.Fields(strings.SplitN(section, "\n", 2)[0])
+		if len(fields) >= 4 {
+			path = strings.TrimPrefix(fields[3], "b/")
+		}
+	}
+	return path, old
+}
+
+// summarizeFile returns the header lines of a file section without content
+// and the number of lines it adds and removes
+func summarizeFile(section string) (string, int) {
+	var header strings.Builder
+	lines := 0
+	inHunks := false
+	for _, line := range strings.Split(strings.TrimSuffix(section, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"), strings.HasPrefix(line, "Binary files"), strings.HasPrefix(line, "GIT binary patch"):
+			inHunks = true
+		case !inHunks && !strings.HasPrefix(line, "index "):
+			header.WriteString(line + "\n")
+		case inHunks && (strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-")):
+			lines++
+		}
+	}
+	return header.String(), lines
+}
@@ -0,0 +1,129 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	m, err := New([]string{
+		"# comment",
+		"secrets/",
+		"/infra/prod/**",
+		"*.pem",
+		"fixtures/customer-*.json",
+		"!fixtures/customer-demo.json",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"secrets/db.env", true},
+		{"app/secrets/db.env", true},
+		{"secrets", false}, // A file, not the directory
+		{"infra/prod/main.tf", true},
+		{"infra/prod/modules/vpc/main.tf", true},
+		{"infra/staging/main.tf", false},
+		{"deploy/infra/prod/main.tf", false},
+		{"certs/server.pem", true},
+		{"server.pem.go", false},
+		{"fixtures/customer-acme.json", true},
+		{"fixtures/customer-demo.json", false},
+		{"fixtures/sub/customer-acme.json", false},
+		{"cmd/root.go", false},
+	}
+
+	for _, tt := range tests {
+		if got := m.Match(tt.path); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestMatchNil(t *testing.T) {
+	var m *Matcher
+	if m.Match("secrets/db.env") || !m.Empty() {
+		t.Error("nil matcher should ignore nothing")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, FileName), []byte("secrets/\n\n# comment\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := Load(dir, []string{"*.key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.Match("secrets/a") || !m.Match("tls/server.key") || m.Match("main.go") {
+		t.Error("Load() should combine the ignore file and the configured patterns")
+	}
+
+	if m, err := Load(t.TempDir(), nil); err != nil || !m.Empty() {
+		t.Errorf("Load() without an ignore file = %v, %v, want an empty matcher", m, err)
+	}
+}
+
+const testDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,2 +1,3 @@
+ package main
++// hello
+diff --git a/secrets/db.env b/secrets/db.env
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/secrets/db.env
+@@ -0,0 +1,2 @@
++DB_USER=admin
++DB_PASSWORD=hunter22
+diff --git a/secrets/old.env b/secrets/old.env
+deleted file mode 100644
+index 4444444..0000000
+--- a/secrets/old.env
++++ /dev/null
+@@ -1 +0,0 @@
+-TOKEN=abc
+`
+
+func TestFilterDiff(t *testing.T) {
+	m, err := New([]string{"secrets/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filtered, exclusions := m.FilterDiff(testDiff)
+	for _, leaked := range []string{"hunter22", "DB_USER", "TOKEN=abc"} {
+		if strings.Contains(filtered, leaked) {
+			t.Errorf("FilterDiff() kept %q:\n%s", leaked, filtered)
+		}
+	}
+	for _, kept := range []string{"+// hello", "new file mode", "deleted file mode", "2 lines changed in secrets/db.env", "1 line changed in secrets/old.env"} {
+		if !strings.Contains(filtered, kept) {
+			t.Errorf("FilterDiff() lost %q:\n%s", kept, filtered)
+		}
+	}
+
+	if len(exclusions) != 2 || exclusions[0] != (Exclusion{Path: "secrets/db.env", Lines: 2}) {
+		t.Errorf("FilterDiff() exclusions = %+v", exclusions)
+	}
+}
+
+func TestFilterDiffRename(t *testing.T) {
+	diff := "diff --git a/secrets/a.env b/config/a.env\nsimilarity index 90%\nrename from secrets/a.env\nrename to config/a.env\n--- a/secrets/a.env\n+++ b/config/a.env\n@@ -1 +1 @@\n-KEY=1\n+KEY=2\n"
+	m, _ := New([]string{"secrets/"})
+	filtered, exclusions := m.FilterDiff(diff)
+	if strings.Contains(filtered, "KEY=") || len(exclusions) != 1 {
+		t.Errorf("FilterDiff() should drop files renamed out of an ignored directory:\n%s", filtered)
+	}
+}
@@ -0,0 +1,105 @@
+package pr
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Description is a generated pull or merge request description
+type Description struct {
+	Title   string   `json:"title"`
+	Body    string   `json:"body"`
+	Base    string   `json:"base"`
+	Head    string   `json:"head"`
+	Tickets []string `json:"tickets,omitempty"`
+}
+
+// String returns the title and body separated by a blank line
+func (d Description) String() string {
+	return d.Title + "\n\n" + strings.TrimSpace(d.Body) + "\n"
+}
+
+// Area is a part of the codebase touched by a pull request
+type Area struct {
+	Name  string // Directory, or "(root)" for top-level files
+	Files []string
+}
+
+// rootArea names the area of files at the top of the repository
+const rootArea = "(root)"
+
+// Areas groups files by directory, cutting directories to at most depth
+// elements (internal/git/log.go → "internal/git" with depth 2). Areas are
+// sorted by name with top-level files first.
+func Areas(files []string, depth int) []Area {
+	byName := make(map[string]*Area)
+	for _, file := range files {
+		name := path.Dir(file)
+		if name == "." {
+			name = rootArea
+		} else if elements := strings.Split(name, "/"); depth > 0 && len(elements) > depth {
+			name = strings.Join(elements[:depth], "/")
+		}
+
+		if byName[name] == nil {
+			byName[name] = &Area{Name: name}
+		}
+		byName[name].Files = append(byName[name].Files, file)
+	}
+
+	areas := make([]Area, 0, len(byName))
+	for _, area := range byName {
+		areas = append(areas, *area)
+	}
+	sort.Slice(areas, func(i, j int) bool {
+		if (areas[i].Name == rootArea) != (areas[j].Name == rootArea) {
+			return areas[i].Name == rootArea
+		}
+		return areas[i].Name < areas[j].Name
+	})
+	return areas
+}
+
+// Describe returns a one-line summary of the area for prompts
+func (a Area) Describe() string {
+	const maxFiles = 5
+	names := make([]string, 0, maxFiles)
+	for i, file := range a.Files {
+		if i == maxFiles {
+			names = append(names, fmt.Sprintf("%d more", len(a.Files)-maxFiles))
+			break
+		}
+		names = append(names, path.Base(file))
+	}
+	return fmt.Sprintf("%s: %s", a.Name, strings.Join(names, ", "))
+}
+
+// LinkTickets makes sure every ticket is referenced in body. Tickets the body
+// does not mention yet are listed in a "Related tickets" section, linked
+// through ticketURL (with a {ticket} placeholder) when it is set.
+func LinkTickets(body string, tickets []string, ticketURL string) string {
+	var missing []string
+	for _, ticket := range tickets {
+		if !strings.Contains(body, ticket) {
+			missing = append(missing, ticket)
+		}
+	}
+	if len(missing) == 0 {
+		return body
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(body, "\n"))
+	b.WriteString("\n\n## Related tickets\n\n")
+	for _, ticket := range missing {
+		if ticketURL != "" {
+			url := strings.ReplaceAll(ticketURL, "{ticket}", strings.TrimPrefix(ticket, "#"))
+			b.WriteString(fmt.Sprintf("- [%s](%s)\n", ticket, url))
+		} else {
+			b.WriteString(fmt.Sprintf("- %s\n", ticket))
+		}
+	}
+	return b.String()
+}
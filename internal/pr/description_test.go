@@ -0,0 +1,65 @@
+package pr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAreas(t *testing.T) {
+	files := []string{"internal/git/log.go", "cmd/pr.go", "README.md", "internal/git/tag.go", "internal/ai/pr.go", "cmd/root.go"}
+
+	var got []string
+	for _, area := range Areas(files, 2) {
+		got = append(got, area.Name+"="+strings.Join(area.Files, ","))
+	}
+
+	want := []string{
+		"(root)=README.md",
+		"cmd=cmd/pr.go,cmd/root.go",
+		"internal/ai=internal/ai/pr.go",
+		"internal/git=internal/git/log.go,internal/git/tag.go",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Areas() = %v, want %v", got, want)
+	}
+
+	if got := Areas([]string{"a/b/c/d.go"}, 1); got[0].Name != "a" {
+		t.Errorf("Areas(depth 1) = %q, want \"a\"", got[0].Name)
+	}
+}
+
+func TestLinkTickets(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		tickets []string
+		url     string
+		want    string
+	}{
+		{"no tickets", "## Summary\n", nil, "", "## Summary\n"},
+		{"already mentioned", "Fixes PROJ-1.", []string{"PROJ-1"}, "", "Fixes PROJ-1."},
+		{
+			"missing",
+			"## Summary\n\nText\n",
+			[]string{"PROJ-1", "#42"},
+			"",
+			"## Summary\n\nText\n\n## Related tickets\n\n- PROJ-1\n- #42\n",
+		},
+		{
+			"linked",
+			"Text",
+			[]string{"#42"},
+			"https://github.com/o/r/issues/{ticket}",
+			"Text\n\n## Related tickets\n\n- [#42](https://github.com/o/r/issues/42)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LinkTickets(tt.body, tt.tickets, tt.url); got != tt.want {
+				t.Errorf("LinkTickets() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package pr
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// templateFiles are the single-template locations GitHub and GitLab look at,
+// in order of preference. Names are matched case-insensitively.
+var templateFiles = []string{
+	".github/pull_request_template.md",
+	"pull_request_template.md",
+	"docs/pull_request_template.md",
+	".gitlab/merge_request_templates/default.md",
+}
+
+// templateDirs hold several templates; the first one alphabetically is used
+var templateDirs = []string{
+	".github/PULL_REQUEST_TEMPLATE",
+	"PULL_REQUEST_TEMPLATE",
+	"docs/PULL_REQUEST_TEMPLATE",
+	".gitlab/merge_request_templates",
+}
+
+// FindTemplate returns the path and contents of the repository's pull or
+// merge request template below root. The path is empty if there is none.
+func FindTemplate(root string) (string, string, error) {
+	for _, name := range templateFiles {
+		if path := findFile(root, name); path != "" {
+			return readTemplate(path)
+		}
+	}
+
+	for _, dir := range templateDirs {
+		path := findFile(root, dir)
+		if path == "" {
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			continue
+		}
+
+		var names []string
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.EqualFold(filepath.Ext(entry.Name()), ".md") {
+				names = append(names, entry.Name())
+			}
+		}
+		if len(names) > 0 {
+			sort.Strings(names)
+			return readTemplate(filepath.Join(path, names[0]))
+		}
+	}
+
+	return "", "", nil
+}
+
+// readTemplate reads a template file
+func readTemplate(path string) (string, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read PR template: %w", err)
+	}
+	return path, string(data), nil
+}
+
+// findFile resolves a slash-separated relative path below root, matching each
+// element case-insensitively. It returns "" if the path does not exist.
+func findFile(root, name string) string {
+	path := root
+	for _, element := range strings.Split(name, "/") {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return ""
+		}
+
+		found := ""
+		for _, entry := range entries {
+			if entry.Name() == element {
+				found = entry.Name()
+				break
+			}
+			if found == "" && strings.EqualFold(entry.Name(), element) {
+				found = entry.Name()
+			}
+		}
+		if found == "" {
+			return ""
+		}
+		path = filepath.Join(path, found)
+	}
+	return path
+}
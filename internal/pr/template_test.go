@@ -0,0 +1,54 @@
+package pr
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindTemplate(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  string
+	}{
+		{"none", []string{"README.md"}, ""},
+		{"github", []string{".github/pull_request_template.md"}, ".github/pull_request_template.md"},
+		{"upper case", []string{".github/PULL_REQUEST_TEMPLATE.md"}, ".github/PULL_REQUEST_TEMPLATE.md"},
+		{"root before docs", []string{"docs/pull_request_template.md", "pull_request_template.md"}, "pull_request_template.md"},
+		{"gitlab", []string{".gitlab/merge_request_templates/Default.md"}, ".gitlab/merge_request_templates/Default.md"},
+		{"directory", []string{".github/PULL_REQUEST_TEMPLATE/feature.md", ".github/PULL_REQUEST_TEMPLATE/bugfix.md"}, ".github/PULL_REQUEST_TEMPLATE/bugfix.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for _, file := range tt.files {
+				path := filepath.Join(root, filepath.FromSlash(file))
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte("## "+file), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			path, content, err := FindTemplate(root)
+			if err != nil {
+				t.Fatalf("FindTemplate() error = %v", err)
+			}
+			if tt.want == "" {
+				if path != "" {
+					t.Errorf("FindTemplate() = %q, want none", path)
+				}
+				return
+			}
+			if want := filepath.Join(root, filepath.FromSlash(tt.want)); path != want {
+				t.Errorf("FindTemplate() = %q, want %q", path, want)
+			}
+			if content != "## "+tt.want {
+				t.Errorf("FindTemplate() content = %q", content)
+			}
+		})
+	}
+}
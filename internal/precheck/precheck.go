@@ -0,0 +1,230 @@
+package precheck
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xyue92/gitai/internal/git"
+	"github.com/xyue92/gitai/internal/ignore"
+)
+
+// Level decides what a finding of a rule does to the commit
+type Level string
+
+const (
+	LevelOff   Level = "off"   // The rule does not run
+	LevelWarn  Level = "warn"  // Findings are reported, the commit goes ahead
+	LevelBlock Level = "block" // Findings stop the commit
+)
+
+// Rule names, as used in the precheck.rules configuration
+const (
+	RuleDebug    = "debug"    // Debug prints and breakpoints
+	RuleTodo     = "todo"     // TODO/FIXME comments added by the change
+	RuleBinary   = "binary"   // Large binary files
+	RuleConflict = "conflict" // Merge conflict markers
+	RuleSecret   = "secret"   // Credentials, keys and tokens
+	RuleReview   = "review"   // Bugs and missing tests reported by the model
+)
+
+// DefaultLevels are the levels of rules that are not configured
+var DefaultLevels = map[string]Level{
+	RuleDebug:    LevelWarn,
+	RuleTodo:     LevelWarn,
+	RuleBinary:   LevelWarn,
+	RuleConflict: LevelBlock,
+	RuleSecret:   LevelBlock,
+	RuleReview:   LevelWarn,
+}
+
+// DefaultMaxBinarySize is the size above which binary files are reported
+const DefaultMaxBinarySize = 1024 * 1024
+
+// hunkStartPattern captures the first line number of the new side of a hunk
+var hunkStartPattern = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)`)
+
+// Finding is a problem found in the staged changes
+type Finding struct {
+	Rule    string `json:"rule"`
+	Level   Level  `json:"level"`
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"` // 0 when the finding is not about one line
+	Message string `json:"message"`
+	Source  string `json:"source"` // "rules" or "model"
+}
+
+// Location returns "file:line", or the file alone
+func (f Finding) Location() string {
+	if f.Line > 0 {
+		return fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	return f.File
+}
+
+// Report is the result of a check
+type Report struct {
+	Findings []Finding `json:"findings"`
+}
+
+// Blocked reports whether a finding stops the commit
+func (r Report) Blocked() bool {
+	for _, finding := range r.Findings {
+		if finding.Level == LevelBlock {
+			return true
+		}
+	}
+	return false
+}
+
+// Count returns the number of findings at level
+func (r Report) Count(level Level) int {
+	count := 0
+	for _, finding := range r.Findings {
+		if finding.Level == level {
+			count++
+		}
+	}
+	return count
+}
+
+// Options configures a check
+type Options struct {
+	Levels        map[string]string // Rule → "off", "warn" or "block"; missing rules use DefaultLevels
+	MaxBinarySize int64             // Binary files above this size are reported (default DefaultMaxBinarySize)
+	Exclude       []string          // Files the debug, todo and binary rules skip, in .gitignore syntax
+}
+
+// excludedRules may skip files of Options.Exclude. Conflict markers and
+// secrets are always reported.
+var excludedRules = map[string]bool{RuleDebug: true, RuleTodo: true, RuleBinary: true}
+
+// Level returns the level of rule
+func (o Options) Level(rule string) Level {
+	if level, ok := o.Levels[rule]; ok {
+		return Level(strings.ToLower(level))
+	}
+	return DefaultLevels[rule]
+}
+
+// Validate checks the configured rules and levels
+func (o Options) Validate() error {
+	for rule, level := range o.Levels {
+		if _, ok := DefaultLevels[rule]; !ok {
+			return fmt.Errorf("unknown precheck rule %q", rule)
+		}
+		switch Level(strings.ToLower(level)) {
+		case LevelOff, LevelWarn, LevelBlock:
+		default:
+			return fmt.Errorf("invalid level %q for precheck rule %s (expected off, warn or block)", level, rule)
+		}
+	}
+	if _, err := ignore.New(o.Exclude); err != nil {
+		return fmt.Errorf("invalid precheck exclude: %w", err)
+	}
+	return nil
+}
+
+// AddedLine is a line added by a change
+type AddedLine struct {
+	File string
+	Line int // Line number in the new version of the file
+	Text string
+}
+
+// AddedLines returns the lines a patch adds, with their line numbers
+func AddedLines(patch *git.Patch) []AddedLine {
+	var lines []AddedLine
+	for _, hunk := range patch.Hunks() {
+		matches := hunkStartPattern.FindStringSubmatch(hunk.Header)
+		if matches == nil {
+			continue
+		}
+		number, _ := strconv.Atoi(matches[1])
+
+		for _, line := range hunk.Lines {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				lines = append(lines, AddedLine{File: hunk.File, Line: number, Text: line[1:]})
+				number++
+			case strings.HasPrefix(line, " "), line == "":
+				number++
+			}
+		}
+	}
+	return lines
+}
+
+// BinaryFile is a staged binary file
+type BinaryFile struct {
+	Path string
+	Size int64
+}
+
+// Check runs the rules on the added lines and binary files of a change.
+// Findings are sorted by file and line.
+func Check(lines []AddedLine, binaries []BinaryFile, opts Options) Report {
+	report := Report{Findings: []Finding{}}
+	exclude, err := ignore.New(opts.Exclude)
+	if err != nil {
+		exclude = nil
+	}
+	add := func(rule, file string, line int, message string) {
+		if exclude != nil && excludedRules[rule] && exclude.Match(file) {
+			return
+		}
+		if level := opts.Level(rule); level != LevelOff {
+			report.Findings = append(report.Findings, Finding{
+				Rule: rule, Level: level, File: file, Line: line, Message: message, Source: "rules",
+			})
+		}
+	}
+
+	for _, line := range lines {
+		if marker := conflictMarker(line.Text); marker != "" {
+			add(RuleConflict, line.File, line.Line, fmt.Sprintf("conflict marker %q", marker))
+		}
+		if name := findSecret(line.Text); name != "" {
+			add(RuleSecret, line.File, line.Line, name)
+		}
+		if call := findDebug(line.File, line.Text); call != "" {
+			add(RuleDebug, line.File, line.Line, fmt.Sprintf("debug statement %s", call))
+		}
+		if tag := findTodo(line.Text); tag != "" {
+			add(RuleTodo, line.File, line.Line, fmt.Sprintf("%s added", tag))
+		}
+	}
+
+	maxSize := opts.MaxBinarySize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxBinarySize
+	}
+	for _, binary := range binaries {
+		if binary.Size > maxSize {
+			add(RuleBinary, binary.Path, 0, fmt.Sprintf("binary file of %s (limit %s)", formatSize(binary.Size), formatSize(maxSize)))
+		}
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return report
+}
+
+// formatSize formats a size in bytes for humans
+func formatSize(size int64) string {
+	switch {
+	case size >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+	case size >= 1024:
+		return fmt.Sprintf("%.1f KB", float64(size)/1024)
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
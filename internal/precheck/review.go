@@ -0,0 +1,60 @@
+package precheck
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/xyue92/gitai/internal/ai"
+)
+
+// reviewLocationPattern splits "path/file.go:12: issue" or "file.go: issue"
+var reviewLocationPattern = regexp.MustCompile(`^([^\s:]+)(?::(\d+))?:\s+(.+)$`)
+
+// ReviewFindings turns the issues of a model review into findings. Secrets
+// and debug leftovers use the levels of their rules, bugs and missing tests
+// the level of the review rule. The rules decide what blocks a commit, so
+// model findings are at most warnings unless the rules report the same
+// problem in the same file.
+func ReviewFindings(review ai.Review, rules Report, opts Options) []Finding {
+	var findings []Finding
+	add := func(rule, prefix string, items []string) {
+		level := opts.Level(rule)
+		if level == LevelOff {
+			return
+		}
+		for _, item := range items {
+			finding := Finding{Rule: rule, Level: level, Message: prefix + item, Source: "model"}
+			matches := reviewLocationPattern.FindStringSubmatch(strings.ReplaceAll(item, "`", ""))
+			if matches != nil && strings.ContainsAny(matches[1], "./") {
+				finding.File = matches[1]
+				finding.Line, _ = strconv.Atoi(matches[2])
+				finding.Message = prefix + strings.TrimSpace(matches[3])
+			}
+			if finding.Level == LevelBlock && !rules.confirms(finding) {
+				finding.Level = LevelWarn
+			}
+			findings = append(findings, finding)
+		}
+	}
+
+	add(RuleSecret, "", review.Secrets)
+	add(RuleDebug, "", review.DebugLeftovers)
+	add(RuleReview, "possible bug: ", review.Bugs)
+	add(RuleReview, "missing test: ", review.MissingTests)
+	return findings
+}
+
+// confirms reports whether a rule found a problem of the same rule in the
+// file of a model finding
+func (r Report) confirms(finding Finding) bool {
+	if finding.File == "" {
+		return false
+	}
+	for _, f := range r.Findings {
+		if f.Source == "rules" && f.Rule == finding.Rule && f.File == finding.File {
+			return true
+		}
+	}
+	return false
+}
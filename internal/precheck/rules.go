@@ -0,0 +1,84 @@
+package precheck
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/xyue92/gitai/internal/redact"
+)
+
+// debugPatterns detect debug statements by file extension
+var debugPatterns = map[string]*regexp.Regexp{
+	".go":   regexp.MustCompile(`\b(fmt\.Print(ln|f)?\(|println\(|spew\.Dump\(|litter\.Dump\()`),
+	".js":   regexp.MustCompile(`\b(console\.(log|debug|trace)\(|debugger\b)`),
+	".py":   regexp.MustCompile(`(^\s*print\(|\bpdb\.set_trace\(|\bbreakpoint\(\)|\bipdb\.set_trace\()`),
+	".rb":   regexp.MustCompile(`\b(binding\.pry|byebug|debugger|puts\s+.*inspect)\b`),
+	".php":  regexp.MustCompile(`\b(var_dump\(|print_r\(|dd\(|dump\()`),
+	".java": regexp.MustCompile(`\b(System\.(out|err)\.print(ln)?\(|\.printStackTrace\(\))`),
+	".rs":   regexp.MustCompile(`\b(dbg!\(|println!\()`),
+}
+
+// debugExtensions maps related extensions onto the patterns above
+var debugExtensions = map[string]string{
+	".jsx": ".js", ".ts": ".js", ".tsx": ".js", ".mjs": ".js", ".cjs": ".js", ".vue": ".js", ".svelte": ".js",
+	".kt": ".java", ".scala": ".java",
+}
+
+// todoPattern matches TODO-style comment tags
+var todoPattern = regexp.MustCompile(`\b(TODO|FIXME|XXX|HACK)\b`)
+
+// conflictPattern matches the markers git writes into conflicted files
+var conflictPattern = regexp.MustCompile(`^(<{7}|>{7}|\|{7})(\s|$)`)
+
+// findSecret returns the kind of secret in text, or "". It uses the same
+// detectors that redact prompts, so what blocks a commit is also never sent to
+// the model.
+func findSecret(text string) string {
+	if secrets := redact.FindSecrets(text); len(secrets) > 0 {
+		return secrets[0].Label
+	}
+	return ""
+}
+
+// findDebug returns the debug statement in a line of file, or "". Test files
+// print on purpose and are not checked.
+func findDebug(file, text string) string {
+	if isTestFile(file) {
+		return ""
+	}
+
+	ext := strings.ToLower(filepath.Ext(file))
+	if alias, ok := debugExtensions[ext]; ok {
+		ext = alias
+	}
+	pattern, ok := debugPatterns[ext]
+	if !ok {
+		return ""
+	}
+
+	return strings.TrimSpace(pattern.FindString(text))
+}
+
+// findTodo returns the TODO-style tag in text, or ""
+func findTodo(text string) string {
+	return todoPattern.FindString(text)
+}
+
+// conflictMarker returns the conflict marker that starts text, or ""
+func conflictMarker(text string) string {
+	if conflictPattern.MatchString(text) {
+		return text[:7]
+	}
+	return ""
+}
+
+// isTestFile reports whether path looks like a test file
+func isTestFile(path string) bool {
+	base := strings.ToLower(filepath.Base(path))
+	return strings.HasSuffix(base, "_test.go") ||
+		strings.HasPrefix(base, "test_") ||
+		strings.Contains(base, ".test.") ||
+		strings.Contains(base, ".spec.") ||
+		strings.Contains(filepath.ToSlash(path), "/testdata/")
+}
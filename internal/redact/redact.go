@@ -0,0 +1,259 @@
+package redact
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// detector finds one kind of sensitive value. Group selects the part of the
+// match that is sensitive (0 for the whole match), e.g. only the password of
+// a URL with credentials.
+type detector struct {
+	Kind    string // Placeholder name, e.g. "aws_key"
+	Label   string // Human-readable name, e.g. "AWS access key"
+	Pattern *regexp.Regexp
+	Group   int
+	Secret  bool // Credentials rather than personal data or guesses
+}
+
+// detectors are tried in order; earlier detectors win overlapping matches
+var detectors = []detector{
+	{"private_key", "private key", regexp.MustCompile(`-----BEGIN ([A-Z]+ )*PRIVATE KEY( BLOCK)?-----[\s\S]*?-----END ([A-Z]+ )*PRIVATE KEY( BLOCK)?-----`), 0, true},
+	{"private_key", "private key", regexp.MustCompile(`-----BEGIN ([A-Z]+ )*PRIVATE KEY( BLOCK)?-----`), 0, true},
+	{"aws_key", "AWS access key", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`), 0, true},
+	{"github_token", "GitHub token", regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{60,})\b`), 0, true},
+	{"gitlab_token", "GitLab token", regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}\b`), 0, true},
+	{"slack_token", "Slack token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}\b`), 0, true},
+	{"stripe_key", "Stripe key", regexp.MustCompile(`\b[sr]k_live_[A-Za-z0-9]{20,}\b`), 0, true},
+	{"google_api_key", "Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`), 0, true},
+	{"jwt", "JSON web token", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`), 0, true},
+	{"url_password", "credentials in URL", regexp.MustCompile(`[a-z][a-z0-9+.-]*://[^/\s:@]+:([^/\s:@]{3,})@`), 1, true},
+	{"password", "hard-coded password", regexp.MustCompile(`(?i)\b(?:password|passwd|pwd|secret|api_?key|access_?token|auth_?token|client_?secret)\b["']?\s*[:=]\s*["']([^"'\s]{8,})["']`), 1, true},
+	{"password", "password in config", regexp.MustCompile(`(?im)^[+\- ]?\s*(?:export\s+)?[\w.-]*(?:password|passwd|secret|token|api_?key|private_?key|credentials?)\s*(?::\s|=)\s*["']?([^\s"'(),;]{4,})["']?\s*$`), 1, true},
+	{"email", "email address", regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}\b`), 0, false},
+}
+
+// tokenPattern finds candidates for the high-entropy check
+var tokenPattern = regexp.MustCompile(`[A-Za-z0-9_\-+/=]{24,}`)
+
+// referencePattern matches config values that point elsewhere instead of
+// holding a secret: $VAR, ${VAR}, {{ .Value }}, <placeholder>, dotted names
+var referencePattern = regexp.MustCompile(`^(\$|\{\{|<|%\(|\*+$)|^[A-Za-z_]\w*(\.[A-Za-z_]\w*)+$`)
+
+// typeNames are values of password-like keys in type declarations, e.g.
+// "password: string" in a TypeScript interface
+var typeNames = map[string]bool{
+	"string": true, "str": true, "number": true, "int": true, "boolean": true, "bool": true,
+	"null": true, "nil": true, "none": true, "true": true, "false": true, "undefined": true,
+	"optional": true, "required": true,
+}
+
+// safeEmailDomains are documentation and no-reply domains that identify nobody
+var safeEmailDomains = []string{"example.com", "example.org", "example.net", "users.noreply.github.com"}
+
+// Match is a sensitive value found in a text
+type Match struct {
+	Kind   string
+	Label  string
+	Start  int // Byte offsets of the sensitive value
+	End    int
+	Secret bool
+}
+
+// Find returns the sensitive values in text, in order and without overlaps
+func Find(text string) []Match {
+	var matches []Match
+	taken := func(start, end int) bool {
+		for _, m := range matches {
+			if start < m.End && m.Start < end {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, d := range detectors {
+		for _, loc := range d.Pattern.FindAllStringSubmatchIndex(text, -1) {
+			start, end := loc[2*d.Group], loc[2*d.Group+1]
+			if start < 0 || taken(start, end) || !d.accept(text[start:end]) {
+				continue
+			}
+			matches = append(matches, Match{Kind: d.Kind, Label: d.Label, Start: start, End: end, Secret: d.Secret})
+		}
+	}
+
+	for _, loc := range tokenPattern.FindAllStringIndex(text, -1) {
+		if !taken(loc[0], loc[1]) && isHighEntropy(text[loc[0]:loc[1]]) {
+			matches = append(matches, Match{Kind: "high_entropy", Label: "high-entropy string", Start: loc[0], End: loc[1]})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Start < matches[j].Start })
+	return matches
+}
+
+// FindSecrets returns the credentials in text: no personal data and no
+// high-entropy guesses
+func FindSecrets(text string) []Match {
+	var secrets []Match
+	for _, m := range Find(text) {
+		if m.Secret {
+			secrets = append(secrets, m)
+		}
+	}
+	return secrets
+}
+
+// accept filters out matches that are not sensitive after all
+func (d detector) accept(value string) bool {
+	switch d.Kind {
+	case "password":
+		return !referencePattern.MatchString(value) && !typeNames[strings.ToLower(value)]
+	case "email":
+		domain := strings.ToLower(value[strings.LastIndex(value, "@")+1:])
+		for _, safe := range safeEmailDomains {
+			if domain == safe || strings.HasSuffix(domain, "."+safe) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isHighEntropy reports whether token looks like a random key: mixed letters
+// and digits with high Shannon entropy. Hex strings (hashes) are left alone
+// because commit and blob hashes are common in diffs and not secret.
+func isHighEntropy(token string) bool {
+	var upper, lower, digit, hex bool
+	hex = true
+	for _, c := range token {
+		switch {
+		case c >= 'A' && c <= 'Z':
+			upper = true
+		case c >= 'a' && c <= 'z':
+			lower = true
+		case c >= '0' && c <= '9':
+			digit = true
+		}
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			hex = false
+		}
+	}
+	if hex || !digit || !(upper || lower) || strings.Count(token, "/") > 1 {
+		return false
+	}
+
+	// Random strings use nearly as many distinct symbols as they are long
+	threshold := 0.85 * math.Log2(math.Min(float64(len(token)), 64))
+	return entropy(token) >= threshold
+}
+
+// entropy returns the Shannon entropy of s in bits per character
+func entropy(s string) float64 {
+	counts := make(map[rune]int)
+	for _, c := range s {
+		counts[c]++
+	}
+
+	var result float64
+	n := float64(len(s))
+	for _, count := range counts {
+		p := float64(count) / n
+		result -= p * math.Log2(p)
+	}
+	return result
+}
+
+// Redaction summarizes the values of one kind masked in a text. It never
+// holds the values themselves.
+type Redaction struct {
+	Kind         string
+	Label        string
+	Placeholders []string // Distinct placeholders used, in order
+	Count        int      // Occurrences replaced
+}
+
+// Redactor replaces sensitive values with placeholders such as
+// "[REDACTED_EMAIL_1]". The same value always gets the same placeholder, so
+// the model can still tell values apart and repeated prompts stay identical.
+type Redactor struct {
+	allow        []*regexp.Regexp
+	placeholders map[string]string // Value → placeholder
+	counters     map[string]int    // Kind → placeholders handed out
+}
+
+// New creates a redactor. Values matching one of the allow patterns are kept.
+func New(allow []string) (*Redactor, error) {
+	r := &Redactor{placeholders: make(map[string]string), counters: make(map[string]int)}
+	for _, pattern := range allow {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction allow pattern %q: %w", pattern, err)
+		}
+		r.allow = append(r.allow, re)
+	}
+	return r, nil
+}
+
+// Redact masks the sensitive values in text and reports what was masked
+func (r *Redactor) Redact(text string) (string, []Redaction) {
+	var b strings.Builder
+	var redactions []Redaction
+	index := make(map[string]int) // Kind → position in redactions
+
+	last := 0
+	for _, m := range Find(text) {
+		value := text[m.Start:m.End]
+		if r.allowed(value) {
+			continue
+		}
+
+		placeholder, seen := r.placeholders[value]
+		if !seen {
+			r.counters[m.Kind]++
+			placeholder = fmt.Sprintf("[REDACTED_%s_%d]", strings.ToUpper(m.Kind), r.counters[m.Kind])
+			r.placeholders[value] = placeholder
+		}
+
+		i, ok := index[m.Kind]
+		if !ok {
+			i = len(redactions)
+			index[m.Kind] = i
+			redactions = append(redactions, Redaction{Kind: m.Kind, Label: m.Label})
+		}
+		redactions[i].Count++
+		if !containsString(redactions[i].Placeholders, placeholder) {
+			redactions[i].Placeholders = append(redactions[i].Placeholders, placeholder)
+		}
+
+		b.WriteString(text[last:m.Start])
+		b.WriteString(placeholder)
+		last = m.End
+	}
+	b.WriteString(text[last:])
+
+	return b.String(), redactions
+}
+
+// allowed reports whether value matches an allow pattern
+func (r *Redactor) allowed(value string) bool {
+	for _, re := range r.allow {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,211 @@
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// versionPattern matches a Semantic Versioning 2.0.0 version with an optional "v" prefix
+var versionPattern = regexp.MustCompile(`^(v?)(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[A-Za-z-][0-9A-Za-z-]*)(?:\.(?:0|[1-9]\d*|\d*[A-Za-z-][0-9A-Za-z-]*))*))?` +
+	`(?:\+([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?$`)
+
+// Level is the kind of version bump
+type Level int
+
+const (
+	None Level = iota
+	Patch
+	Minor
+	Major
+)
+
+// String returns the level name
+func (l Level) String() string {
+	switch l {
+	case Patch:
+		return "patch"
+	case Minor:
+		return "minor"
+	case Major:
+		return "major"
+	default:
+		return "none"
+	}
+}
+
+// ParseLevel parses "major", "minor" or "patch"
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "major":
+		return Major, nil
+	case "minor":
+		return Minor, nil
+	case "patch":
+		return Patch, nil
+	}
+	return None, fmt.Errorf("invalid bump level %q (expected major, minor or patch)", s)
+}
+
+// Version is a Semantic Versioning 2.0.0 version
+type Version struct {
+	Prefix     string // "v" or ""
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease []string // Dot-separated pre-release identifiers, e.g. ["rc", "1"]
+	Build      []string // Dot-separated build metadata identifiers
+}
+
+// Parse parses a version such as "v1.2.3-rc.1+build.5"
+func Parse(s string) (Version, error) {
+	matches := versionPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if matches == nil {
+		return Version{}, fmt.Errorf("invalid semantic version: %q", s)
+	}
+
+	v := Version{Prefix: matches[1]}
+	v.Major, _ = strconv.Atoi(matches[2])
+	v.Minor, _ = strconv.Atoi(matches[3])
+	v.Patch, _ = strconv.Atoi(matches[4])
+	if matches[5] != "" {
+		v.Prerelease = strings.Split(matches[5], ".")
+	}
+	if matches[6] != "" {
+		v.Build = strings.Split(matches[6], ".")
+	}
+
+	return v, nil
+}
+
+// String formats the version including prefix, pre-release and build metadata
+func (v Version) String() string {
+	s := fmt.Sprintf("%s%d.%d.%d", v.Prefix, v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	if len(v.Build) > 0 {
+		s += "+" + strings.Join(v.Build, ".")
+	}
+	return s
+}
+
+// IsPrerelease reports whether the version has pre-release identifiers
+func (v Version) IsPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+// Release returns the version without pre-release and build metadata
+func (v Version) Release() Version {
+	return Version{Prefix: v.Prefix, Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+}
+
+// Bump returns the next release version for the given level.
+// Pre-release and build metadata are dropped.
+func (v Version) Bump(level Level) Version {
+	next := v.Release()
+	switch level {
+	case Major:
+		next.Major++
+		next.Minor, next.Patch = 0, 0
+	case Minor:
+		next.Minor++
+		next.Patch = 0
+	case Patch:
+		next.Patch++
+	}
+	return next
+}
+
+// WithPrerelease returns the pre-release of v with the given identifier.
+// If current is already a pre-release of the same version and identifier, its
+// counter is incremented (1.2.0-rc.1 → 1.2.0-rc.2); otherwise it starts at 1.
+func (v Version) WithPrerelease(id string, current Version) Version {
+	next := v.Release()
+	if current.IsPrerelease() && Compare(current.Release(), next) == 0 && current.Prerelease[0] == id {
+		counter := 0
+		if len(current.Prerelease) > 1 {
+			counter, _ = strconv.Atoi(current.Prerelease[len(current.Prerelease)-1])
+		}
+		next.Prerelease = []string{id, strconv.Itoa(counter + 1)}
+		return next
+	}
+
+	next.Prerelease = []string{id, "1"}
+	return next
+}
+
+// Compare returns -1, 0 or 1 following semver precedence. Build metadata and
+// the prefix are ignored.
+func Compare(a, b Version) int {
+	for _, pair := range [][2]int{{a.Major, b.Major}, {a.Minor, b.Minor}, {a.Patch, b.Patch}} {
+		if pair[0] != pair[1] {
+			return compareInts(pair[0], pair[1])
+		}
+	}
+
+	// A version without pre-release has higher precedence
+	switch {
+	case len(a.Prerelease) == 0 && len(b.Prerelease) == 0:
+		return 0
+	case len(a.Prerelease) == 0:
+		return 1
+	case len(b.Prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(a.Prerelease) && i < len(b.Prerelease); i++ {
+		if c := compareIdentifiers(a.Prerelease[i], b.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(a.Prerelease), len(b.Prerelease))
+}
+
+// compareIdentifiers compares pre-release identifiers: numeric ones
+// numerically and lower than alphanumeric ones, which compare as strings
+func compareIdentifiers(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return compareInts(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// compareInts returns -1, 0 or 1
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Latest returns the highest version among tags, ignoring tags that are not
+// semantic versions. Pre-releases are skipped unless includePrerelease is set.
+// The tag name is returned as well, since it may differ from String() in
+// build metadata.
+func Latest(tags []string, includePrerelease bool) (Version, string, bool) {
+	var latest Version
+	latestTag := ""
+	for _, tag := range tags {
+		v, err := Parse(tag)
+		if err != nil || (v.IsPrerelease() && !includePrerelease) {
+			continue
+		}
+		if latestTag == "" || Compare(v, latest) > 0 {
+			latest, latestTag = v, tag
+		}
+	}
+	return latest, latestTag, latestTag != ""
+}
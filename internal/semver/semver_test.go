@@ -0,0 +1,129 @@
+package semver
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"1.2.3", "1.2.3", false},
+		{"v0.10.0", "v0.10.0", false},
+		{"v1.0.0-rc.1+build.5", "v1.0.0-rc.1+build.5", false},
+		{"1.0.0-alpha-1", "1.0.0-alpha-1", false},
+		{"1.2", "", true},
+		{"01.2.3", "", true},
+		{"1.2.3-01", "", true},
+		{"release-1", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			v, err := Parse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if err == nil && v.String() != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.input, v, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// Ordered by precedence, from the semver 2.0.0 spec
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0",
+	}
+
+	versions := make([]Version, len(ordered))
+	for i, s := range ordered {
+		versions[i], _ = Parse(s)
+	}
+
+	shuffled := []Version{versions[7], versions[0], versions[10], versions[5], versions[2], versions[9], versions[4], versions[1], versions[8], versions[6], versions[3]}
+	sort.Slice(shuffled, func(i, j int) bool { return Compare(shuffled[i], shuffled[j]) < 0 })
+	for i, v := range shuffled {
+		if v.String() != ordered[i] {
+			t.Errorf("position %d = %s, want %s", i, v, ordered[i])
+		}
+	}
+
+	a, _ := Parse("v1.0.0+build.1")
+	b, _ := Parse("1.0.0+build.2")
+	if Compare(a, b) != 0 {
+		t.Errorf("build metadata and prefix should not affect precedence")
+	}
+}
+
+func TestBump(t *testing.T) {
+	tests := []struct {
+		version string
+		level   Level
+		want    string
+	}{
+		{"v1.2.3", Patch, "v1.2.4"},
+		{"v1.2.3", Minor, "v1.3.0"},
+		{"v1.2.3", Major, "v2.0.0"},
+		{"1.2.3-rc.1+b", Patch, "1.2.4"},
+		{"0.0.0", Minor, "0.1.0"},
+	}
+
+	for _, tt := range tests {
+		v, _ := Parse(tt.version)
+		if got := v.Bump(tt.level).String(); got != tt.want {
+			t.Errorf("%s.Bump(%s) = %s, want %s", tt.version, tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestWithPrerelease(t *testing.T) {
+	tests := []struct {
+		next    string
+		id      string
+		current string
+		want    string
+	}{
+		{"1.3.0", "rc", "1.2.0", "1.3.0-rc.1"},
+		{"1.3.0", "rc", "1.3.0-rc.1", "1.3.0-rc.2"},
+		{"1.3.0", "rc", "1.3.0-beta.4", "1.3.0-rc.1"},
+		{"1.3.0", "beta", "1.3.0-beta", "1.3.0-beta.1"},
+		{"2.0.0", "rc", "1.3.0-rc.2", "2.0.0-rc.1"},
+	}
+
+	for _, tt := range tests {
+		next, _ := Parse(tt.next)
+		current, _ := Parse(tt.current)
+		if got := next.WithPrerelease(tt.id, current).String(); got != tt.want {
+			t.Errorf("WithPrerelease(%s, %s) from %s = %s, want %s", tt.next, tt.id, tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestLatest(t *testing.T) {
+	tags := []string{"v1.2.0", "v1.10.0", "v1.11.0-rc.1", "release-2", "v1.9.3", "v0.9.0"}
+
+	tests := []struct {
+		includePrerelease bool
+		want              string
+	}{
+		{false, "v1.10.0"},
+		{true, "v1.11.0-rc.1"},
+	}
+
+	for _, tt := range tests {
+		_, tag, ok := Latest(tags, tt.includePrerelease)
+		if !ok || tag != tt.want {
+			t.Errorf("Latest(prerelease=%v) = %q, want %q", tt.includePrerelease, tag, tt.want)
+		}
+	}
+
+	if _, _, ok := Latest([]string{"release-2", "latest"}, true); ok {
+		t.Error("Latest() found a version among non-semver tags")
+	}
+}
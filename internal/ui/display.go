@@ -6,6 +6,7 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/xyue92/gitai/internal/git"
+	"github.com/xyue92/gitai/internal/precheck"
 )
 
 // Display provides formatted output functions
@@ -188,3 +189,184 @@ func (d *Display) ShowCommitSuccess(message string, files []string) {
 
 	d.ShowInfo("View commit: git show HEAD")
 }
+
+// ShowMessageComparison displays the old and new commit messages side by side
+func (d *Display) ShowMessageComparison(oldMessage, newMessage string) {
+	const columnWidth = 50
+
+	left := wrapLines(oldMessage, columnWidth)
+	right := wrapLines(newMessage, columnWidth)
+
+	rows := len(left)
+	if len(right) > rows {
+		rows = len(right)
+	}
+
+	bold := color.New(color.Bold)
+	bold.Printf("%-*s │ %s\n", columnWidth, "Old message", "New message")
+	fmt.Println(strings.Repeat("─", columnWidth) + "─┼─" + strings.Repeat("─", columnWidth))
+
+	red := color.New(color.FgRed)
+	green := color.New(color.FgGreen)
+	for i := 0; i < rows; i++ {
+		var l, r string
+		if i < len(left) {
+			l = left[i]
+		}
+		if i < len(right) {
+			r = right[i]
+		}
+
+		padding := strings.Repeat(" ", columnWidth-len([]rune(l)))
+		if d.NoColor {
+			fmt.Printf("%s%s │ %s\n", l, padding, r)
+		} else {
+			red.Print(l)
+			fmt.Printf("%s │ ", padding)
+			green.Println(r)
+		}
+	}
+	fmt.Println()
+}
+
+// RewordEntry is one row of a batch reword plan
+type RewordEntry struct {
+	Hash       string
+	OldSubject string
+	NewSubject string
+}
+
+// ShowRewordPlan displays a table of old → new subjects for a batch reword
+func (d *Display) ShowRewordPlan(entries []RewordEntry) {
+	const subjectWidth = 40
+
+	bold := color.New(color.Bold)
+	bold.Printf("%-3s %-9s %-*s   %s\n", "#", "Commit", subjectWidth, "Old subject", "New subject")
+	fmt.Println(strings.Repeat("─", 16+2*subjectWidth))
+
+	red := color.New(color.FgRed)
+	green := color.New(color.FgGreen)
+	for i, entry := range entries {
+		old := truncate(entry.OldSubject, subjectWidth)
+		padding := strings.Repeat(" ", subjectWidth-len([]rune(old)))
+		fmt.Printf("%-3d %-9s ", i+1, entry.Hash)
+		if d.NoColor {
+			fmt.Printf("%s%s → %s\n", old, padding, entry.NewSubject)
+		} else {
+			red.Print(old)
+			fmt.Printf("%s → ", padding)
+			green.Println(entry.NewSubject)
+		}
+	}
+	fmt.Println()
+}
+
+// ShowSplitGroups displays the proposed commit groups of a split
+func (d *Display) ShowSplitGroups(groups []git.SplitGroup) {
+	bold := color.New(color.Bold)
+	cyan := color.New(color.FgCyan)
+
+	for i, group := range groups {
+		commitType := group.Type
+		if commitType == "" {
+			commitType = "?"
+		}
+		bold.Printf("%d. ", i+1)
+		cyan.Print(commitType)
+		fmt.Printf(" - %s (%d file(s), %d change(s))\n", group.Label, len(group.Files()), len(group.Hunks))
+		for _, hunk := range group.Hunks {
+			fmt.Printf("     - %s\n", hunk.Describe())
+		}
+	}
+	fmt.Println()
+}
+
+// ReportSection is a titled list of findings in a report
+type ReportSection struct {
+	Title string
+	Text  string // Paragraph shown instead of a list
+	Items []string
+	Empty string // Shown when there are no items
+	Alert bool   // Highlight the items as problems
+}
+
+// ShowReport displays a report such as a code review, one section at a time
+func (d *Display) ShowReport(sections []ReportSection) {
+	bold := color.New(color.Bold)
+	green := color.New(color.FgGreen)
+	red := color.New(color.FgRed)
+
+	for _, section := range sections {
+		bold.Println(section.Title)
+		if section.Text != "" {
+			for _, line := range strings.Split(section.Text, "\n") {
+				fmt.Printf("  %s\n", line)
+			}
+			fmt.Println()
+			continue
+		}
+		if len(section.Items) == 0 {
+			if d.NoColor {
+				fmt.Printf("  %s\n", section.Empty)
+			} else {
+				green.Printf("  %s\n", section.Empty)
+			}
+		}
+		for _, item := range section.Items {
+			if section.Alert && !d.NoColor {
+				red.Print("  • ")
+				fmt.Println(item)
+			} else {
+				fmt.Printf("  • %s\n", item)
+			}
+		}
+		fmt.Println()
+	}
+}
+
+// ShowFindings displays the findings of a pre-commit check, one per line
+func (d *Display) ShowFindings(findings []precheck.Finding) {
+	red := color.New(color.FgRed, color.Bold)
+	yellow := color.New(color.FgYellow)
+
+	for _, finding := range findings {
+		marker, paint := "⚠", yellow
+		if finding.Level == precheck.LevelBlock {
+			marker, paint = "✗", red
+		}
+		location := finding.Location()
+		if location == "" {
+			location = "-"
+		}
+		if d.NoColor {
+			fmt.Printf("%s %-8s %s  %s\n", marker, finding.Rule, location, finding.Message)
+		} else {
+			paint.Printf("%s %-8s ", marker, finding.Rule)
+			fmt.Printf("%s  %s\n", location, finding.Message)
+		}
+	}
+	fmt.Println()
+}
+
+// truncate shortens text to at most width runes, marking the cut with "…"
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// wrapLines splits text into lines no wider than width runes
+func wrapLines(text string, width int) []string {
+	var result []string
+	for _, line := range strings.Split(text, "\n") {
+		runes := []rune(line)
+		for len(runes) > width {
+			result = append(result, string(runes[:width]))
+			runes = runes[width:]
+		}
+		result = append(result, string(runes))
+	}
+	return result
+}
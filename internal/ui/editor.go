@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/xyue92/gitai/internal/git"
+)
+
+// editorFileName matches git's own file name so editors pick up commit syntax
+const editorFileName = "COMMIT_EDITMSG"
+
+// EditInEditor opens the user's editor on a temporary file pre-filled with the
+// message, then reads the result back with commit.cleanup semantics applied.
+// diffStats is shown as commented context below the message.
+func EditInEditor(message, diffStats string) (string, error) {
+	dir, err := os.MkdirTemp("", "gitai-edit-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	mode := git.GetCleanupMode()
+	commentChar := git.GetCommentChar()
+
+	path := filepath.Join(dir, editorFileName)
+	content := buildEditorTemplate(message, diffStats, mode, commentChar)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	if err := runEditor(git.GetEditor(), path); err != nil {
+		return "", err
+	}
+
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read edited message: %w", err)
+	}
+
+	return git.CleanupMessage(string(edited), mode, commentChar), nil
+}
+
+// buildEditorTemplate renders the message followed by git-style commented help.
+// Verbatim and whitespace modes keep comment lines, so only the message is
+// written.
+func buildEditorTemplate(message, diffStats, mode, commentChar string) string {
+	var sb strings.Builder
+
+	sb.WriteString(strings.TrimRight(message, "\n"))
+	if mode == git.CleanupVerbatim || mode == git.CleanupWhitespace {
+		sb.WriteString("\n")
+		return sb.String()
+	}
+	sb.WriteString("\n\n")
+
+	// Comments are only stripped in strip mode; otherwise cut with scissors
+	stripComments := mode != git.CleanupScissors
+	if !stripComments {
+		sb.WriteString(git.ScissorsLine(commentChar) + "\n")
+		sb.WriteString(commentChar + " Do not modify or remove the line above.\n")
+		sb.WriteString(commentChar + " Everything below it will be ignored.\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("%s Please edit the commit message for your changes. Lines starting\n", commentChar))
+		sb.WriteString(fmt.Sprintf("%s with '%s' will be ignored, and an empty message aborts the commit.\n", commentChar, commentChar))
+	}
+
+	if strings.TrimSpace(diffStats) != "" {
+		sb.WriteString(commentChar + "\n")
+		sb.WriteString(commentChar + " Changes to be committed:\n")
+		for _, line := range strings.Split(strings.TrimRight(diffStats, "\n"), "\n") {
+			sb.WriteString(commentChar + "  " + strings.TrimSpace(line) + "\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// runEditor launches the editor attached to the terminal and waits for it.
+// Like git, the editor string is run through the shell so it may contain arguments.
+func runEditor(editor, path string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		parts := strings.Fields(editor)
+		if len(parts) == 0 {
+			return fmt.Errorf("no editor configured")
+		}
+		cmd = exec.Command(parts[0], append(parts[1:], path)...)
+	} else {
+		cmd = exec.Command("sh", "-c", editor+` "$@"`, editor, path)
+	}
+
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor '%s' failed: %w", editor, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/xyue92/gitai/internal/git"
+)
+
+func TestEditorTemplateRoundTrip(t *testing.T) {
+	message := "feat(api): add login\n\nSupports tokens."
+	stats := " api/login.go | 10 +++++\n 1 file changed"
+
+	// An unedited template must give back the message in every mode
+	for _, mode := range []string{git.CleanupDefault, git.CleanupStrip, git.CleanupWhitespace, git.CleanupScissors, git.CleanupVerbatim} {
+		t.Run(mode, func(t *testing.T) {
+			content := buildEditorTemplate(message, stats, mode, "#")
+			got := git.CleanupMessage(content, mode, "#")
+			if mode == git.CleanupVerbatim {
+				got = got[:len(got)-1] // The newline ending the file
+			}
+			if got != message {
+				t.Errorf("CleanupMessage(buildEditorTemplate()) = %q, want %q", got, message)
+			}
+		})
+	}
+}
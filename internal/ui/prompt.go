@@ -6,11 +6,13 @@ import (
 
 	"github.com/manifoldco/promptui"
 	"github.com/xyue92/gitai/internal/config"
+	"github.com/xyue92/gitai/internal/git"
 )
 
 // CommitSelector provides interactive selection for commit parameters
 type CommitSelector struct {
-	Config *config.Config
+	Config    *config.Config
+	DiffStats string // Diff stats shown as context when editing (default: staged stats)
 }
 
 // NewCommitSelector creates a new CommitSelector
@@ -123,29 +125,107 @@ func (cs *CommitSelector) ConfirmAction(message string) (Action, error) {
 	return actions[idx].Action, nil
 }
 
-// EditMessage allows user to manually edit the message
+// EditMessage opens the message in the user's editor for full multi-line editing
 func (cs *CommitSelector) EditMessage(original string) (string, error) {
-	validate := func(input string) error {
-		if len(input) == 0 {
-			return fmt.Errorf("commit message cannot be empty")
-		}
-		return nil
+	stats := cs.DiffStats
+	if stats == "" {
+		stats, _ = git.GetDiffStats()
 	}
 
-	prompt := promptui.Prompt{
-		Label:    "Edit commit message",
-		Default:  original,
-		Validate: validate,
-	}
-
-	result, err := prompt.Run()
+	result, err := EditInEditor(original, stats)
 	if err != nil {
 		return "", err
 	}
 
+	if strings.TrimSpace(result) == "" {
+		return "", fmt.Errorf("commit message cannot be empty")
+	}
+
 	return result, nil
 }
 
+// ConfirmBatchAction asks user what to do with a batch of generated messages
+func (cs *CommitSelector) ConfirmBatchAction() (Action, error) {
+	actions := []ActionItem{
+		{Name: "use", Display: "✅ Apply all messages", Action: ActionUse},
+		{Name: "edit", Display: "✏️  Edit a message", Action: ActionEdit},
+		{Name: "regenerate", Display: "🔄 Regenerate a message", Action: ActionRegenerate},
+		{Name: "drop", Display: "➖ Keep a commit's original message", Action: ActionDrop},
+		{Name: "cancel", Display: "❌ Cancel", Action: ActionCancel},
+	}
+
+	templates := &promptui.SelectTemplates{
+		Label:    "{{ . }}",
+		Active:   "▸ {{ .Display | cyan }}",
+		Inactive: "  {{ .Display }}",
+		Selected: "{{ .Display | green }}",
+	}
+
+	prompt := promptui.Select{
+		Label:     "What do you want to do?",
+		Items:     actions,
+		Templates: templates,
+		Size:      5,
+	}
+
+	idx, _, err := prompt.Run()
+	if err != nil {
+		return ActionCancel, err
+	}
+
+	return actions[idx].Action, nil
+}
+
+// ConfirmSplitAction asks user how to adjust the proposed commit groups
+func (cs *CommitSelector) ConfirmSplitAction() (Action, error) {
+	actions := []ActionItem{
+		{Name: "use", Display: "✅ Commit these groups", Action: ActionUse},
+		{Name: "move", Display: "↔️  Move a change to another group", Action: ActionMove},
+		{Name: "merge", Display: "🔗 Merge two groups", Action: ActionMerge},
+		{Name: "retype", Display: "🏷️  Change a group's type", Action: ActionRetype},
+		{Name: "regroup", Display: "🔀 Regroup by directory and symbol", Action: ActionRegroup},
+		{Name: "together", Display: "📦 Commit everything together", Action: ActionCommitTogether},
+		{Name: "cancel", Display: "❌ Cancel", Action: ActionCancel},
+	}
+
+	templates := &promptui.SelectTemplates{
+		Label:    "{{ . }}",
+		Active:   "▸ {{ .Display | cyan }}",
+		Inactive: "  {{ .Display }}",
+		Selected: "{{ .Display | green }}",
+	}
+
+	prompt := promptui.Select{
+		Label:     "Split into separate commits?",
+		Items:     actions,
+		Templates: templates,
+		Size:      7,
+	}
+
+	idx, _, err := prompt.Run()
+	if err != nil {
+		return ActionCancel, err
+	}
+
+	return actions[idx].Action, nil
+}
+
+// SelectItem prompts user to pick one of items and returns its index
+func (cs *CommitSelector) SelectItem(label string, items []string) (int, error) {
+	prompt := promptui.Select{
+		Label: label,
+		Items: items,
+		Size:  10,
+	}
+
+	idx, _, err := prompt.Run()
+	if err != nil {
+		return -1, err
+	}
+
+	return idx, nil
+}
+
 // ConfirmActionAfterEdit asks user what to do after editing the message
 func (cs *CommitSelector) ConfirmActionAfterEdit(message string) (Action, error) {
 	actions := []ActionItem{
@@ -192,6 +272,28 @@ func (cs *CommitSelector) Confirm(message string) (bool, error) {
 	return result == "Yes", nil
 }
 
+// PromptText asks for a line of text, offering def as the default.
+// validate may be nil.
+func (cs *CommitSelector) PromptText(label, def string, validate func(string) error) (string, error) {
+	prompt := promptui.Prompt{
+		Label:     label,
+		Default:   def,
+		AllowEdit: true,
+	}
+	if validate != nil {
+		prompt.Validate = func(input string) error {
+			return validate(strings.TrimSpace(input))
+		}
+	}
+
+	result, err := prompt.Run()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(result), nil
+}
+
 // PromptTicket asks user to input ticket/issue number
 func (cs *CommitSelector) PromptTicket(prefix string) (string, error) {
 	label := "Enter ticket/issue number"
@@ -227,6 +329,12 @@ const (
 	ActionEdit
 	ActionCancel
 	ActionRegenerateFromEdit
+	ActionDrop
+	ActionMove
+	ActionMerge
+	ActionRetype
+	ActionRegroup
+	ActionCommitTogether
 )
 
 // ActionItem represents a selectable action